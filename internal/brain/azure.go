@@ -0,0 +1,152 @@
+package brain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// azureAPIVersion is the Azure OpenAI API version used when none is set.
+const azureAPIVersion = "2024-10-21"
+
+type azureToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"` // JSON, as a string
+	} `json:"function"`
+}
+
+type azureMessage struct {
+	Role       string          `json:"role"`
+	Content    string          `json:"content"`
+	ToolCalls  []azureToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string          `json:"tool_call_id,omitempty"`
+}
+
+// azureProvider implements Provider against an Azure OpenAI deployment's
+// chat completions API.
+type azureProvider struct {
+	client    *http.Client
+	url       string
+	apiKey    string
+	maxTokens int64
+	tools     []ollamaTool // same OpenAI-style function definitions
+}
+
+func newAzureProvider(endpoint, apiKey, deployment, apiVersion string, maxTokens int64, tools []Tool) *azureProvider {
+	if apiVersion == "" {
+		apiVersion = azureAPIVersion
+	}
+	a := &azureProvider{
+		client: &http.Client{},
+		url: fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=%s",
+			strings.TrimRight(endpoint, "/"), url.PathEscape(deployment), url.QueryEscape(apiVersion)),
+		apiKey:    apiKey,
+		maxTokens: maxTokens,
+	}
+	for _, t := range tools {
+		var at ollamaTool
+		at.Type = "function"
+		at.Function.Name = t.Name()
+		at.Function.Description = t.Description()
+		at.Function.Parameters = t.JSONSchema()
+		a.tools = append(a.tools, at)
+	}
+	return a
+}
+
+func (a *azureProvider) Send(ctx context.Context, systemPrompt string, history []Message) (*Response, error) {
+	msgs := []azureMessage{{Role: "system", Content: systemPrompt}}
+	for _, m := range history {
+		if len(m.ToolResults) > 0 {
+			for _, tr := range m.ToolResults {
+				content := tr.Content
+				if tr.IsError {
+					content = "Error: " + content
+				}
+				msgs = append(msgs, azureMessage{Role: "tool", Content: content, ToolCallID: tr.ID})
+			}
+			continue
+		}
+
+		am := azureMessage{Role: m.Role, Content: m.Text}
+		for _, tc := range m.ToolCalls {
+			call := azureToolCall{ID: tc.ID, Type: "function"}
+			call.Function.Name = tc.Name
+			call.Function.Arguments = string(tc.Input)
+			am.ToolCalls = append(am.ToolCalls, call)
+		}
+		msgs = append(msgs, am)
+	}
+
+	req := map[string]any{
+		"messages":              msgs,
+		"max_completion_tokens": tokenBudget(ctx, a.maxTokens),
+	}
+	if len(a.tools) > 0 {
+		req["tools"] = a.tools
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("azure: marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("azure: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("api-key", a.apiKey)
+
+	httpResp, err := a.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("azure: %w", err)
+	}
+	defer httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(httpResp.Body, 512))
+		return nil, &statusError{service: "azure", code: httpResp.StatusCode, status: httpResp.Status, body: string(bytes.TrimSpace(msg))}
+	}
+
+	var resp struct {
+		Choices []struct {
+			Message      azureMessage `json:"message"`
+			FinishReason string       `json:"finish_reason"`
+		} `json:"choices"`
+		Usage struct {
+			PromptTokens     int64 `json:"prompt_tokens"`
+			CompletionTokens int64 `json:"completion_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("azure: decode response: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, fmt.Errorf("azure: no choices in response")
+	}
+
+	choice := resp.Choices[0]
+	out := &Response{
+		Text:         choice.Message.Content,
+		Done:         choice.FinishReason != "tool_calls" && len(choice.Message.ToolCalls) == 0,
+		InputTokens:  resp.Usage.PromptTokens,
+		OutputTokens: resp.Usage.CompletionTokens,
+	}
+	for _, tc := range choice.Message.ToolCalls {
+		if tc.Function.Arguments == "" {
+			tc.Function.Arguments = "{}"
+		}
+		out.ToolCalls = append(out.ToolCalls, ToolCall{
+			ID:    tc.ID,
+			Name:  tc.Function.Name,
+			Input: json.RawMessage(tc.Function.Arguments),
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,249 @@
+package brain
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+)
+
+// bedrockVersion is the Anthropic API version Bedrock expects in the body.
+const bedrockVersion = "bedrock-2023-05-31"
+
+// awsCredentials authenticates Bedrock requests, with either a Bedrock API
+// key (bearer) or an access key pair for SigV4 signing.
+type awsCredentials struct {
+	bearer               string
+	accessKey, secretKey string
+	sessionToken         string
+}
+
+// loadAWSCredentials finds credentials the way the AWS CLI does for the
+// common cases: AWS_BEARER_TOKEN_BEDROCK, then the AWS_ACCESS_KEY_ID family
+// of variables, then the AWS_PROFILE (or default) profile in
+// ~/.aws/credentials.
+func loadAWSCredentials() (awsCredentials, error) {
+	if token := os.Getenv("AWS_BEARER_TOKEN_BEDROCK"); token != "" {
+		return awsCredentials{bearer: token}, nil
+	}
+	if id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"); id != "" && secret != "" {
+		return awsCredentials{accessKey: id, secretKey: secret, sessionToken: os.Getenv("AWS_SESSION_TOKEN")}, nil
+	}
+
+	path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return awsCredentials{}, fmt.Errorf("no AWS credentials in the environment and no home directory: %w", err)
+		}
+		path = filepath.Join(home, ".aws", "credentials")
+	}
+	profile := os.Getenv("AWS_PROFILE")
+	if profile == "" {
+		profile = "default"
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("no AWS credentials in the environment or %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var creds awsCredentials
+	var section string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		key, val, ok := strings.Cut(line, "=")
+		if !ok || section != profile {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "aws_access_key_id":
+			creds.accessKey = strings.TrimSpace(val)
+		case "aws_secret_access_key":
+			creds.secretKey = strings.TrimSpace(val)
+		case "aws_session_token":
+			creds.sessionToken = strings.TrimSpace(val)
+		}
+	}
+	if creds.accessKey == "" || creds.secretKey == "" {
+		return awsCredentials{}, fmt.Errorf("no credentials for profile %q in %s", profile, path)
+	}
+	return creds, nil
+}
+
+// awsRegion returns region, or the AWS_REGION / AWS_DEFAULT_REGION
+// variables when it's empty.
+func awsRegion(region string) string {
+	for _, r := range []string{region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION")} {
+		if r != "" {
+			return r
+		}
+	}
+	return ""
+}
+
+// newBedrockProvider runs Claude through Amazon Bedrock. It's the Claude
+// provider with requests rewritten and signed for Bedrock's invoke API.
+// Bedrock streams in AWS's event-stream format, so answers arrive whole.
+func newBedrockProvider(region, model string, maxTokens int64, tools []Tool) (*claudeProvider, error) {
+	region = awsRegion(region)
+	if region == "" {
+		return nil, fmt.Errorf("no AWS region: set bedrock.region or AWS_REGION")
+	}
+	creds, err := loadAWSCredentials()
+	if err != nil {
+		return nil, err
+	}
+	client := anthropic.NewClient(
+		option.WithAPIKey("bedrock"), // keep the SDK from sending ANTHROPIC_API_KEY
+		option.WithBaseURL(fmt.Sprintf("https://bedrock-runtime.%s.amazonaws.com", region)),
+		option.WithMiddleware(bedrockMiddleware(creds, region)),
+		option.WithMaxRetries(0),
+	)
+	c := newClaudeWithClient(&client, model, maxTokens, tools)
+	c.noStream = true
+	return c, nil
+}
+
+// bedrockMiddleware moves the model and version into Bedrock's URL and
+// body format and authenticates the request.
+func bedrockMiddleware(creds awsCredentials, region string) option.Middleware {
+	return func(r *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+		var body []byte
+		if r.Body != nil {
+			var err error
+			if body, err = io.ReadAll(r.Body); err != nil {
+				return nil, err
+			}
+			r.Body.Close()
+		}
+
+		if r.Method == http.MethodPost && r.URL.Path == "/v1/messages" {
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(body, &fields); err != nil {
+				return nil, fmt.Errorf("bedrock: rewrite request: %w", err)
+			}
+			var model string
+			json.Unmarshal(fields["model"], &model)
+			delete(fields, "model")
+			delete(fields, "stream")
+			fields["anthropic_version"], _ = json.Marshal(bedrockVersion)
+			body, _ = json.Marshal(fields)
+
+			r.URL.Path = "/model/" + model + "/invoke"
+			r.URL.RawPath = "/model/" + url.QueryEscape(model) + "/invoke"
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		r.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(body)), nil }
+		r.ContentLength = int64(len(body))
+
+		r.Header.Del("X-Api-Key")
+		r.Header.Del("Anthropic-Version")
+		if creds.bearer != "" {
+			r.Header.Set("Authorization", "Bearer "+creds.bearer)
+		} else {
+			signV4(r, body, creds, region, "bedrock", time.Now())
+		}
+		return next(r)
+	}
+}
+
+// signV4 adds an AWS Signature Version 4 Authorization header to r,
+// signing the host, date, and (if any) session token headers.
+func signV4(r *http.Request, body []byte, creds awsCredentials, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	r.Header.Set("X-Amz-Date", amzDate)
+	r.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if creds.sessionToken != "" {
+		r.Header.Set("X-Amz-Security-Token", creds.sessionToken)
+	}
+
+	headers := map[string]string{
+		"host":                 r.URL.Host,
+		"x-amz-date":           amzDate,
+		"x-amz-content-sha256": payloadHash,
+	}
+	if creds.sessionToken != "" {
+		headers["x-amz-security-token"] = creds.sessionToken
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonHeaders strings.Builder
+	for _, name := range names {
+		canonHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signed := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		r.Method,
+		awsEscapePath(r.URL.EscapedPath()), // non-S3 services escape the path twice
+		r.URL.Query().Encode(),
+		canonHeaders.String(),
+		signed,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + region + "/" + service + "/aws4_request"
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+
+	key := hmacSHA256([]byte("AWS4"+creds.secretKey), day)
+	for _, part := range []string{region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+
+	r.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.accessKey, scope, signed, signature))
+}
+
+// awsEscapePath percent-encodes everything in path except unreserved
+// characters and slashes.
+func awsEscapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || strings.IndexByte("-_.~/", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
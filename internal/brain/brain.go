@@ -3,28 +3,67 @@ package brain
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"regexp"
+	"runtime/debug"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/moorebrett0/pipet/internal/knowledge"
+	"github.com/moorebrett0/pipet/internal/metrics"
 	"github.com/moorebrett0/pipet/internal/monitor"
 	"github.com/moorebrett0/pipet/internal/pet"
+	"github.com/moorebrett0/pipet/internal/schedule"
 	"github.com/moorebrett0/pipet/internal/shell"
 	"github.com/moorebrett0/pipet/internal/species"
 )
 
 // Brain wraps an AI provider with system prompt building and tool-use loop.
 type Brain struct {
-	provider Provider
+	provider *queuedProvider
+	conn     *lazyProvider    // the provider behind the queue, once it's up
+	breaker  *breakerProvider // retries, and pauses the AI while it's down
 	maxTools int
 	executor *shell.Executor
+	python   *shell.Python   // nil disables run_python
+	docs     *knowledge.Base // nil disables search_docs
+	services []string        // units manage_service may act on
+	runtime  string          // docker or podman; "" disables containers
+	restarts []string        // containers the containers tool may restart
 	petState *pet.PetState
 	monitor  *monitor.Monitor
-
-	// Sliding-window rate limiter
+	memory   *memory // recent exchanges per channel; nil forgets everything
+	tools    *registry
+	toolsOff []string // tools turned off in config
+	usage    *ledger
+	jobs     jobBoard
+	tone     string // Personality directives, appended to the system prompt
+
+	// The config the provider is built from, which SetModel changes, and
+	// the models it may switch between
+	cfgMu     sync.Mutex
+	cfg       Config
+	models    []Model
+	modelPath string
+
+	// Tool output longer than this many bytes is summarized by the model
+	// before it goes back into the conversation (0 disables)
+	summarizeOver int
+
+	structured    bool // AskReply asks for a JSON envelope
+	redactSecrets bool // scrub keys and tokens from what the model says
+
+	// Monthly token cap (0 = none) and prices per million tokens
+	tokenCap                int64
+	inputPrice, outputPrice float64
+
+	// Sliding-window rate limiter, one window per conversation
 	mu      sync.Mutex
-	window  []time.Time
+	windows map[string][]time.Time
 	rateMax int
 	rateDur time.Duration
 }
@@ -39,96 +78,309 @@ type Config struct {
 	GeminiAPIKey string
 	GeminiModel  string
 
-	// Which provider to force ("claude", "gemini", or "" for auto-detect)
+	// Ollama (or another server speaking its API) on the LAN
+	OllamaURL   string
+	OllamaModel string
+
+	// Azure OpenAI: the resource endpoint, key, and deployment to call
+	// (the deployment stands in for the model name)
+	AzureEndpoint   string
+	AzureAPIKey     string
+	AzureDeployment string
+	AzureAPIVersion string
+
+	// Claude on Amazon Bedrock, authenticated from the usual AWS
+	// environment variables or ~/.aws/credentials; an empty region falls
+	// back to AWS_REGION
+	BedrockRegion string
+	BedrockModel  string
+
+	// Which provider to force ("claude", "gemini", "ollama", "azure",
+	// "bedrock", or "" for auto-detect)
 	Provider string
 
+	// More provider:model choices for SetModel beyond each provider's
+	// model above, and where the current choice is saved ("" = not saved)
+	Models    []string
+	ModelPath string
+
 	MaxTokens  int64
 	MaxTools   int
 	RateLimit  int
 	RateWindow time.Duration
+
+	// Worker queue: how many AI requests run at once, and how many more
+	// may wait before new ones are turned away
+	Concurrency int
+	MaxQueue    int
+
+	// Tone knobs layered over the species personality
+	Personality Personality
+
+	// Summarize tool output longer than this many bytes (0 disables)
+	SummarizeOver int
+
+	// Sandboxed interpreter for the run_python tool (nil disables it)
+	Python *shell.Python
+
+	// The owner's notes about their setup, for the search_docs tool (nil or
+	// empty disables it)
+	Docs *knowledge.Base
+
+	// systemd units the manage_service tool may act on (empty disables it)
+	ServiceUnits []string
+
+	// Containers the containers tool may restart; listing and logs work for
+	// every container whenever docker or podman is installed
+	Containers []string
+
+	// Tool names to offer the model; nil offers all of them, and an empty
+	// list none
+	EnabledTools []string
+
+	// Have AskReply request a JSON envelope (text, mood change, optional
+	// embed) instead of plain text
+	StructuredReplies bool
+
+	// Redact private keys, password hashes, and API tokens from everything
+	// the model says before it's posted
+	RedactSecrets bool
+
+	// Extra tools to offer the model alongside the built-in ones; one with
+	// a built-in's name replaces it
+	Tools []Tool
+
+	// Conversation memory: how many recent exchanges per channel are fed
+	// back into each question (0 disables it), and where they're saved
+	// ("" keeps them in memory only)
+	MemoryTurns int
+	MemoryPath  string
+
+	// Token accounting: where totals are saved, a monthly cap past which
+	// only owners get answers (0 = none), and dollars per million input
+	// and output tokens for estimating spend
+	UsagePath       string
+	MonthlyTokenCap int64
+	InputPrice      float64
+	OutputPrice     float64
 }
 
-// New creates a Brain. Returns nil if no API key is configured.
+// New creates a Brain. Returns nil if no API key is configured. If the
+// provider fails to start, the Brain is returned anyway and keeps retrying
+// in the background until ctx ends; see Awake.
 func New(ctx context.Context, cfg Config, exec *shell.Executor, state *pet.PetState, mon *monitor.Monitor) *Brain {
-	provider := newProvider(ctx, cfg)
-	if provider == nil {
-		slog.Info("brain: no API key configured, AI features disabled")
-		return nil
+	if cfg.Docs != nil && cfg.Docs.Len() == 0 {
+		cfg.Docs = nil
 	}
-
-	return &Brain{
-		provider: provider,
+	models := modelChoices(cfg)
+	if m, ok := loadModel(cfg.ModelPath); ok {
+		if slices.Contains(models, m) {
+			slog.Info("brain: using saved model choice", "model", m)
+			cfg = cfg.withModel(m)
+		} else {
+			slog.Warn("brain: saved model isn't configured anymore, ignoring it", "model", m)
+		}
+	}
+	b := &Brain{
 		maxTools: cfg.MaxTools,
 		executor: exec,
+		python:   cfg.Python,
+		docs:     cfg.Docs,
+		services: cfg.ServiceUnits,
+		runtime:  shell.ContainerRuntime(),
+		restarts: cfg.Containers,
 		petState: state,
 		monitor:  mon,
+		tone:     cfg.Personality.directives(),
+		windows:  make(map[string][]time.Time),
 		rateMax:  cfg.RateLimit,
 		rateDur:  cfg.RateWindow,
+
+		cfg:       cfg,
+		models:    models,
+		modelPath: cfg.ModelPath,
+
+		summarizeOver: cfg.SummarizeOver,
+		structured:    cfg.StructuredReplies,
+		redactSecrets: cfg.RedactSecrets,
+		tokenCap:      cfg.MonthlyTokenCap,
+		inputPrice:    cfg.InputPrice,
+		outputPrice:   cfg.OutputPrice,
+	}
+	b.tools = newRegistry()
+	b.tools.add(b.builtinTools()...)
+	b.tools.add(cfg.Tools...)
+	if cfg.EnabledTools != nil {
+		b.toolsOff = b.tools.only(cfg.EnabledTools)
+		for _, name := range cfg.EnabledTools {
+			if _, ok := b.tools.lookup(name); !ok {
+				slog.Warn("brain: enabled tool doesn't exist or isn't set up", "tool", name)
+			}
+		}
+		slog.Info("brain: tools limited by config", "enabled", cfg.EnabledTools, "off", b.toolsOff)
+	}
+
+	var conn *lazyProvider
+	provider, err := newProvider(ctx, cfg, b.tools.tools)
+	switch {
+	case err != nil:
+		slog.Error("brain: AI provider failed to start, retrying in the background", "err", err)
+		conn = retryProvider(ctx, func() (Provider, error) { return newProvider(ctx, b.config(), b.tools.tools) })
+	case provider == nil:
+		slog.Info("brain: no API key configured, AI features disabled")
+		return nil
+	default:
+		conn = readyProvider(provider)
 	}
-}
 
-// newProvider auto-detects or forces the AI provider.
-func newProvider(ctx context.Context, cfg Config) Provider {
-	pick := cfg.Provider
-
-	// Auto-detect if not forced
-	if pick == "" {
-		switch {
-		case cfg.ClaudeAPIKey != "":
-			pick = "claude"
-		case cfg.GeminiAPIKey != "":
-			pick = "gemini"
+	if cfg.MemoryTurns > 0 {
+		if b.memory, err = openMemory(cfg.MemoryPath, cfg.MemoryTurns); err != nil {
+			slog.Error("brain: couldn't load conversation memory, starting fresh", "err", err)
+			b.memory = &memory{path: cfg.MemoryPath, turns: cfg.MemoryTurns, chats: make(map[string][]exchange)}
 		}
 	}
+	if b.usage, err = openLedger(cfg.UsagePath); err != nil {
+		slog.Error("brain: couldn't load token usage, counting from zero", "err", err)
+		b.usage = &ledger{path: cfg.UsagePath, Days: make(map[string]Tokens), Months: make(map[string]Tokens)}
+	}
+
+	b.conn = conn
+	b.breaker = &breakerProvider{Provider: conn}
+	var p Provider = &meteredProvider{Provider: b.breaker, ledger: b.usage}
+	if b.redactSecrets {
+		p = redactingProvider{p}
+	}
+	b.provider = newQueuedProvider(p, cfg.Concurrency, cfg.MaxQueue)
+	return b
+}
 
-	switch pick {
+// newProvider auto-detects or forces the AI provider. Returns nil, nil if
+// none is configured.
+func newProvider(ctx context.Context, cfg Config, tools []Tool) (Provider, error) {
+	switch cfg.picked().Provider {
 	case "claude":
 		if cfg.ClaudeAPIKey == "" {
 			slog.Error("brain: AI_PROVIDER=claude but ANTHROPIC_API_KEY is not set")
-			return nil
+			return nil, nil
 		}
 		slog.Info("brain: using claude", "model", cfg.ClaudeModel)
-		return newClaudeProvider(cfg.ClaudeAPIKey, cfg.ClaudeModel, cfg.MaxTokens)
+		return newClaudeProvider(cfg.ClaudeAPIKey, cfg.ClaudeModel, cfg.MaxTokens, tools), nil
 	case "gemini":
 		if cfg.GeminiAPIKey == "" {
 			slog.Error("brain: AI_PROVIDER=gemini but GOOGLE_API_KEY is not set")
-			return nil
+			return nil, nil
 		}
 		slog.Info("brain: using gemini", "model", cfg.GeminiModel)
-		p, err := newGeminiProvider(ctx, cfg.GeminiAPIKey, cfg.GeminiModel, cfg.MaxTokens)
+		p, err := newGeminiProvider(ctx, cfg.GeminiAPIKey, cfg.GeminiModel, cfg.MaxTokens, tools)
 		if err != nil {
-			slog.Error("brain: failed to create gemini provider", "err", err)
-			return nil
+			return nil, fmt.Errorf("create gemini provider: %w", err)
+		}
+		return p, nil
+	case "ollama":
+		if cfg.OllamaURL == "" {
+			slog.Error("brain: AI_PROVIDER=ollama but OLLAMA_URL is not set")
+			return nil, nil
 		}
-		return p
+		slog.Info("brain: using ollama", "url", cfg.OllamaURL, "model", cfg.OllamaModel)
+		p, err := newOllamaProvider(ctx, cfg.OllamaURL, cfg.OllamaModel, cfg.MaxTokens, tools)
+		if err != nil {
+			return nil, fmt.Errorf("create ollama provider: %w", err)
+		}
+		return p, nil
+	case "azure":
+		if cfg.AzureEndpoint == "" || cfg.AzureAPIKey == "" {
+			slog.Error("brain: AI_PROVIDER=azure but AZURE_OPENAI_ENDPOINT or AZURE_OPENAI_API_KEY is not set")
+			return nil, nil
+		}
+		slog.Info("brain: using azure openai", "endpoint", cfg.AzureEndpoint, "deployment", cfg.AzureDeployment)
+		return newAzureProvider(cfg.AzureEndpoint, cfg.AzureAPIKey, cfg.AzureDeployment, cfg.AzureAPIVersion, cfg.MaxTokens, tools), nil
+	case "bedrock":
+		slog.Info("brain: using bedrock", "region", awsRegion(cfg.BedrockRegion), "model", cfg.BedrockModel)
+		p, err := newBedrockProvider(cfg.BedrockRegion, cfg.BedrockModel, cfg.MaxTokens, tools)
+		if err != nil {
+			return nil, fmt.Errorf("create bedrock provider: %w", err)
+		}
+		return p, nil
 	default:
-		return nil
+		return nil, nil
 	}
 }
 
 // Ask sends a user message to the AI with full context and returns the text response.
 // It handles the tool-use loop internally.
 func (b *Brain) Ask(ctx context.Context, userMessage string) (string, error) {
-	if !b.rateAllow() {
-		return "I need a moment to catch my breath... too many messages! Try again shortly.", nil
+	reply, err := b.ask(ctx, userMessage, false)
+	return reply.Text, err
+}
+
+// ask runs the tool-use loop for Ask and AskReply. With structured set, the
+// model is asked for a reply envelope, and its answer isn't streamed since
+// it's JSON until parsed.
+func (b *Brain) ask(ctx context.Context, userMessage string, structured bool) (_ Reply, err error) {
+	defer func(start time.Time) { metrics.Since("brain.ask", start, err) }(time.Now())
+	defer func() {
+		if p := recover(); p != nil {
+			slog.Error("brain: panic in tool-use loop", "panic", p, "stack", string(debug.Stack()))
+			err = fmt.Errorf("brain panicked: %v", p)
+			metrics.Observe("panic.brain", 0, err)
+		}
+	}()
+
+	if !b.conn.online() {
+		return Reply{Text: "my brain is still booting up... give me a minute and ask again."}, nil
+	}
+	if !b.rateAllow(ctx) {
+		return Reply{Text: "I need a moment to catch my breath... too many messages! Try again shortly."}, nil
+	}
+	if b.overBudget(ctx) {
+		return Reply{Text: "I've used up this month's thinking budget... I'm saving what's left for my owner until next month."}, nil
+	}
+
+	systemPrompt := b.buildSystemPrompt() + b.factsPrompt() + b.preferencesPrompt(ctx)
+	if structured {
+		systemPrompt += replyContract
 	}
 
-	systemPrompt := b.buildSystemPrompt()
+	history := append(b.recall(ctx), Message{Role: "user", Text: userMessage})
 
-	history := []Message{
-		{Role: "user", Text: userMessage},
+	// Stream the answer to whoever's watching, if anyone
+	sendCtx := ctx
+	report := progressFrom(ctx)
+	if b.redactSecrets {
+		report = redactingReport(report)
+	}
+	progress := newProgressLog(report)
+	if progress != nil && !structured {
+		sendCtx = withTextStream(ctx, progress.text)
 	}
 
 	// Tool-use loop
 	for i := 0; i <= b.maxTools; i++ {
-		resp, err := b.provider.Send(ctx, systemPrompt, history)
+		compactHistory(history)
+		resp, err := b.provider.Send(sendCtx, systemPrompt, history)
+		if errors.Is(err, errUnreachable) {
+			slog.Warn("brain: AI server unreachable", "err", err)
+			return Reply{Text: "I can't reach my brain right now... the box it lives on seems to be offline. I'll be my simple self until it's back."}, nil
+		}
+		if errors.Is(err, ErrOutage) {
+			return Reply{}, err
+		}
 		if err != nil {
 			slog.Error("brain: AI API error", "err", err)
-			return "", fmt.Errorf("AI API error: %w", err)
+			return Reply{}, fmt.Errorf("AI API error: %w", err)
 		}
 
 		if resp.Done {
-			return resp.Text, nil
+			reply := Reply{Text: resp.Text}
+			if structured {
+				var ok bool
+				if reply, ok = parseReply(resp.Text); !ok {
+					slog.Warn("brain: reply wasn't a valid envelope, using it as text")
+					reply = Reply{Text: resp.Text}
+				}
+			}
+			b.remember(ctx, userMessage, reply.Text)
+			return reply, nil
 		}
 
 		// Build assistant message with text + tool calls
@@ -142,7 +394,8 @@ func (b *Brain) Ask(ctx context.Context, userMessage string) (string, error) {
 		// Execute tools and collect results
 		var results []ToolResult
 		for _, tc := range resp.ToolCalls {
-			content, isError := b.executeTool(ctx, tc.Name, tc.Input)
+			progress.tool(tc)
+			content, isError := b.safeExecuteTool(ctx, tc.Name, tc.Input)
 			results = append(results, ToolResult{
 				ID:      tc.ID,
 				Content: content,
@@ -158,31 +411,157 @@ func (b *Brain) Ask(ctx context.Context, userMessage string) (string, error) {
 
 	// Hit max tool iterations
 	slog.Warn("brain: hit max tool iterations", "max", b.maxTools)
-	return "I got a bit carried away investigating... let me summarize what I found so far.", nil
+	return Reply{Text: "I got a bit carried away investigating... let me summarize what I found so far."}, nil
 }
 
-func (b *Brain) executeTool(ctx context.Context, name string, input json.RawMessage) (string, bool) {
-	switch name {
-	case "run_shell":
-		var params struct {
-			Command string `json:"command"`
-		}
-		if err := json.Unmarshal(input, &params); err != nil {
-			return fmt.Sprintf("invalid input: %v", err), true
-		}
+// recall returns the recent exchanges in ctx's conversation, to go ahead of
+// the new question. Background requests have no conversation to recall.
+func (b *Brain) recall(ctx context.Context) []Message {
+	channel := conversationFrom(ctx)
+	if b.memory == nil || channel == "" {
+		return nil
+	}
+	u, _ := userFrom(ctx)
+	return b.memory.recall(channel, u.id, time.Now())
+}
 
-		slog.Info("brain: executing shell command", "command", params.Command)
-		output, err := b.executor.Run(ctx, params.Command)
-		if err != nil {
-			return fmt.Sprintf("Error: %v\nOutput: %s", err, output), true
+// remember saves a question and its answer to ctx's conversation.
+func (b *Brain) remember(ctx context.Context, question, answer string) {
+	channel := conversationFrom(ctx)
+	if b.memory == nil || channel == "" || answer == "" {
+		return
+	}
+	u, _ := userFrom(ctx)
+	b.memory.remember(channel, exchange{
+		At:       time.Now(),
+		UserID:   u.id,
+		Name:     u.name,
+		Question: question,
+		Answer:   answer,
+	})
+}
+
+// safeExecuteTool runs a tool, turning a panic into an error result so the
+// model can carry on without it.
+func (b *Brain) safeExecuteTool(ctx context.Context, name string, input json.RawMessage) (content string, isError bool) {
+	defer func() {
+		if p := recover(); p != nil {
+			slog.Error("brain: tool panicked", "tool", name, "panic", p, "stack", string(debug.Stack()))
+			metrics.Observe("panic.tool", 0, fmt.Errorf("panic: %v", p))
+			content, isError = fmt.Sprintf("%s crashed: %v", name, p), true
 		}
-		return output, false
+	}()
+	return b.executeTool(ctx, name, input)
+}
 
-	default:
+func (b *Brain) executeTool(ctx context.Context, name string, input json.RawMessage) (string, bool) {
+	t, ok := b.tools.lookup(name)
+	if !ok {
+		if slices.Contains(b.toolsOff, name) {
+			return fmt.Sprintf("%s is turned off on this pet", name), true
+		}
 		return fmt.Sprintf("unknown tool: %s", name), true
 	}
+	return t.Execute(ctx, input)
+}
+
+// summaryTokens caps a model-side summary of long tool output.
+const summaryTokens = 300
+
+// condense has the model boil very long tool output down to what matters,
+// so a big log or table doesn't crowd out the conversation. Output under
+// the threshold, or a failed summary, comes back unchanged.
+func (b *Brain) condense(ctx context.Context, source, output string) string {
+	if b.summarizeOver <= 0 || len(output) <= b.summarizeOver {
+		return output
+	}
+
+	system := "You condense command output for another assistant diagnosing a Raspberry Pi. Keep exact numbers, names, paths, error messages, and timestamps that matter; drop boilerplate and repeats. Plain text, no commentary about the task."
+	prompt := fmt.Sprintf("Output of %s (%d bytes):\n%s", source, len(output), output)
+
+	resp, err := b.provider.Send(withTokenBudget(ctx, summaryTokens), system, []Message{{Role: "user", Text: prompt}})
+	if err != nil || !resp.Done || strings.TrimSpace(resp.Text) == "" {
+		slog.Warn("brain: couldn't summarize tool output, using it as is", "err", err)
+		return output
+	}
+	return fmt.Sprintf("[summarized from %d bytes]\n%s", len(output), strings.TrimSpace(resp.Text))
+}
+
+// maxDocHits caps how many snippets one search_docs call returns.
+const maxDocHits = 4
+
+// Tool descriptions shared by the providers.
+const (
+	runShellDesc           = "Execute a shell command on the Raspberry Pi host. Use this to check system status, manage services, or investigate issues. Commands have a timeout and blocked patterns for safety. Long output comes back condensed (repeated lines folded with a [×N] count, big tables cut to their header and fullest or busiest rows, and the middle of anything still too long dropped, with every gap marked like \"[40 lines omitted]\") or as a summary marked \"[summarized from N bytes]\"; that is the whole result, so narrow the command (grep, tail, head) instead of rerunning it to find what was cut."
+	searchDocsDesc         = "Search the notes your owner wrote about this Pi's setup (what services run, what drives are for, what not to touch). Check them before diagnosing or changing anything instead of guessing."
+	runPythonDesc          = "Run a short Python 3 script in a sandbox for calculations or parsing text (e.g. log output you already fetched with run_shell). No network, no third-party packages, an empty scratch directory, and tight CPU, memory, and time limits. Print the result."
+	setPreferenceDesc      = "Remember how the person you're talking to likes things, so you can adapt to them next time. Use it when they tell you (or clearly show) a preference: a nickname, how chatty to be, their timezone, a topic to steer clear of, or a command they like. Only for their own preferences."
+	rememberDesc           = "Save a fact to your long-term memory so you still know it weeks from now: the owner's name, what a drive or service is for, a chore you did together (\"cleaned /var/log on March 3\"). Only lasting, useful things, not chit-chat or current stats. Only owners can have you remember things."
+	rememberFactDesc       = "The fact, one short self-contained sentence"
+	manageServiceDesc      = "Check on, start, restart, or stop one of the services your owner lets you manage, e.g. to restart one that crashed. Use this instead of systemctl through run_shell. Only owners can have you change a service; anyone can ask for its status."
+	containersDesc         = "Check on the containers running on the Pi (the other creatures in the reef): list them with their status, read the last lines of one's logs, or restart one your owner allows. Only owners can have you restart a container."
+	forgetDesc             = "Drop a fact from your long-term memory, when you're told to forget it or it's no longer true. Only owners can have you forget things."
+	writeFileDesc          = "Replace a file on the Pi with new contents, e.g. to fix a config file. Nothing is written until your owner approves it in Discord, so the tool only tells you the request was posted. Read the file with run_shell first and write the whole thing, not just the changed lines. The old file is backed up."
+	appendFileDesc         = "Add text to the end of a file on the Pi, e.g. a line to a config file or a crontab, creating the file if it's missing. Nothing is written until your owner approves it in Discord, so the tool only tells you the request was posted."
+	filePathDesc           = "Absolute path of the file"
+	setPreferenceValueDesc = "The value: a nickname; brief, normal, or detailed for verbosity; an IANA timezone like America/Chicago; or one topic or command. Empty clears nickname/verbosity/timezone; repeating an existing topic or command forgets it."
+)
+
+// factsPrompt lists what's in the pet's long-term memory.
+func (b *Brain) factsPrompt() string {
+	facts := b.petState.KnownFacts()
+	if len(facts) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("\n\n## Things You Remember\n")
+	for _, f := range facts {
+		fmt.Fprintf(&sb, "%d. %s (%s)\n", f.ID, f.Text, f.At.Format("Jan 2"))
+	}
+	return sb.String()
 }
 
+// preferencesPrompt describes what's known about the user behind ctx.
+func (b *Brain) preferencesPrompt(ctx context.Context) string {
+	u, ok := userFrom(ctx)
+	if !ok {
+		return ""
+	}
+	p := b.petState.Preferences(u.id)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\n\n## Who You're Talking To\n- Discord name: %s\n", u.name)
+	if p.Nickname != "" {
+		fmt.Fprintf(&sb, "- Call them %s.\n", p.Nickname)
+	}
+	switch p.Verbosity {
+	case "brief":
+		sb.WriteString("- They like answers short: one sentence when you can.\n")
+	case "detailed":
+		sb.WriteString("- They like detail: explain what you found and why.\n")
+	}
+	if p.Timezone != "" {
+		if loc, err := time.LoadLocation(p.Timezone); err == nil {
+			fmt.Fprintf(&sb, "- Their timezone is %s (it's %s for them).\n", p.Timezone, time.Now().In(loc).Format("Mon 15:04"))
+		}
+	}
+	if len(p.Avoid) > 0 {
+		fmt.Fprintf(&sb, "- Don't bring up: %s.\n", strings.Join(p.Avoid, ", "))
+	}
+	if len(p.Favorites) > 0 {
+		fmt.Fprintf(&sb, "- Commands they like: %s.\n", strings.Join(p.Favorites, ", "))
+	}
+	if p.IsZero() {
+		sb.WriteString("- You haven't learned their preferences yet. Use set_preference when they tell you one.\n")
+	}
+	return sb.String()
+}
+
+// stateHeading starts the part of the system prompt that changes from call
+// to call. Everything before it (personality, guidelines, tone) only
+// changes when the pet does, so providers can cache it.
+const stateHeading = "## Current State\n"
+
 func (b *Brain) buildSystemPrompt() string {
 	snap := b.petState.Snapshot()
 	stats := b.monitor.Stats()
@@ -191,14 +570,48 @@ func (b *Brain) buildSystemPrompt() string {
 	if sp == nil {
 		sp = species.Registry["octopus"] // fallback
 	}
+	sp = sp.Evolved(snap.Form).AtStage(snap.Stage).Wearing(snap.Skin, time.Now())
+
+	health := "healthy"
+	if snap.Illness != nil {
+		a := snap.Illness.Ailment()
+		health = fmt.Sprintf("ill with %s (%s), from the Pi's %s", a.Name, a.Symptoms, a.Cause)
+	}
+
+	toolHints := ""
+	if shell.Shell != "sh" {
+		toolHints = "\n- This host runs Windows: run_shell commands go to " + shell.Shell + ", so use its cmdlets and Windows paths (C:\\...)."
+	}
+	if b.python != nil {
+		toolHints += "\n- For math or picking apart command output, use run_python rather than long shell one-liners."
+	}
+	if len(b.toolsOff) > 0 {
+		toolHints += "\n- These tools are turned off here, so don't offer to use them: " + strings.Join(b.toolsOff, ", ") + "."
+	}
+	if b.docs != nil {
+		toolHints += fmt.Sprintf("\n- Your owner left notes about this Pi (%s). Use search_docs before diagnosing or touching services, drives, or config, and respect anything they say not to touch.",
+			strings.Join(b.docs.Files(), ", "))
+	}
 
 	return fmt.Sprintf(`You are %s, a digital pet %s (%s) living inside a Raspberry Pi.
 
 ## Your Personality
 %s
 
-## Current State
-- Mood: %s
+## Guidelines
+- Stay in character as %s the %s at all times.
+- You live inside this Raspberry Pi — it's your home/body.
+- When the system is stressed (high CPU, memory, temp), you feel it physically.
+- Keep responses concise (1-3 sentences usually).
+- You can use the run_shell tool to check on your Pi or help your owner.
+- Use the set_preference tool to remember how people like to be talked to.
+- Use remember for lasting facts worth knowing next week, and forget for ones that stop being true.%s
+- write_file and append_file only ask your owner for approval; say so, and don't claim the change is made.
+- If asked about system status, check it with shell commands rather than guessing.
+- Express your personality through your responses — use your species' mannerisms.
+- You care about your owner and your Pi home.%s
+
+`+stateHeading+`- Mood: %s
 - Hunger: %.0f/100 (0=full, 100=starving)
 - Happiness: %.0f/100
 - Energy: %.0f/100
@@ -206,52 +619,254 @@ func (b *Brain) buildSystemPrompt() string {
 - Bond: %.0f/100 (how close you are with your owner)
 - Age: %.1f days
 - Alive: %v
+- Health: %s
 
 ## Host System Status
 - CPU: %.1f%%
 - Memory: %.1f%%
 - Disk: %.1f%%
 - Temperature: %.1f°C
-- Uptime: %.1f days
-
-## Guidelines
-- Stay in character as %s the %s at all times.
-- You live inside this Raspberry Pi — it's your home/body.
-- When the system is stressed (high CPU, memory, temp), you feel it physically.
-- Keep responses concise (1-3 sentences usually).
-- You can use the run_shell tool to check on your Pi or help your owner.
-- If asked about system status, check it with shell commands rather than guessing.
-- Express your personality through your responses — use your species' mannerisms.
-- You care about your owner and your Pi home.`,
+- Uptime: %.1f days`,
 		snap.Name, sp.Name, sp.Emoji, sp.Personality,
+		snap.Name, sp.Name, toolHints, b.tone,
 		snap.Mood, snap.Hunger, snap.Happiness, snap.Energy, snap.Cleanliness, snap.Bond,
-		snap.AgeDays, snap.IsAlive,
-		stats.CPUPercent, stats.MemPercent, stats.DiskPercent, stats.TempC, stats.UptimeDays,
-		snap.Name, sp.Name)
+		snap.AgeDays, snap.IsAlive, health,
+		stats.CPUPercent, stats.MemPercent, stats.DiskPercent, stats.TempC, stats.UptimeDays)
+}
+
+// Awake returns a channel that's closed once the AI provider is connected.
+// It's already closed unless the provider failed to start.
+func (b *Brain) Awake() <-chan struct{} {
+	return b.conn.ready
+}
+
+// Backlogged reports whether a new request would have to wait for other AI
+// requests to finish, so callers can let the user know.
+func (b *Brain) Backlogged() bool {
+	return b.provider.busy()
+}
+
+// Usage returns the AI tokens used today and this month, with the cap and
+// prices to estimate spend.
+func (b *Brain) Usage() Usage {
+	day, month := b.usage.totals(time.Now())
+	return Usage{
+		Today:       day,
+		Month:       month,
+		Cap:         b.tokenCap,
+		InputPrice:  b.inputPrice,
+		OutputPrice: b.outputPrice,
+	}
+}
+
+// overBudget reports whether the monthly token cap is spent and ctx is a
+// conversation with someone other than an owner. The pet's own background
+// work (diary, digests) isn't cut off.
+func (b *Brain) overBudget(ctx context.Context) bool {
+	if b.tokenCap <= 0 || ownerFrom(ctx) || conversationFrom(ctx) == "" {
+		return false
+	}
+	_, month := b.usage.totals(time.Now())
+	return month.Total() >= b.tokenCap
 }
 
 // --- Sliding-window rate limiter ---
 
-func (b *Brain) rateAllow() bool {
+// rateAllow counts a request against the window for ctx's conversation, so
+// a busy thread can't use up another channel's budget. Background requests
+// share the "" window.
+func (b *Brain) rateAllow(ctx context.Context) bool {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
 	now := time.Now()
 	cutoff := now.Add(-b.rateDur)
-
-	// Remove expired entries
-	valid := b.window[:0]
-	for _, t := range b.window {
-		if t.After(cutoff) {
-			valid = append(valid, t)
+	key := conversationFrom(ctx)
+
+	// Remove expired entries, and windows that have gone quiet
+	for k, window := range b.windows {
+		valid := window[:0]
+		for _, t := range window {
+			if t.After(cutoff) {
+				valid = append(valid, t)
+			}
+		}
+		if len(valid) == 0 {
+			delete(b.windows, k)
+		} else {
+			b.windows[k] = valid
 		}
 	}
-	b.window = valid
 
-	if len(b.window) >= b.rateMax {
+	if len(b.windows[key]) >= b.rateMax {
 		return false
 	}
 
-	b.window = append(b.window, now)
+	b.windows[key] = append(b.windows[key], now)
 	return true
 }
+
+// allow applies the rate limit and the monthly token cap to a request
+// outside a conversation's tool-use loop.
+func (b *Brain) allow(ctx context.Context) error {
+	if !b.rateAllow(ctx) {
+		return fmt.Errorf("rate limited")
+	}
+	if b.overBudget(ctx) {
+		return fmt.Errorf("over the monthly token cap")
+	}
+	return nil
+}
+
+// oneShot sends a single prompt with no history or tools, after the same
+// checks as Ask, and returns the trimmed reply. Callers cap its length
+// with withTokenBudget.
+func (b *Brain) oneShot(ctx context.Context, system, prompt string) (string, error) {
+	if err := b.allow(ctx); err != nil {
+		return "", err
+	}
+	resp, err := b.provider.Send(ctx, system, []Message{{Role: "user", Text: prompt}})
+	if err != nil {
+		return "", fmt.Errorf("AI API error: %w", err)
+	}
+	text := strings.TrimSpace(resp.Text)
+	if text == "" {
+		return "", fmt.Errorf("empty reply")
+	}
+	return text, nil
+}
+
+// listMarker matches the bullet or number a model puts before each line of
+// a list, like "- ", "2. ", or "3) ".
+var listMarker = regexp.MustCompile(`^\s*(?:[-*•]|\d+[.)])\s*`)
+
+// SuggestNames asks the model for a handful of species-appropriate pet names.
+func (b *Brain) SuggestNames(ctx context.Context, speciesID string) ([]string, error) {
+	sp := species.Registry[speciesID]
+	if sp == nil {
+		return nil, fmt.Errorf("unknown species: %s", speciesID)
+	}
+
+	system := "You name newly hatched digital pets that live inside a Raspberry Pi. Reply with names only, one per line, no numbering or commentary."
+	prompt := fmt.Sprintf("Suggest 5 short, cute names for a %s. %s", sp.Name, sp.PersonalitySummary())
+
+	text, err := b.oneShot(ctx, system, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, line := range strings.Split(text, "\n") {
+		name := strings.Trim(listMarker.ReplaceAllString(line, ""), " \"'“”")
+		if name != "" && len(name) <= 32 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no names in response")
+	}
+	return names, nil
+}
+
+// WriteDiary asks the model for a short in-character diary entry about the
+// day's events, capped at maxTokens of output.
+func (b *Brain) WriteDiary(ctx context.Context, events []pet.Event, maxTokens int64) (string, error) {
+	var day strings.Builder
+	for _, e := range events {
+		fmt.Fprintf(&day, "- %s %s\n", e.At.Format("15:04"), e.Text)
+	}
+	if day.Len() == 0 {
+		day.WriteString("- nothing much happened\n")
+	}
+
+	system := b.buildSystemPrompt() + "\n\n## Diary\nYou're writing tonight's diary entry. Reflect on the day in 2-4 short sentences, in character, referencing what actually happened. No headings, no tool use."
+	prompt := "Today's events:\n" + day.String()
+
+	return b.oneShot(withTokenBudget(ctx, maxTokens), system, prompt)
+}
+
+// Dream asks the model for a short, surreal in-character dream built from
+// the previous day's events and a plain-text summary of the Pi's readings,
+// capped at maxTokens of output.
+func (b *Brain) Dream(ctx context.Context, events []pet.Event, readings string, maxTokens int64) (string, error) {
+	var day strings.Builder
+	for _, e := range events {
+		fmt.Fprintf(&day, "- %s %s\n", e.At.Format("15:04"), e.Text)
+	}
+	if day.Len() == 0 {
+		day.WriteString("- nothing much happened\n")
+	}
+
+	system := b.buildSystemPrompt() + "\n\n## Dream\nYou just woke up and are telling your owner about last night's dream. Describe it in 2-3 short sentences, in character: surreal and dreamlike, but woven from what actually happened yesterday and the Pi's readings. No headings, no tool use."
+	prompt := "Yesterday's events:\n" + day.String() + "\nYesterday's readings:\n" + readings
+
+	return b.oneShot(withTokenBudget(ctx, maxTokens), system, prompt)
+}
+
+// SummarizeLogs asks the model for a two-line, in-character summary of the
+// day's journal warnings and errors, capped at maxTokens of output.
+func (b *Brain) SummarizeLogs(ctx context.Context, lines []string, maxTokens int64) (string, error) {
+	system := b.buildSystemPrompt() + "\n\n## Log Summary\nYou're telling your owner what showed up in the Pi's system logs today. Reply in exactly two short lines, in character: first whether anything is worth worrying about, then the most notable thing. No tool use."
+	prompt := "Today's journald warnings and errors (repeats collapsed):\n" + strings.Join(lines, "\n")
+
+	return b.oneShot(withTokenBudget(ctx, maxTokens), system, prompt)
+}
+
+// WritePostmortem asks the model for a short incident postmortem from a
+// plain-text incident report, capped at maxTokens of output.
+func (b *Brain) WritePostmortem(ctx context.Context, report string, maxTokens int64) (string, error) {
+	system := b.buildSystemPrompt() + "\n\n## Postmortem\nA distress condition on the Pi just cleared. Write a short postmortem in character: what spiked and when, the likely cause, and what was done about it. Use at most 5 short bullet points. Only claim causes the report supports. No tool use."
+
+	return b.oneShot(withTokenBudget(ctx, maxTokens), system, report)
+}
+
+// PlanTask asks the model to turn a request like "check disk space every
+// Friday evening and tell me" into a scheduled task. Returns
+// schedule.ErrNotSchedule if the request isn't asking for anything recurring.
+func (b *Brain) PlanTask(ctx context.Context, request string) (schedule.Task, error) {
+	if err := b.allow(ctx); err != nil {
+		return schedule.Task{}, err
+	}
+
+	system := `You turn requests for recurring checks on a Raspberry Pi into a schedule entry. Reply with a single JSON object and nothing else:
+{"schedule": true, "description": "<short lowercase summary, e.g. check disk space>", "command": "<one read-only shell command>", "days": ["friday"], "hour": 18, "minute": 0}
+- "days" lists weekday names; use an empty list for every day.
+- Use 24-hour local time. "morning" is 9:00, "afternoon" 14:00, "evening" 18:00, "night" 22:00.
+- Prefer commands that only read state (df, free, uptime, systemctl status, journalctl).
+If the request isn't asking for something to happen on a recurring schedule, reply {"schedule": false}.`
+
+	resp, err := b.provider.Send(withTokenBudget(ctx, 200), system, []Message{{Role: "user", Text: request}})
+	if err != nil {
+		return schedule.Task{}, fmt.Errorf("AI API error: %w", err)
+	}
+	if !resp.Done {
+		return schedule.Task{}, fmt.Errorf("model tried to use a tool while planning")
+	}
+
+	t, err := schedule.ParsePlan(resp.Text)
+	if err != nil {
+		return schedule.Task{}, err
+	}
+	if err := shell.Check(t.Command); err != nil {
+		return schedule.Task{}, err
+	}
+	return t, nil
+}
+
+// TellStory asks the model for a short in-character narrative of how the pet
+// is doing right now, drawing on its current state and recent events,
+// capped at maxTokens of output.
+func (b *Brain) TellStory(ctx context.Context, events []pet.Event, maxTokens int64) (string, error) {
+	var recent strings.Builder
+	for _, e := range events {
+		fmt.Fprintf(&recent, "- %s %s\n", e.At.Format("Mon 15:04"), e.Text)
+	}
+	if recent.Len() == 0 {
+		recent.WriteString("- a quiet stretch, nothing logged\n")
+	}
+
+	system := b.buildSystemPrompt() + "\n\n## Story\nSomeone asked how you're doing. Tell it as a tiny story in the third person, 3-5 sentences, in character: how you feel, what your Pi home is like right now, and what's happened lately. Weave the numbers in naturally instead of listing them. No headings, no tool use."
+	prompt := "Recent events:\n" + recent.String()
+
+	return b.oneShot(withTokenBudget(ctx, maxTokens), system, prompt)
+}
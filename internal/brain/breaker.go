@@ -0,0 +1,160 @@
+package brain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"google.golang.org/genai"
+
+	"github.com/moorebrett0/pipet/internal/metrics"
+)
+
+// ErrOutage is returned while the circuit breaker is open: the provider
+// kept failing, so requests aren't sent until it's had time to recover.
+var ErrOutage = errors.New("AI provider outage")
+
+// Retry and circuit breaker tuning. A Send is tried sendAttempts times on
+// rate limits and server errors, backing off from retryBase; after
+// breakerTrips Sends in a row fail that way, the breaker opens for
+// breakerCooldown, doubling up to breakerMaxCooldown while the provider
+// stays down.
+const (
+	sendAttempts       = 3
+	retryBase          = 2 * time.Second
+	retryMax           = 20 * time.Second
+	breakerTrips       = 3
+	breakerCooldown    = 2 * time.Minute
+	breakerMaxCooldown = 30 * time.Minute
+)
+
+// statusError is an HTTP error from a provider we call directly, kept typed
+// so transient can read the status code.
+type statusError struct {
+	service string
+	code    int
+	status  string
+	body    string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.service, e.status, e.body)
+}
+
+// transient reports whether err is worth retrying: rate limiting,
+// overload, and server errors.
+func transient(err error) bool {
+	code := 0
+	var claudeErr *anthropic.Error
+	var geminiErr genai.APIError
+	var httpErr *statusError
+	switch {
+	case errors.As(err, &claudeErr):
+		code = claudeErr.StatusCode
+	case errors.As(err, &geminiErr):
+		code = geminiErr.Code
+	case errors.As(err, &httpErr):
+		code = httpErr.code
+	}
+	return code == http.StatusTooManyRequests || code == http.StatusRequestTimeout || code >= 500
+}
+
+// breakerProvider retries transient errors with exponential backoff, and
+// stops calling the provider for a while once it keeps failing so the pet
+// can fall back to its templates instead of making everyone wait.
+type breakerProvider struct {
+	Provider
+
+	mu       sync.Mutex
+	failures int           // Sends in a row that failed transiently
+	cooldown time.Duration // how long the next trip opens the breaker
+	since    time.Time     // when the outage began (zero if none)
+	until    time.Time     // no Sends before this
+}
+
+func (p *breakerProvider) Send(ctx context.Context, systemPrompt string, history []Message) (*Response, error) {
+	if _, until, down := p.outage(); down {
+		return nil, fmt.Errorf("%w, trying again at %s", ErrOutage, until.Format(time.Kitchen))
+	}
+
+	backoff := retryBase
+	for attempt := 1; ; attempt++ {
+		resp, err := p.Provider.Send(ctx, systemPrompt, history)
+		if err == nil {
+			p.succeeded()
+			return resp, nil
+		}
+		if !transient(err) || ctx.Err() != nil {
+			return nil, err
+		}
+		if attempt == sendAttempts {
+			if p.failed(err) {
+				return nil, fmt.Errorf("%w: %w", ErrOutage, err)
+			}
+			return nil, err
+		}
+		wait := backoff + time.Duration(rand.Int63n(int64(backoff/2)))
+		slog.Warn("brain: provider error, retrying", "err", err, "attempt", attempt, "retry_in", wait)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(wait):
+		}
+		backoff = min(2*backoff, retryMax)
+	}
+}
+
+// outage reports whether the breaker is open, since when, and until when.
+func (p *breakerProvider) outage() (since, until time.Time, down bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.since, p.until, time.Now().Before(p.until)
+}
+
+func (p *breakerProvider) succeeded() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.since.IsZero() {
+		slog.Info("brain: provider recovered", "down_for", time.Since(p.since).Round(time.Second))
+		metrics.SetHealth("brain", true, "connected")
+	}
+	p.failures, p.cooldown, p.since, p.until = 0, 0, time.Time{}, time.Time{}
+}
+
+// failed counts a Send that ran out of retries, opening the breaker once
+// there have been enough in a row, and reports whether it did. A failure
+// just after the breaker closes again reopens it for twice as long.
+func (p *breakerProvider) failed(err error) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failures++
+	if p.failures < breakerTrips && p.since.IsZero() {
+		return false
+	}
+	if p.cooldown == 0 {
+		p.cooldown = breakerCooldown
+	} else {
+		p.cooldown = min(2*p.cooldown, breakerMaxCooldown)
+	}
+	now := time.Now()
+	if p.since.IsZero() {
+		p.since = now
+	}
+	p.until = now.Add(p.cooldown)
+	slog.Error("brain: provider keeps failing, pausing AI", "err", err, "for", p.cooldown)
+	metrics.SetHealth("brain", false, "provider outage: "+err.Error())
+	return true
+}
+
+// Outage reports whether the AI is paused because its provider kept
+// failing, when that began, and when it'll be tried again. Until then the
+// pet should stick to its templates.
+func (b *Brain) Outage() (since, until time.Time, down bool) {
+	return b.breaker.outage()
+}
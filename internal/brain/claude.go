@@ -3,46 +3,49 @@ package brain
 import (
 	"context"
 	"encoding/json"
+	"log/slog"
+	"strings"
 
 	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/anthropics/anthropic-sdk-go/option"
 )
 
-// runShellTool is the Claude tool definition for executing shell commands.
-var runShellTool anthropic.ToolUnionParam
-
-func init() {
-	tool := anthropic.ToolUnionParamOfTool(
-		anthropic.ToolInputSchemaParam{
-			Type: "object",
-			Properties: map[string]any{
-				"command": map[string]any{
-					"type":        "string",
-					"description": "The shell command to execute",
-				},
-			},
-			Required: []string{"command"},
-		},
-		"run_shell",
-	)
-	tool.OfTool.Description = anthropic.String("Execute a shell command on the Raspberry Pi host. Use this to check system status, manage services, or investigate issues. Commands have a timeout and blocked patterns for safety. Output is truncated to 10KB.")
-	runShellTool = tool
-}
-
 // claudeProvider implements Provider using the Anthropic Claude API.
 type claudeProvider struct {
 	client    *anthropic.Client
 	model     anthropic.Model
 	maxTokens int64
+	tools     []anthropic.ToolUnionParam
+	noStream  bool // the endpoint can't stream in the SDK's format
+}
+
+func newClaudeProvider(apiKey, model string, maxTokens int64, tools []Tool) *claudeProvider {
+	client := anthropic.NewClient(option.WithAPIKey(apiKey), option.WithMaxRetries(0)) // breakerProvider retries
+	return newClaudeWithClient(&client, model, maxTokens, tools)
 }
 
-func newClaudeProvider(apiKey, model string, maxTokens int64) *claudeProvider {
-	client := anthropic.NewClient(option.WithAPIKey(apiKey))
-	return &claudeProvider{
-		client:    &client,
+// newClaudeWithClient builds the provider around a configured client, so
+// other endpoints serving Claude (Bedrock) can share it.
+func newClaudeWithClient(client *anthropic.Client, model string, maxTokens int64, tools []Tool) *claudeProvider {
+	c := &claudeProvider{
+		client:    client,
 		model:     anthropic.Model(model),
 		maxTokens: maxTokens,
 	}
+	for _, t := range tools {
+		schema := t.JSONSchema()
+		required, _ := schema["required"].([]string)
+		tool := anthropic.ToolUnionParamOfTool(
+			anthropic.ToolInputSchemaParam{
+				Properties: schema["properties"],
+				Required:   required,
+			},
+			t.Name(),
+		)
+		tool.OfTool.Description = anthropic.String(t.Description())
+		c.tools = append(c.tools, tool)
+	}
+	return c
 }
 
 func (c *claudeProvider) Send(ctx context.Context, systemPrompt string, history []Message) (*Response, error) {
@@ -80,19 +83,57 @@ func (c *claudeProvider) Send(ctx context.Context, systemPrompt string, history
 		}
 	}
 
-	resp, err := c.client.Messages.New(ctx, anthropic.MessageNewParams{
+	// Cache the tools and the static top of the system prompt, so repeat
+	// calls only pay full price for the current stats and the conversation
+	system := []anthropic.TextBlockParam{{Text: systemPrompt}}
+	if static, state, ok := strings.Cut(systemPrompt, stateHeading); ok {
+		system = []anthropic.TextBlockParam{
+			{Text: static, CacheControl: anthropic.NewCacheControlEphemeralParam()},
+			{Text: stateHeading + state},
+		}
+	}
+
+	params := anthropic.MessageNewParams{
 		Model:     c.model,
-		MaxTokens: c.maxTokens,
-		System:    []anthropic.TextBlockParam{{Text: systemPrompt}},
+		MaxTokens: tokenBudget(ctx, c.maxTokens),
+		System:    system,
 		Messages:  msgs,
-		Tools:     []anthropic.ToolUnionParam{runShellTool},
-	})
-	if err != nil {
-		return nil, err
+		Tools:     c.tools,
+	}
+	var resp *anthropic.Message
+	if onText := streamFrom(ctx); onText != nil && !c.noStream {
+		stream := c.client.Messages.NewStreaming(ctx, params)
+		defer stream.Close()
+		resp = &anthropic.Message{}
+		for stream.Next() {
+			event := stream.Current()
+			if err := resp.Accumulate(event); err != nil {
+				return nil, err
+			}
+			if event.Type == "content_block_delta" && event.Delta.Type == "text_delta" {
+				onText(event.Delta.Text)
+			}
+		}
+		if err := stream.Err(); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if resp, err = c.client.Messages.New(ctx, params); err != nil {
+			return nil, err
+		}
 	}
 
-	// Convert response
-	out := &Response{Done: resp.StopReason != anthropic.StopReasonToolUse}
+	// Convert response. Cached prompt tokens are reported separately; they
+	// still count toward the token cap
+	out := &Response{
+		Done:         resp.StopReason != anthropic.StopReasonToolUse,
+		InputTokens:  resp.Usage.InputTokens + resp.Usage.CacheCreationInputTokens + resp.Usage.CacheReadInputTokens,
+		OutputTokens: resp.Usage.OutputTokens,
+	}
+	if resp.Usage.CacheReadInputTokens > 0 {
+		slog.Debug("brain: claude prompt cache hit", "cached", resp.Usage.CacheReadInputTokens, "uncached", resp.Usage.InputTokens)
+	}
 
 	for _, block := range resp.Content {
 		switch block.Type {
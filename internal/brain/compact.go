@@ -0,0 +1,38 @@
+package brain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moorebrett0/pipet/internal/shell"
+)
+
+// Tool-loop compaction: the newest keepRawResults rounds of tool results go
+// back to the model as they are, and older ones are cut to about
+// compactResultBytes so a long investigation doesn't outgrow the context.
+const (
+	keepRawResults     = 2
+	compactResultBytes = 800
+	compactedNote      = "[earlier output, trimmed from %d bytes]\n"
+)
+
+// compactHistory trims tool results older than the last keepRawResults
+// rounds, in place. The model already saw them in full and answered, so
+// the gist (head, tail, errors, unusual table rows) is enough from then on.
+func compactHistory(history []Message) {
+	rounds := 0
+	for i := len(history) - 1; i >= 0; i-- {
+		if len(history[i].ToolResults) == 0 {
+			continue
+		}
+		if rounds++; rounds <= keepRawResults {
+			continue
+		}
+		for j, tr := range history[i].ToolResults {
+			if len(tr.Content) <= compactResultBytes || strings.HasPrefix(tr.Content, "[earlier output") {
+				continue
+			}
+			history[i].ToolResults[j].Content = fmt.Sprintf(compactedNote, len(tr.Content)) + shell.Condense(tr.Content, compactResultBytes)
+		}
+	}
+}
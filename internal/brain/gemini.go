@@ -3,34 +3,20 @@ package brain
 import (
 	"context"
 	"encoding/json"
+	"strings"
 
 	"google.golang.org/genai"
 )
 
-// runShellDecl is the Gemini function declaration for executing shell commands.
-var runShellDecl = &genai.FunctionDeclaration{
-	Name:        "run_shell",
-	Description: "Execute a shell command on the Raspberry Pi host. Use this to check system status, manage services, or investigate issues. Commands have a timeout and blocked patterns for safety. Output is truncated to 10KB.",
-	Parameters: &genai.Schema{
-		Type: genai.TypeObject,
-		Properties: map[string]*genai.Schema{
-			"command": {
-				Type:        genai.TypeString,
-				Description: "The shell command to execute",
-			},
-		},
-		Required: []string{"command"},
-	},
-}
-
 // geminiProvider implements Provider using the Google Gemini API.
 type geminiProvider struct {
 	client    *genai.Client
 	model     string
 	maxTokens int32
+	decls     []*genai.FunctionDeclaration
 }
 
-func newGeminiProvider(ctx context.Context, apiKey, model string, maxTokens int64) (*geminiProvider, error) {
+func newGeminiProvider(ctx context.Context, apiKey, model string, maxTokens int64, tools []Tool) (*geminiProvider, error) {
 	client, err := genai.NewClient(ctx, &genai.ClientConfig{
 		APIKey:  apiKey,
 		Backend: genai.BackendGeminiAPI,
@@ -38,10 +24,19 @@ func newGeminiProvider(ctx context.Context, apiKey, model string, maxTokens int6
 	if err != nil {
 		return nil, err
 	}
+	var decls []*genai.FunctionDeclaration
+	for _, t := range tools {
+		decls = append(decls, &genai.FunctionDeclaration{
+			Name:                 t.Name(),
+			Description:          t.Description(),
+			ParametersJsonSchema: t.JSONSchema(),
+		})
+	}
 	return &geminiProvider{
 		client:    client,
 		model:     model,
 		maxTokens: int32(maxTokens),
+		decls:     decls,
 	}, nil
 }
 
@@ -92,40 +87,57 @@ func (g *geminiProvider) Send(ctx context.Context, systemPrompt string, history
 
 	config := &genai.GenerateContentConfig{
 		SystemInstruction: genai.NewContentFromText(systemPrompt, ""),
-		MaxOutputTokens:   g.maxTokens,
+		MaxOutputTokens:   int32(tokenBudget(ctx, int64(g.maxTokens))),
 		Tools: []*genai.Tool{
-			{FunctionDeclarations: []*genai.FunctionDeclaration{runShellDecl}},
+			{FunctionDeclarations: g.decls},
 		},
 	}
 
-	resp, err := g.client.Models.GenerateContent(ctx, g.model, contents, config)
-	if err != nil {
-		return nil, err
-	}
-
-	// Extract function calls
-	calls := resp.FunctionCalls()
-	if len(calls) > 0 {
-		out := &Response{Done: false}
-		// Also grab any text from the response
-		out.Text = resp.Text()
-		for _, fc := range calls {
-			raw, _ := json.Marshal(fc.Args)
-			id := fc.ID
-			if id == "" {
-				id = fc.Name // fallback: use name as ID
+	var (
+		text  string
+		calls []*genai.FunctionCall
+		usage *genai.GenerateContentResponseUsageMetadata
+	)
+	if onText := streamFrom(ctx); onText != nil {
+		var sb strings.Builder
+		for chunk, err := range g.client.Models.GenerateContentStream(ctx, g.model, contents, config) {
+			if err != nil {
+				return nil, err
 			}
-			out.ToolCalls = append(out.ToolCalls, ToolCall{
-				ID:    id,
-				Name:  fc.Name,
-				Input: raw,
-			})
+			if t := chunk.Text(); t != "" {
+				sb.WriteString(t)
+				onText(t)
+			}
+			calls = append(calls, chunk.FunctionCalls()...)
+			if chunk.UsageMetadata != nil {
+				usage = chunk.UsageMetadata // running totals; the last chunk has them all
+			}
+		}
+		text = sb.String()
+	} else {
+		resp, err := g.client.Models.GenerateContent(ctx, g.model, contents, config)
+		if err != nil {
+			return nil, err
 		}
-		return out, nil
+		text, calls, usage = resp.Text(), resp.FunctionCalls(), resp.UsageMetadata
 	}
 
-	return &Response{
-		Text: resp.Text(),
-		Done: true,
-	}, nil
+	out := &Response{Text: text, Done: len(calls) == 0}
+	if usage != nil {
+		out.InputTokens = int64(usage.PromptTokenCount + usage.ToolUsePromptTokenCount)
+		out.OutputTokens = int64(usage.CandidatesTokenCount + usage.ThoughtsTokenCount)
+	}
+	for _, fc := range calls {
+		raw, _ := json.Marshal(fc.Args)
+		id := fc.ID
+		if id == "" {
+			id = fc.Name // fallback: use name as ID
+		}
+		out.ToolCalls = append(out.ToolCalls, ToolCall{
+			ID:    id,
+			Name:  fc.Name,
+			Input: raw,
+		})
+	}
+	return out, nil
 }
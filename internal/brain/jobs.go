@@ -0,0 +1,82 @@
+package brain
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/moorebrett0/pipet/internal/metrics"
+)
+
+// jobTimeout bounds a background job, well past Discord's 15-minute window
+// for interaction followups.
+const jobTimeout = 45 * time.Minute
+
+// Job is a brain request running in the background, for work that may
+// outlast the Discord interaction that started it.
+type Job struct {
+	ID      string
+	Label   string // what it's doing, e.g. "diagnosing the Pi"
+	UserID  string // who asked
+	Started time.Time
+}
+
+// jobBoard tracks the jobs still running.
+type jobBoard struct {
+	mu      sync.Mutex
+	seq     int
+	running []Job
+}
+
+func (j *jobBoard) add(label, userID string) Job {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.seq++
+	job := Job{ID: strconv.Itoa(j.seq), Label: label, UserID: userID, Started: time.Now()}
+	j.running = append(j.running, job)
+	return job
+}
+
+func (j *jobBoard) remove(id string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.running = slices.DeleteFunc(j.running, func(job Job) bool { return job.ID == id })
+}
+
+// Start runs Ask in the background and calls done with the answer when it
+// finishes. ctx's values (user, conversation, progress, ...) carry over but
+// its deadline doesn't; the job gets jobTimeout instead, so the caller can
+// return right away.
+func (b *Brain) Start(ctx context.Context, label, userMessage string, done func(job Job, answer string, err error)) Job {
+	u, _ := userFrom(ctx)
+	job := b.jobs.add(label, u.id)
+	go func() {
+		// Nothing above this goroutine would catch a panic in done, so it
+		// would take the whole bot down
+		defer func() {
+			if p := recover(); p != nil {
+				slog.Error("brain: background job panicked", "job", job.ID, "label", job.Label, "panic", p, "stack", string(debug.Stack()))
+				metrics.Observe("panic.job", 0, fmt.Errorf("panic: %v", p))
+			}
+		}()
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), jobTimeout)
+		defer cancel()
+		answer, err := b.Ask(ctx, userMessage)
+		b.jobs.remove(job.ID)
+		done(job, answer, err)
+	}()
+	return job
+}
+
+// Jobs lists the background jobs still running, oldest first.
+func (b *Brain) Jobs() []Job {
+	b.jobs.mu.Lock()
+	defer b.jobs.mu.Unlock()
+	return slices.Clone(b.jobs.running)
+}
@@ -0,0 +1,87 @@
+package brain
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/moorebrett0/pipet/internal/metrics"
+)
+
+// ErrAsleep is returned while the AI provider hasn't come up yet.
+var ErrAsleep = errors.New("AI provider not connected yet")
+
+// lazyProvider stands in for a provider that failed to start (say, DNS
+// isn't up yet on boot). It keeps retrying in the background and forwards
+// to the real provider once one is built.
+type lazyProvider struct {
+	mu    sync.RWMutex
+	p     Provider
+	ready chan struct{} // closed once p is set
+}
+
+// readyProvider wraps a provider that's already up.
+func readyProvider(p Provider) *lazyProvider {
+	l := &lazyProvider{p: p, ready: make(chan struct{})}
+	close(l.ready)
+	return l
+}
+
+// retryProvider calls build until it succeeds or ctx ends, backing off
+// from 5s up to 5m between attempts.
+func retryProvider(ctx context.Context, build func() (Provider, error)) *lazyProvider {
+	l := &lazyProvider{ready: make(chan struct{})}
+	metrics.SetHealth("brain", false, "provider not connected")
+	go func() {
+		backoff := 5 * time.Second
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			p, err := build()
+			if err != nil {
+				slog.Warn("brain: provider still unavailable", "err", err, "retry_in", min(2*backoff, 5*time.Minute))
+				backoff = min(2*backoff, 5*time.Minute)
+				continue
+			}
+			l.set(p)
+			slog.Info("brain: provider connected")
+			return
+		}
+	}()
+	return l
+}
+
+// set swaps in p, for when the provider comes up or the model changes.
+func (l *lazyProvider) set(p Provider) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.p = p
+	if !l.online() {
+		close(l.ready)
+		metrics.SetHealth("brain", true, "connected")
+	}
+}
+
+func (l *lazyProvider) Send(ctx context.Context, systemPrompt string, history []Message) (*Response, error) {
+	l.mu.RLock()
+	p := l.p
+	l.mu.RUnlock()
+	if p == nil {
+		return nil, ErrAsleep
+	}
+	return p.Send(ctx, systemPrompt, history)
+}
+
+func (l *lazyProvider) online() bool {
+	select {
+	case <-l.ready:
+		return true
+	default:
+		return false
+	}
+}
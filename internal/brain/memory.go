@@ -0,0 +1,125 @@
+package brain
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// memoryStale is how long a conversation can go quiet before the pet stops
+// treating it as the same conversation.
+const memoryStale = 2 * time.Hour
+
+// exchange is one question and the pet's answer.
+type exchange struct {
+	At       time.Time `json:"at"`
+	UserID   string    `json:"user_id,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	Question string    `json:"question"`
+	Answer   string    `json:"answer"`
+}
+
+// memory keeps the last few exchanges in each channel, persisted so a
+// follow-up like "what did you find?" still works after a restart.
+type memory struct {
+	mu    sync.Mutex
+	path  string // "" keeps it in memory only
+	turns int
+	chats map[string][]exchange // channel or thread ID → oldest first
+}
+
+// openMemory loads saved conversations from path. A missing file is an
+// empty memory.
+func openMemory(path string, turns int) (*memory, error) {
+	m := &memory{path: path, turns: turns, chats: make(map[string][]exchange)}
+	if path == "" {
+		return m, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, fmt.Errorf("read memory: %w", err)
+	}
+	if err := json.Unmarshal(data, &m.chats); err != nil {
+		return nil, fmt.Errorf("unmarshal memory: %w", err)
+	}
+	if m.chats == nil {
+		m.chats = make(map[string][]exchange)
+	}
+	return m, nil
+}
+
+// recall returns the recent exchanges in a channel as alternating user and
+// assistant messages, oldest first. Turns by someone other than userID are
+// labelled with who asked.
+func (m *memory) recall(channel, userID string, now time.Time) []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chat := m.chats[channel]
+	if n := len(chat); n == 0 || now.Sub(chat[n-1].At) > memoryStale {
+		return nil
+	}
+	history := make([]Message, 0, 2*len(chat))
+	for _, e := range chat {
+		q := e.Question
+		if e.UserID != userID && e.Name != "" {
+			q = fmt.Sprintf("(%s asked) %s", e.Name, q)
+		}
+		history = append(history,
+			Message{Role: "user", Text: q},
+			Message{Role: "assistant", Text: e.Answer},
+		)
+	}
+	return history
+}
+
+// remember adds an exchange to a channel, dropping the oldest past the
+// window and any left over from a stale conversation, and saves.
+func (m *memory) remember(channel string, e exchange) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	chat := m.chats[channel]
+	if n := len(chat); n > 0 && e.At.Sub(chat[n-1].At) > memoryStale {
+		chat = nil
+	}
+	chat = append(chat, e)
+	if len(chat) > m.turns {
+		chat = chat[len(chat)-m.turns:]
+	}
+	m.chats[channel] = chat
+
+	// Let quiet channels fall out of the file
+	for id, c := range m.chats {
+		if e.At.Sub(c[len(c)-1].At) > memoryStale {
+			delete(m.chats, id)
+		}
+	}
+	m.saveLocked()
+}
+
+// saveLocked writes the conversations atomically. Caller must hold m.mu.
+func (m *memory) saveLocked() {
+	if m.path == "" {
+		return
+	}
+	data, err := json.MarshalIndent(m.chats, "", "  ")
+	if err != nil {
+		slog.Error("brain: marshal memory", "err", err)
+		return
+	}
+	tmp := m.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		slog.Error("brain: write memory", "err", err)
+		return
+	}
+	if err := os.Rename(tmp, m.path); err != nil {
+		slog.Error("brain: rename memory", "err", err)
+	}
+}
@@ -0,0 +1,217 @@
+package brain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+)
+
+// providers in the order auto-detection prefers them.
+var providers = []string{"claude", "gemini", "ollama", "azure", "bedrock"}
+
+// Model is a provider and one of its models, written provider:model.
+type Model struct {
+	Provider string
+	Name     string
+}
+
+func (m Model) String() string {
+	return m.Provider + ":" + m.Name
+}
+
+// ParseModel reads a provider:model string, like "ollama:llama3.1".
+func ParseModel(s string) (Model, error) {
+	provider, name, ok := strings.Cut(strings.TrimSpace(s), ":")
+	if !ok || provider == "" || name == "" {
+		return Model{}, fmt.Errorf("%q isn't provider:model", s)
+	}
+	return Model{Provider: strings.ToLower(provider), Name: name}, nil
+}
+
+// configured reports whether cfg has what provider needs to start.
+func (cfg Config) configured(provider string) bool {
+	switch provider {
+	case "claude":
+		return cfg.ClaudeAPIKey != ""
+	case "gemini":
+		return cfg.GeminiAPIKey != ""
+	case "ollama":
+		return cfg.OllamaURL != ""
+	case "azure":
+		return cfg.AzureEndpoint != "" && cfg.AzureAPIKey != ""
+	case "bedrock":
+		// AWS credentials are often lying around for other reasons, so
+		// Bedrock is only picked with a region in the config or when forced
+		return cfg.BedrockRegion != "" || cfg.Provider == "bedrock"
+	}
+	return false
+}
+
+// picked returns the model cfg selects, auto-detecting the provider from
+// whichever keys are set when none is forced.
+func (cfg Config) picked() Model {
+	m := Model{Provider: cfg.Provider}
+	if m.Provider == "" {
+		for _, p := range providers {
+			if cfg.configured(p) {
+				m.Provider = p
+				break
+			}
+		}
+	}
+	switch m.Provider {
+	case "claude":
+		m.Name = cfg.ClaudeModel
+	case "gemini":
+		m.Name = cfg.GeminiModel
+	case "ollama":
+		m.Name = cfg.OllamaModel
+	case "azure":
+		m.Name = cfg.AzureDeployment
+	case "bedrock":
+		m.Name = cfg.BedrockModel
+	}
+	return m
+}
+
+// withModel returns cfg forced onto m.
+func (cfg Config) withModel(m Model) Config {
+	cfg.Provider = m.Provider
+	switch m.Provider {
+	case "claude":
+		cfg.ClaudeModel = m.Name
+	case "gemini":
+		cfg.GeminiModel = m.Name
+	case "ollama":
+		cfg.OllamaModel = m.Name
+	case "azure":
+		cfg.AzureDeployment = m.Name
+	case "bedrock":
+		cfg.BedrockModel = m.Name
+	}
+	return cfg
+}
+
+// modelChoices lists the models cfg can run: each configured provider's
+// default model, then the extras in cfg.Models whose provider is set up.
+func modelChoices(cfg Config) []Model {
+	var choices []Model
+	for _, p := range providers {
+		c := cfg
+		c.Provider = p
+		if m := c.picked(); cfg.configured(p) && m.Name != "" {
+			choices = append(choices, m)
+		}
+	}
+	for _, s := range cfg.Models {
+		m, err := ParseModel(s)
+		if err != nil {
+			slog.Warn("brain: ignoring model choice", "err", err)
+			continue
+		}
+		if !cfg.configured(m.Provider) {
+			slog.Warn("brain: ignoring model choice, its provider isn't set up", "model", m)
+			continue
+		}
+		if !slices.Contains(choices, m) {
+			choices = append(choices, m)
+		}
+	}
+	return choices
+}
+
+// Models lists what SetModel can switch to.
+func (b *Brain) Models() []Model {
+	return slices.Clone(b.models)
+}
+
+// Model returns the model answering right now.
+func (b *Brain) Model() Model {
+	b.cfgMu.Lock()
+	defer b.cfgMu.Unlock()
+	return b.cfg.picked()
+}
+
+// SetModel switches the brain to m without a restart, once its provider
+// starts, and saves the choice so it's used after a restart too. Requests
+// already running finish on the old model.
+func (b *Brain) SetModel(ctx context.Context, m Model) error {
+	if !slices.Contains(b.models, m) {
+		return fmt.Errorf("%s isn't one of the configured models", m)
+	}
+	b.cfgMu.Lock()
+	cfg := b.cfg.withModel(m)
+	b.cfgMu.Unlock()
+
+	p, err := newProvider(ctx, cfg, b.tools.tools)
+	if err != nil {
+		return err
+	}
+	if p == nil {
+		return fmt.Errorf("%s isn't set up", m.Provider)
+	}
+
+	b.cfgMu.Lock()
+	b.cfg = cfg
+	b.cfgMu.Unlock()
+	b.conn.set(p)
+	slog.Info("brain: switched model", "model", m)
+
+	if err := saveModel(b.modelPath, m); err != nil {
+		slog.Error("brain: couldn't save model choice", "err", err)
+	}
+	return nil
+}
+
+// config returns the config the provider is built from.
+func (b *Brain) config() Config {
+	b.cfgMu.Lock()
+	defer b.cfgMu.Unlock()
+	return b.cfg
+}
+
+// loadModel reads the model saved by SetModel. ok is false if there's none.
+func loadModel(path string) (_ Model, ok bool) {
+	if path == "" {
+		return Model{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("brain: couldn't read saved model", "err", err)
+		}
+		return Model{}, false
+	}
+	var saved struct {
+		Model string `json:"model"`
+	}
+	if err := json.Unmarshal(data, &saved); err != nil {
+		slog.Warn("brain: couldn't read saved model", "err", err)
+		return Model{}, false
+	}
+	m, err := ParseModel(saved.Model)
+	return m, err == nil
+}
+
+// saveModel writes the model choice atomically.
+func saveModel(path string, m Model) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.Marshal(map[string]string{"model": m.String()})
+	if err != nil {
+		return fmt.Errorf("marshal model: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write model: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("rename model: %w", err)
+	}
+	return nil
+}
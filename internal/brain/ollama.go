@@ -0,0 +1,198 @@
+package brain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// errUnreachable wraps failures to reach a self-hosted model server, so Ask
+// can tell the user the brain is offline instead of failing outright.
+var errUnreachable = errors.New("AI server unreachable")
+
+// ollamaProbeTimeout bounds the startup check that the server is up.
+const ollamaProbeTimeout = 10 * time.Second
+
+// ollamaTool is a tool definition in Ollama's (OpenAI-style) format.
+type ollamaTool struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Parameters  any    `json:"parameters"`
+	} `json:"function"`
+}
+
+type ollamaToolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+type ollamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+	ToolName  string           `json:"tool_name,omitempty"`
+}
+
+// ollamaProvider implements Provider against an Ollama server's chat API,
+// for running the brain on a local model with no cloud costs.
+type ollamaProvider struct {
+	client    *http.Client
+	baseURL   string
+	model     string
+	maxTokens int64
+	tools     []ollamaTool
+}
+
+// newOllamaProvider checks that the server at baseURL answers, and warns if
+// it doesn't have the model pulled.
+func newOllamaProvider(ctx context.Context, baseURL, model string, maxTokens int64, tools []Tool) (*ollamaProvider, error) {
+	o := &ollamaProvider{
+		client:    &http.Client{},
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		model:     model,
+		maxTokens: maxTokens,
+	}
+
+	for _, t := range tools {
+		var ot ollamaTool
+		ot.Type = "function"
+		ot.Function.Name = t.Name()
+		ot.Function.Description = t.Description()
+		ot.Function.Parameters = t.JSONSchema()
+		o.tools = append(o.tools, ot)
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, ollamaProbeTimeout)
+	defer cancel()
+	models, err := o.models(probeCtx)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.ContainsFunc(models, func(m string) bool { return m == model || strings.TrimSuffix(m, ":latest") == model }) {
+		slog.Warn("brain: ollama doesn't have the model, pull it on the server", "model", model, "have", models)
+	}
+	return o, nil
+}
+
+// models lists the models the server has pulled.
+func (o *ollamaProvider) models(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.baseURL+"/api/tags", nil)
+	if err != nil {
+		return nil, fmt.Errorf("ollama: %w", err)
+	}
+	var out struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := o.do(req, &out); err != nil {
+		return nil, err
+	}
+	names := make([]string, len(out.Models))
+	for i, m := range out.Models {
+		names[i] = m.Name
+	}
+	return names, nil
+}
+
+func (o *ollamaProvider) Send(ctx context.Context, systemPrompt string, history []Message) (*Response, error) {
+	msgs := []ollamaMessage{{Role: "system", Content: systemPrompt}}
+	for _, m := range history {
+		if len(m.ToolResults) > 0 {
+			for _, tr := range m.ToolResults {
+				content := tr.Content
+				if tr.IsError {
+					content = "Error: " + content
+				}
+				msgs = append(msgs, ollamaMessage{Role: "tool", Content: content, ToolName: tr.ID})
+			}
+			continue
+		}
+
+		om := ollamaMessage{Role: m.Role, Content: m.Text}
+		for _, tc := range m.ToolCalls {
+			var call ollamaToolCall
+			call.Function.Name = tc.Name
+			call.Function.Arguments = tc.Input
+			om.ToolCalls = append(om.ToolCalls, call)
+		}
+		msgs = append(msgs, om)
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"model":    o.model,
+		"messages": msgs,
+		"tools":    o.tools,
+		"stream":   false,
+		"options":  map[string]any{"num_predict": tokenBudget(ctx, o.maxTokens)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ollama: marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("ollama: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp struct {
+		Message         ollamaMessage `json:"message"`
+		PromptEvalCount int64         `json:"prompt_eval_count"`
+		EvalCount       int64         `json:"eval_count"`
+	}
+	if err := o.do(req, &resp); err != nil {
+		return nil, err
+	}
+
+	// Ollama doesn't assign call IDs, so results are matched up by name
+	out := &Response{
+		Text:         resp.Message.Content,
+		Done:         len(resp.Message.ToolCalls) == 0,
+		InputTokens:  resp.PromptEvalCount,
+		OutputTokens: resp.EvalCount,
+	}
+	for _, tc := range resp.Message.ToolCalls {
+		out.ToolCalls = append(out.ToolCalls, ToolCall{
+			ID:    tc.Function.Name,
+			Name:  tc.Function.Name,
+			Input: tc.Function.Arguments,
+		})
+	}
+	return out, nil
+}
+
+// do sends req and decodes the JSON reply into v. Failures to connect are
+// wrapped in errUnreachable.
+func (o *ollamaProvider) do(req *http.Request, v any) error {
+	resp, err := o.client.Do(req)
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("ollama at %s: %w: %v", o.baseURL, errUnreachable, err)
+		}
+		return fmt.Errorf("ollama: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &statusError{service: "ollama", code: resp.StatusCode, status: resp.Status, body: string(bytes.TrimSpace(msg))}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("ollama: decode response: %w", err)
+	}
+	return nil
+}
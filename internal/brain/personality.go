@@ -0,0 +1,59 @@
+package brain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Personality tunes the pet's tone on top of its species personality. The
+// zero value means "not configured" and adds no directives.
+type Personality struct {
+	Sassiness    int    // 0 (sweet) – 10 (maximum attitude); 5 is neutral
+	Verbosity    string // "brief", "normal", or "chatty"
+	Emoji        string // "none", "some", or "lots"
+	StatMentions string // "never", "sometimes", or "often"
+}
+
+// directives turns the knobs into a system-prompt section, or "" if they're
+// all neutral.
+func (p Personality) directives() string {
+	if p == (Personality{}) {
+		return ""
+	}
+	var lines []string
+
+	switch {
+	case p.Sassiness <= 0:
+		lines = append(lines, "Be unfailingly sweet and gentle. No teasing, sarcasm, or snark, even if your species is usually sassy.")
+	case p.Sassiness > 0 && p.Sassiness <= 3:
+		lines = append(lines, "Keep any attitude very mild: gentle, friendly teasing at most. This channel may include kids.")
+	case p.Sassiness >= 8:
+		lines = append(lines, fmt.Sprintf("Turn the attitude up (sassiness %d/10): be cheeky, dramatic, and opinionated, but never mean.", p.Sassiness))
+	}
+
+	switch p.Verbosity {
+	case "brief":
+		lines = append(lines, "Keep replies to a single short sentence whenever you can.")
+	case "chatty":
+		lines = append(lines, "You can be chatty: a short paragraph is fine when you have something to say.")
+	}
+
+	switch p.Emoji {
+	case "none":
+		lines = append(lines, "Don't use emoji in your replies.")
+	case "lots":
+		lines = append(lines, "Use plenty of emoji.")
+	}
+
+	switch p.StatMentions {
+	case "never":
+		lines = append(lines, "Don't mention CPU, memory, disk, or temperature unless someone asks about them or something is wrong.")
+	case "often":
+		lines = append(lines, "Work how the Pi is doing (CPU, memory, temperature) into your replies often.")
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+	return "\n\n## Tone\n- " + strings.Join(lines, "\n- ")
+}
@@ -0,0 +1,50 @@
+package brain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// progressLog builds the running transcript Ask reports through
+// WithProgress: the model's text as it streams, plus a line for each tool
+// it reaches for. A nil progressLog ignores everything.
+type progressLog struct {
+	sb     strings.Builder
+	report func(string)
+}
+
+func newProgressLog(report func(string)) *progressLog {
+	if report == nil {
+		return nil
+	}
+	return &progressLog{report: report}
+}
+
+// text appends streamed model output.
+func (p *progressLog) text(delta string) {
+	if p == nil {
+		return
+	}
+	p.sb.WriteString(delta)
+	p.report(p.sb.String())
+}
+
+// tool notes a tool call, showing the command for run_shell.
+func (p *progressLog) tool(tc ToolCall) {
+	if p == nil {
+		return
+	}
+	if p.sb.Len() > 0 && !strings.HasSuffix(p.sb.String(), "\n") {
+		p.sb.WriteString("\n")
+	}
+	var params struct {
+		Command string `json:"command"`
+	}
+	if tc.Name == "run_shell" && json.Unmarshal(tc.Input, &params) == nil && params.Command != "" {
+		fmt.Fprintf(&p.sb, "> `$ %s`\n", strings.ReplaceAll(params.Command, "`", "'"))
+	} else {
+		fmt.Fprintf(&p.sb, "> *%s*\n", tc.Name)
+	}
+	p.report(p.sb.String())
+}
@@ -5,6 +5,117 @@ import (
 	"encoding/json"
 )
 
+type budgetKey struct{}
+
+// withTokenBudget caps the output tokens for requests made with ctx, below
+// the provider's configured maximum.
+func withTokenBudget(ctx context.Context, maxTokens int64) context.Context {
+	return context.WithValue(ctx, budgetKey{}, maxTokens)
+}
+
+// tokenBudget returns the output token cap for ctx, or def if none was set.
+func tokenBudget(ctx context.Context, def int64) int64 {
+	if n, ok := ctx.Value(budgetKey{}).(int64); ok && n > 0 && n < def {
+		return n
+	}
+	return def
+}
+
+type streamKey struct{}
+
+// withTextStream asks the provider to stream its reply, calling onText with
+// each piece of text as it arrives. Providers that can't stream ignore it
+// and return the whole reply at once.
+func withTextStream(ctx context.Context, onText func(string)) context.Context {
+	return context.WithValue(ctx, streamKey{}, onText)
+}
+
+func streamFrom(ctx context.Context) func(string) {
+	onText, _ := ctx.Value(streamKey{}).(func(string))
+	return onText
+}
+
+type progressKey struct{}
+
+// WithProgress has Ask call progress with everything written so far, text
+// and tool calls, as the answer streams in, so a caller can show the pet
+// thinking. progress is called from Ask's goroutine and must not block.
+func WithProgress(ctx context.Context, progress func(text string)) context.Context {
+	return context.WithValue(ctx, progressKey{}, progress)
+}
+
+func progressFrom(ctx context.Context) func(string) {
+	progress, _ := ctx.Value(progressKey{}).(func(string))
+	return progress
+}
+
+type userKey struct{}
+
+// user identifies who a request is on behalf of.
+type user struct {
+	id, name string
+}
+
+// WithUser marks ctx as a request from a Discord user, so the brain can load
+// and update their preferences.
+func WithUser(ctx context.Context, userID, name string) context.Context {
+	return context.WithValue(ctx, userKey{}, user{id: userID, name: name})
+}
+
+func userFrom(ctx context.Context) (user, bool) {
+	u, ok := ctx.Value(userKey{}).(user)
+	return u, ok && u.id != ""
+}
+
+type ownerKey struct{}
+
+// WithOwner marks ctx as a request from one of the pet's owners, who keep
+// full access after the monthly token cap is reached.
+func WithOwner(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ownerKey{}, true)
+}
+
+func ownerFrom(ctx context.Context) bool {
+	owner, _ := ctx.Value(ownerKey{}).(bool)
+	return owner
+}
+
+type conversationKey struct{}
+
+// WithConversation scopes ctx to one conversation (a channel or thread ID),
+// so its rate limit is kept separate from other conversations.
+func WithConversation(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, conversationKey{}, id)
+}
+
+func conversationFrom(ctx context.Context) string {
+	id, _ := ctx.Value(conversationKey{}).(string)
+	return id
+}
+
+type approverKey struct{}
+
+// FileWrite is a change to a file the model wants to make, which an owner
+// has to approve before anything is written.
+type FileWrite struct {
+	Path    string
+	Content string
+	Append  bool   // add Content to the end instead of replacing the file
+	UserID  string // who the pet was talking to when it asked
+}
+
+// WithApprover lets the write_file and append_file tools hand their changes
+// to approve, which should ask an owner and return once the request is
+// posted, not once it's answered. Without an approver those tools refuse.
+func WithApprover(ctx context.Context, approve func(FileWrite) error) context.Context {
+	return context.WithValue(ctx, approverKey{}, approve)
+}
+
+func approverFrom(ctx context.Context) func(FileWrite) error {
+	approve, _ := ctx.Value(approverKey{}).(func(FileWrite) error)
+	return approve
+}
+
 // Provider abstracts the AI API (Claude, Gemini, etc.).
 type Provider interface {
 	Send(ctx context.Context, systemPrompt string, history []Message) (*Response, error)
@@ -37,4 +148,8 @@ type Response struct {
 	Text      string     // text output (may be empty if tool calls)
 	ToolCalls []ToolCall // non-empty means the model wants to use tools
 	Done      bool       // true if the model is finished (no more tool calls)
+
+	// Tokens the call used, as reported by the provider (0 if unknown)
+	InputTokens  int64
+	OutputTokens int64
 }
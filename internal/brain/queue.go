@@ -0,0 +1,62 @@
+package brain
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/moorebrett0/pipet/internal/metrics"
+)
+
+// ErrQueueFull is returned when too many requests are already waiting for
+// the AI.
+var ErrQueueFull = errors.New("too many requests waiting")
+
+// queuedProvider bounds how many requests hit the AI at once. Each Send
+// takes a worker slot; up to maxWait more wait in line, and anything past
+// that is turned away.
+type queuedProvider struct {
+	Provider
+	slots   chan struct{}
+	waiting atomic.Int32
+	maxWait int32
+}
+
+func newQueuedProvider(p Provider, workers, maxWait int) *queuedProvider {
+	return &queuedProvider{
+		Provider: p,
+		slots:    make(chan struct{}, max(1, workers)),
+		maxWait:  int32(max(0, maxWait)),
+	}
+}
+
+func (q *queuedProvider) Send(ctx context.Context, systemPrompt string, history []Message) (_ *Response, err error) {
+	queued := time.Now()
+	select {
+	case q.slots <- struct{}{}:
+	default:
+		if q.waiting.Add(1) > q.maxWait {
+			q.waiting.Add(-1)
+			return nil, ErrQueueFull
+		}
+		select {
+		case q.slots <- struct{}{}:
+			q.waiting.Add(-1)
+		case <-ctx.Done():
+			q.waiting.Add(-1)
+			return nil, ctx.Err()
+		}
+	}
+	defer func() { <-q.slots }()
+	metrics.Since("brain.queue_wait", queued, nil)
+
+	// Time the provider alone, so model latency shows apart from queueing
+	defer func(start time.Time) { metrics.Since("provider.send", start, err) }(time.Now())
+	return q.Provider.Send(ctx, systemPrompt, history)
+}
+
+// busy reports whether a new request would have to wait.
+func (q *queuedProvider) busy() bool {
+	return len(q.slots) == cap(q.slots)
+}
@@ -0,0 +1,74 @@
+package brain
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+)
+
+// secretPatterns match output that should never reach a public channel,
+// whatever the model was talked into printing: key material, password
+// hashes from /etc/shadow, and API tokens. Each match is replaced with its
+// replacement, which may refer to the pattern's groups.
+var secretPatterns = []struct {
+	kind string
+	re   *regexp.Regexp
+	repl string
+}{
+	// Up to the END line, or the end of the text while it's still streaming
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----[\s\S]*?(-----END [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----|$)`), "[private key redacted]"},
+	{"password hash", regexp.MustCompile(`\$(1|2[abxy]|5|6|7|y|gy)\$[./A-Za-z0-9$=+-]{8,}`), "[password hash redacted]"},
+	{"anthropic key", regexp.MustCompile(`sk-ant-[A-Za-z0-9_-]{20,}`), "[API key redacted]"},
+	{"openai key", regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}`), "[API key redacted]"},
+	{"google key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`), "[API key redacted]"},
+	{"aws key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`), "[AWS key redacted]"},
+	{"github token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`), "[GitHub token redacted]"},
+	{"slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`), "[Slack token redacted]"},
+	{"discord token", regexp.MustCompile(`\b[MNO][A-Za-z0-9_-]{23,25}\.[A-Za-z0-9_-]{6}\.[A-Za-z0-9_-]{27,}`), "[Discord token redacted]"},
+	// key=value and key: value assignments, keeping the name so the answer
+	// still reads; the value stops at quotes so JSON stays intact
+	{"secret assignment", regexp.MustCompile(`(?i)\b((?:api[_-]?key|secret(?:[_-]?access)?[_-]?key|client[_-]?secret|auth[_-]?token|access[_-]?token|password|passwd|token)\\?["']?\s*[:=]\s*\\?["']?)[^\s"'\\]{8,}`), "${1}[redacted]"},
+}
+
+// redact replaces anything matching secretPatterns in s, and returns the
+// kinds it found.
+func redact(s string) (string, []string) {
+	var found []string
+	for _, p := range secretPatterns {
+		if p.re.MatchString(s) {
+			s = p.re.ReplaceAllString(s, p.repl)
+			found = append(found, p.kind)
+		}
+	}
+	return s, found
+}
+
+// redactingProvider runs every answer through redact before Brain hands it
+// on to be posted.
+type redactingProvider struct {
+	Provider
+}
+
+func (r redactingProvider) Send(ctx context.Context, systemPrompt string, history []Message) (*Response, error) {
+	resp, err := r.Provider.Send(ctx, systemPrompt, history)
+	if err != nil || resp.Text == "" {
+		return resp, err
+	}
+	var found []string
+	if resp.Text, found = redact(resp.Text); len(found) > 0 {
+		slog.Warn("brain: redacted sensitive output", "kinds", found)
+	}
+	return resp, nil
+}
+
+// redactingReport wraps a progress report, which shows the answer while it
+// streams and so runs ahead of redactingProvider.
+func redactingReport(report func(string)) func(string) {
+	if report == nil {
+		return nil
+	}
+	return func(text string) {
+		text, _ = redact(text)
+		report(text)
+	}
+}
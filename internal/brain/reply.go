@@ -0,0 +1,93 @@
+package brain
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// Limits on what a structured reply can do.
+const (
+	maxMoodDelta    = 10
+	maxReplyFields  = 10
+	replyFieldBytes = 1024 // Discord's limit per embed field value
+)
+
+// replyContract is added to the system prompt when structured replies are
+// on.
+const replyContract = `
+
+## Reply Format
+When you give your final answer (not while calling tools), reply with only a JSON object, no code fence and nothing around it:
+{"text": "what you say, in character", "mood_delta": 0, "embed": null}
+- mood_delta: how this exchange makes you feel, from -10 (hurt or worried) to 10 (delighted). Usually 0 to 3.
+- embed: only when structured data reads better as a card, e.g. a status report or a list of findings: {"title": "...", "description": "...", "fields": [{"name": "...", "value": "...", "inline": true}]}. Otherwise null.`
+
+// Reply is an answer from AskReply: what to say, and what it does to the pet.
+type Reply struct {
+	Text      string
+	MoodDelta float64     // -10 to 10, for the caller to apply to happiness
+	Embed     *ReplyEmbed // nil unless the model sent a card
+}
+
+// ReplyEmbed is a card the model wants shown under its answer.
+type ReplyEmbed struct {
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	Fields      []ReplyField `json:"fields"`
+}
+
+// ReplyField is one name/value row of a ReplyEmbed.
+type ReplyField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+// AskReply is Ask for callers that can use more than text. With structured
+// replies enabled the model answers in a small JSON envelope, so a reply can
+// move the pet's mood or carry an embed. With them off, or when the answer
+// isn't a valid envelope, the whole answer comes back as Text.
+func (b *Brain) AskReply(ctx context.Context, userMessage string) (Reply, error) {
+	return b.ask(ctx, userMessage, b.structured)
+}
+
+// parseReply reads a reply envelope, tolerating a code fence or stray text
+// around the JSON. ok is false if there's no usable envelope.
+func parseReply(raw string) (_ Reply, ok bool) {
+	start, end := strings.Index(raw, "{"), strings.LastIndex(raw, "}")
+	if start < 0 || end < start {
+		return Reply{}, false
+	}
+	var env struct {
+		Text      string      `json:"text"`
+		MoodDelta float64     `json:"mood_delta"`
+		Embed     *ReplyEmbed `json:"embed"`
+	}
+	if err := json.Unmarshal([]byte(raw[start:end+1]), &env); err != nil || strings.TrimSpace(env.Text) == "" {
+		return Reply{}, false
+	}
+
+	r := Reply{
+		Text:      strings.TrimSpace(env.Text),
+		MoodDelta: max(-maxMoodDelta, min(maxMoodDelta, env.MoodDelta)),
+	}
+	if e := env.Embed; e != nil && (e.Title != "" || e.Description != "" || len(e.Fields) > 0) {
+		var fields []ReplyField
+		for _, f := range e.Fields {
+			if f.Name == "" || f.Value == "" {
+				continue
+			}
+			if len(f.Value) > replyFieldBytes {
+				f.Value = f.Value[:replyFieldBytes-3] + "..."
+			}
+			fields = append(fields, f)
+		}
+		if len(fields) > maxReplyFields {
+			fields = fields[:maxReplyFields]
+		}
+		e.Fields = fields
+		r.Embed = e
+	}
+	return r, true
+}
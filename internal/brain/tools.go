@@ -0,0 +1,368 @@
+package brain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/moorebrett0/pipet/internal/pet"
+	"github.com/moorebrett0/pipet/internal/shell"
+)
+
+// Tool is something the model can call while answering. Providers turn
+// each one into their own tool format; Ask runs them.
+type Tool interface {
+	Name() string
+	Description() string
+	// JSONSchema describes the input object, e.g.
+	// {"type": "object", "properties": {...}, "required": [...]}
+	JSONSchema() map[string]any
+	// Execute runs the tool and returns its output for the model, and
+	// whether that output is an error.
+	Execute(ctx context.Context, input json.RawMessage) (string, bool)
+}
+
+// NewTool builds a Tool from a name, description, input schema properties,
+// the required property names, and the function that runs it.
+func NewTool(name, description string, properties map[string]any, required []string, run func(ctx context.Context, input json.RawMessage) (string, bool)) Tool {
+	return &funcTool{
+		name: name,
+		desc: description,
+		schema: map[string]any{
+			"type":       "object",
+			"properties": properties,
+			"required":   required,
+		},
+		run: run,
+	}
+}
+
+type funcTool struct {
+	name, desc string
+	schema     map[string]any
+	run        func(context.Context, json.RawMessage) (string, bool)
+}
+
+func (t *funcTool) Name() string               { return t.name }
+func (t *funcTool) Description() string        { return t.desc }
+func (t *funcTool) JSONSchema() map[string]any { return t.schema }
+func (t *funcTool) Execute(ctx context.Context, input json.RawMessage) (string, bool) {
+	return t.run(ctx, input)
+}
+
+// registry holds the tools offered to the model, in the order they were
+// added.
+type registry struct {
+	tools  []Tool
+	byName map[string]Tool
+}
+
+func newRegistry() *registry {
+	return &registry{byName: make(map[string]Tool)}
+}
+
+// add registers a tool. A later tool with the same name replaces an
+// earlier one, so configured tools can override the built-ins.
+func (r *registry) add(tools ...Tool) {
+	for _, t := range tools {
+		if _, ok := r.byName[t.Name()]; ok {
+			for i, old := range r.tools {
+				if old.Name() == t.Name() {
+					r.tools[i] = t
+				}
+			}
+		} else {
+			r.tools = append(r.tools, t)
+		}
+		r.byName[t.Name()] = t
+	}
+}
+
+// only drops every tool not named in enabled, and returns the names it
+// dropped.
+func (r *registry) only(enabled []string) (off []string) {
+	kept := r.tools[:0]
+	for _, t := range r.tools {
+		if slices.Contains(enabled, t.Name()) {
+			kept = append(kept, t)
+			continue
+		}
+		off = append(off, t.Name())
+		delete(r.byName, t.Name())
+	}
+	r.tools = kept
+	return off
+}
+
+func (r *registry) lookup(name string) (Tool, bool) {
+	t, ok := r.byName[name]
+	return t, ok
+}
+
+// stringProp is a schema property for a string input.
+func stringProp(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+// builtinTools returns the tools the brain always has, plus run_python and
+// search_docs when they're configured.
+func (b *Brain) builtinTools() []Tool {
+	tools := []Tool{
+		NewTool("run_shell", runShellDesc,
+			map[string]any{"command": stringProp("The shell command to execute")},
+			[]string{"command"}, b.runShell),
+		NewTool("set_preference", setPreferenceDesc,
+			map[string]any{
+				"key":   map[string]any{"type": "string", "enum": pet.PreferenceKeys, "description": "Which preference to set"},
+				"value": stringProp(setPreferenceValueDesc),
+			},
+			[]string{"key", "value"}, b.setPreference),
+		NewTool("remember", rememberDesc,
+			map[string]any{"fact": stringProp(rememberFactDesc)},
+			[]string{"fact"}, b.rememberFact),
+		NewTool("forget", forgetDesc,
+			map[string]any{"id": map[string]any{"type": "integer", "description": "The fact's number, from the list of things you remember"}},
+			[]string{"id"}, b.forgetFact),
+		NewTool("write_file", writeFileDesc,
+			map[string]any{
+				"path":    stringProp(filePathDesc),
+				"content": stringProp("The file's complete new contents"),
+			},
+			[]string{"path", "content"}, b.writeFile(false)),
+		NewTool("append_file", appendFileDesc,
+			map[string]any{
+				"path":    stringProp(filePathDesc),
+				"content": stringProp("The text to add, usually ending in a newline"),
+			},
+			[]string{"path", "content"}, b.writeFile(true)),
+	}
+	if b.python != nil {
+		tools = append(tools, NewTool("run_python", runPythonDesc,
+			map[string]any{"code": stringProp("The Python 3 script to run; print what you want to see")},
+			[]string{"code"}, b.runPython))
+	}
+	if len(b.services) > 0 {
+		tools = append(tools, NewTool("manage_service", manageServiceDesc,
+			map[string]any{
+				"unit":   map[string]any{"type": "string", "enum": b.services, "description": "Which service"},
+				"action": map[string]any{"type": "string", "enum": shell.ServiceActions, "description": "What to do"},
+			},
+			[]string{"unit", "action"}, b.manageService))
+	}
+	if b.runtime != "" {
+		tools = append(tools, NewTool("containers", containersDesc,
+			map[string]any{
+				"action": map[string]any{"type": "string", "enum": shell.ContainerActions, "description": "What to do"},
+				"name":   stringProp("The container's name, for logs and restart"),
+				"lines":  map[string]any{"type": "integer", "description": "How many log lines to show (default 50, max 500)"},
+			},
+			[]string{"action"}, b.containers))
+	}
+	if b.docs != nil {
+		tools = append(tools, NewTool("search_docs", searchDocsDesc,
+			map[string]any{"query": stringProp("Keywords to look for, e.g. a service name, device, or symptom")},
+			[]string{"query"}, b.searchDocs))
+	}
+	return tools
+}
+
+func (b *Brain) runShell(ctx context.Context, input json.RawMessage) (string, bool) {
+	var params struct {
+		Command string `json:"command"`
+	}
+	if err := json.Unmarshal(input, &params); err != nil {
+		return fmt.Sprintf("invalid input: %v", err), true
+	}
+
+	slog.Info("brain: executing shell command", "command", params.Command)
+	output, err := b.executor.Run(ctx, params.Command)
+	output = b.condense(ctx, params.Command, output)
+	if err != nil {
+		return fmt.Sprintf("Error: %v\nOutput: %s", err, output), true
+	}
+	return output, false
+}
+
+func (b *Brain) runPython(ctx context.Context, input json.RawMessage) (string, bool) {
+	var params struct {
+		Code string `json:"code"`
+	}
+	if err := json.Unmarshal(input, &params); err != nil {
+		return fmt.Sprintf("invalid input: %v", err), true
+	}
+
+	slog.Info("brain: running python", "bytes", len(params.Code))
+	output, err := b.python.Run(ctx, params.Code)
+	output = b.condense(ctx, "a python script", output)
+	if err != nil {
+		return fmt.Sprintf("Error: %v\nOutput: %s", err, output), true
+	}
+	return output, false
+}
+
+func (b *Brain) manageService(ctx context.Context, input json.RawMessage) (string, bool) {
+	var params struct {
+		Unit   string `json:"unit"`
+		Action string `json:"action"`
+	}
+	if err := json.Unmarshal(input, &params); err != nil {
+		return fmt.Sprintf("invalid input: %v", err), true
+	}
+	if !slices.Contains(b.services, params.Unit) {
+		return fmt.Sprintf("%s isn't a service you're allowed to manage", params.Unit), true
+	}
+	if params.Action != "status" && !ownerFrom(ctx) {
+		return "only your owner can have you change a service; you can still check its status", true
+	}
+
+	slog.Info("brain: managing service", "unit", params.Unit, "action", params.Action)
+	output, err := b.executor.Service(ctx, params.Unit, params.Action)
+	if params.Action != "status" && err == nil {
+		b.petState.LogEvent(fmt.Sprintf("was asked to %s %s", params.Action, params.Unit))
+	}
+	output = b.condense(ctx, "systemctl "+params.Action+" "+params.Unit, output)
+	if err != nil {
+		return fmt.Sprintf("Error: %v\nOutput: %s", err, output), true
+	}
+	return output, false
+}
+
+func (b *Brain) containers(ctx context.Context, input json.RawMessage) (string, bool) {
+	var params struct {
+		Action string `json:"action"`
+		Name   string `json:"name"`
+		Lines  int    `json:"lines"`
+	}
+	if err := json.Unmarshal(input, &params); err != nil {
+		return fmt.Sprintf("invalid input: %v", err), true
+	}
+	if params.Action == "restart" {
+		if !slices.Contains(b.restarts, params.Name) {
+			return fmt.Sprintf("%s isn't a container you're allowed to restart", params.Name), true
+		}
+		if !ownerFrom(ctx) {
+			return "only your owner can have you restart a container; you can still list them and read logs", true
+		}
+	}
+
+	slog.Info("brain: containers", "action", params.Action, "name", params.Name)
+	output, err := b.executor.Container(ctx, params.Action, params.Name, params.Lines)
+	if params.Action == "restart" && err == nil {
+		b.petState.LogEvent("was asked to restart the " + params.Name + " container")
+	}
+	output = b.condense(ctx, b.runtime+" "+params.Action+" "+params.Name, output)
+	if err != nil {
+		return fmt.Sprintf("Error: %v\nOutput: %s", err, output), true
+	}
+	return output, false
+}
+
+func (b *Brain) searchDocs(ctx context.Context, input json.RawMessage) (string, bool) {
+	var params struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(input, &params); err != nil {
+		return fmt.Sprintf("invalid input: %v", err), true
+	}
+
+	hits := b.docs.Search(params.Query, maxDocHits)
+	if len(hits) == 0 {
+		return "nothing in the docs about that", false
+	}
+	var sb strings.Builder
+	for _, h := range hits {
+		fmt.Fprintf(&sb, "--- %s", h.File)
+		if h.Heading != "" {
+			fmt.Fprintf(&sb, " (%s)", h.Heading)
+		}
+		fmt.Fprintf(&sb, "\n%s\n", h.Text)
+	}
+	return sb.String(), false
+}
+
+func (b *Brain) setPreference(ctx context.Context, input json.RawMessage) (string, bool) {
+	var params struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(input, &params); err != nil {
+		return fmt.Sprintf("invalid input: %v", err), true
+	}
+	u, ok := userFrom(ctx)
+	if !ok {
+		return "don't know who you're talking to", true
+	}
+	if err := b.petState.SetPreference(u.id, params.Key, params.Value); err != nil {
+		return err.Error(), true
+	}
+	slog.Info("brain: set preference", "user", u.id, "key", params.Key)
+	return "saved", false
+}
+
+func (b *Brain) rememberFact(ctx context.Context, input json.RawMessage) (string, bool) {
+	var params struct {
+		Fact string `json:"fact"`
+	}
+	if err := json.Unmarshal(input, &params); err != nil {
+		return fmt.Sprintf("invalid input: %v", err), true
+	}
+	// Facts go into every system prompt, so only the owner gets to add them
+	if !ownerFrom(ctx) {
+		return "only your owner can have you remember something for good", true
+	}
+	u, _ := userFrom(ctx)
+	f, err := b.petState.Remember(params.Fact, u.id)
+	if err != nil {
+		return err.Error(), true
+	}
+	slog.Info("brain: remembered", "id", f.ID, "user", u.id)
+	return fmt.Sprintf("remembered as fact %d", f.ID), false
+}
+
+func (b *Brain) forgetFact(ctx context.Context, input json.RawMessage) (string, bool) {
+	var params struct {
+		ID int `json:"id"`
+	}
+	if err := json.Unmarshal(input, &params); err != nil {
+		return fmt.Sprintf("invalid input: %v", err), true
+	}
+	if !ownerFrom(ctx) {
+		return "only your owner can have you forget something", true
+	}
+	if _, ok := b.petState.Forget(params.ID); !ok {
+		return fmt.Sprintf("no fact %d", params.ID), true
+	}
+	slog.Info("brain: forgot", "id", params.ID)
+	return "forgotten", false
+}
+
+// writeFile returns the write_file (or append_file) tool's handler, which
+// passes the change to ctx's approver rather than writing it.
+func (b *Brain) writeFile(appendTo bool) func(context.Context, json.RawMessage) (string, bool) {
+	return func(ctx context.Context, input json.RawMessage) (string, bool) {
+		var params struct {
+			Path    string `json:"path"`
+			Content string `json:"content"`
+		}
+		if err := json.Unmarshal(input, &params); err != nil {
+			return fmt.Sprintf("invalid input: %v", err), true
+		}
+		approve := approverFrom(ctx)
+		if approve == nil {
+			return "can't ask for approval here, so files can't be written", true
+		}
+		if !filepath.IsAbs(params.Path) {
+			return "path must be absolute", true
+		}
+		u, _ := userFrom(ctx)
+		if err := approve(FileWrite{Path: filepath.Clean(params.Path), Content: params.Content, Append: appendTo, UserID: u.id}); err != nil {
+			return err.Error(), true
+		}
+		slog.Info("brain: file write awaiting approval", "path", params.Path, "append", appendTo, "bytes", len(params.Content))
+		return "asked your owner to approve the change; nothing is written until they do", false
+	}
+}
@@ -0,0 +1,159 @@
+package brain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// How much usage history is kept on disk.
+const (
+	usageDays   = 31
+	usageMonths = 12
+)
+
+// Tokens is AI usage over some period.
+type Tokens struct {
+	Input  int64 `json:"input"`
+	Output int64 `json:"output"`
+	Calls  int   `json:"calls"`
+}
+
+// Total is input plus output tokens.
+func (t Tokens) Total() int64 {
+	return t.Input + t.Output
+}
+
+// Usage is the pet's AI spend so far, for /budget.
+type Usage struct {
+	Today Tokens
+	Month Tokens
+	Cap   int64 // monthly token cap, 0 = none
+
+	// Price per million tokens, for estimating spend
+	InputPrice  float64
+	OutputPrice float64
+}
+
+// Cost estimates what t cost in dollars at the configured prices.
+func (u Usage) Cost(t Tokens) float64 {
+	return (float64(t.Input)*u.InputPrice + float64(t.Output)*u.OutputPrice) / 1e6
+}
+
+// ledger tallies token usage by day and month, persisted so totals survive
+// a restart.
+type ledger struct {
+	mu     sync.Mutex
+	path   string            // "" keeps totals in memory only
+	Days   map[string]Tokens `json:"days"`   // YYYY-MM-DD, local time
+	Months map[string]Tokens `json:"months"` // YYYY-MM
+}
+
+// openLedger loads saved totals from path. A missing file starts from zero.
+func openLedger(path string) (*ledger, error) {
+	l := &ledger{path: path, Days: make(map[string]Tokens), Months: make(map[string]Tokens)}
+	if path == "" {
+		return l, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return l, nil
+		}
+		return nil, fmt.Errorf("read usage: %w", err)
+	}
+	if err := json.Unmarshal(data, l); err != nil {
+		return nil, fmt.Errorf("unmarshal usage: %w", err)
+	}
+	if l.Days == nil {
+		l.Days = make(map[string]Tokens)
+	}
+	if l.Months == nil {
+		l.Months = make(map[string]Tokens)
+	}
+	return l, nil
+}
+
+// add counts one provider call and saves.
+func (l *ledger) add(now time.Time, in, out int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, p := range []struct {
+		m    map[string]Tokens
+		key  string
+		keep int
+	}{
+		{l.Days, now.Format(time.DateOnly), usageDays},
+		{l.Months, now.Format("2006-01"), usageMonths},
+	} {
+		t := p.m[p.key]
+		t.Input += in
+		t.Output += out
+		t.Calls++
+		p.m[p.key] = t
+		trim(p.m, p.keep)
+	}
+	l.saveLocked()
+}
+
+// totals returns today's and this month's usage.
+func (l *ledger) totals(now time.Time) (day, month Tokens) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.Days[now.Format(time.DateOnly)], l.Months[now.Format("2006-01")]
+}
+
+// trim drops the oldest periods past keep. Keys sort by date.
+func trim(m map[string]Tokens, keep int) {
+	if len(m) <= keep {
+		return
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys[:len(keys)-keep] {
+		delete(m, k)
+	}
+}
+
+// saveLocked writes the totals atomically. Caller must hold l.mu.
+func (l *ledger) saveLocked() {
+	if l.path == "" {
+		return
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		slog.Error("brain: marshal usage", "err", err)
+		return
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		slog.Error("brain: write usage", "err", err)
+		return
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		slog.Error("brain: rename usage", "err", err)
+	}
+}
+
+// meteredProvider records the tokens every call uses.
+type meteredProvider struct {
+	Provider
+	ledger *ledger
+}
+
+func (m *meteredProvider) Send(ctx context.Context, systemPrompt string, history []Message) (*Response, error) {
+	resp, err := m.Provider.Send(ctx, systemPrompt, history)
+	if err == nil && resp.InputTokens+resp.OutputTokens > 0 {
+		m.ledger.add(time.Now(), resp.InputTokens, resp.OutputTokens)
+	}
+	return resp, err
+}
@@ -0,0 +1,142 @@
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// Runner runs a shell command, e.g. *shell.Executor.
+type Runner interface {
+	Run(ctx context.Context, command string) (string, error)
+}
+
+// Category is one kind of reclaimable space found by Scan.
+type Category struct {
+	ID    string   // "apt", "journal", "tmp", "packages", or "large"
+	Label string   // e.g. "apt package cache"
+	Bytes int64    // estimated space freed by cleaning it
+	Items []string // what would go: packages for "packages", files for "large"
+	Done  bool     // cleaned since the scan
+}
+
+// Large files are anything over largeFileMB in the configured paths, biggest
+// first, at most maxLargeFiles of them.
+const (
+	largeFileMB   = 100
+	maxLargeFiles = 10
+)
+
+// journalKeep is how much journal the vacuum leaves behind.
+const journalKeep = 50 << 20
+
+// tmpAgeDays is how old a file in /tmp or /var/tmp must be to be cleaned.
+const tmpAgeDays = 7
+
+// Scan measures each category of reclaimable space, looking for large files
+// under paths. Categories with nothing to reclaim, or that couldn't be
+// measured, are left out.
+func Scan(ctx context.Context, r Runner, paths []string) []Category {
+	var out []Category
+	add := func(c Category) {
+		if c.Bytes > 0 {
+			out = append(out, c)
+		}
+	}
+
+	add(Category{ID: "apt", Label: "apt package cache",
+		Bytes: sum(ctx, r, "du -sb /var/cache/apt/archives 2>/dev/null | cut -f1")})
+
+	if n := sum(ctx, r, "du -sb /var/log/journal 2>/dev/null | cut -f1"); n > journalKeep {
+		add(Category{ID: "journal", Label: "old journal logs", Bytes: n - journalKeep})
+	}
+
+	add(Category{ID: "tmp", Label: fmt.Sprintf("temp files older than %d days", tmpAgeDays),
+		Bytes: sum(ctx, r, fmt.Sprintf("find /tmp /var/tmp -xdev -type f -mtime +%d -printf '%%s\\n' 2>/dev/null | awk '{s+=$1} END {print s+0}'", tmpAgeDays))})
+
+	pkgs := lines(ctx, r, "apt-get -s autoremove 2>/dev/null | awk '/^Remv/ {print $2}'")
+	if len(pkgs) > 0 {
+		// dpkg reports Installed-Size in KiB
+		kb := sum(ctx, r, "dpkg-query -W -f='${Installed-Size}\\n' "+quoteAll(pkgs)+" 2>/dev/null | awk '{s+=$1} END {print s+0}'")
+		add(Category{ID: "packages", Label: "old kernels and unused packages", Bytes: kb << 10, Items: pkgs})
+	}
+
+	if len(paths) > 0 {
+		var large []string
+		var total int64
+		cmd := fmt.Sprintf("find %s -xdev -type f -size +%dM -printf '%%s\\t%%p\\n' 2>/dev/null | sort -rn | head -%d",
+			quoteAll(paths), largeFileMB, maxLargeFiles)
+		for _, line := range lines(ctx, r, cmd) {
+			size, path, ok := strings.Cut(line, "\t")
+			n, err := strconv.ParseInt(size, 10, 64)
+			if !ok || err != nil {
+				continue
+			}
+			large = append(large, path)
+			total += n
+		}
+		add(Category{ID: "large", Label: fmt.Sprintf("files over %dMB", largeFileMB), Bytes: total, Items: large})
+	}
+	return out
+}
+
+// Clean frees the space in one category and returns the command's output.
+func Clean(ctx context.Context, r Runner, c Category) (string, error) {
+	var cmd string
+	switch c.ID {
+	case "apt":
+		cmd = "sudo apt-get clean"
+	case "journal":
+		cmd = fmt.Sprintf("sudo journalctl --vacuum-size=%dM", journalKeep>>20)
+	case "tmp":
+		cmd = fmt.Sprintf("sudo find /tmp /var/tmp -xdev -type f -mtime +%d -delete", tmpAgeDays)
+	case "packages":
+		cmd = "sudo apt-get autoremove -y"
+	case "large":
+		if len(c.Items) == 0 {
+			return "", nil
+		}
+		cmd = "rm -f -- " + quoteAll(c.Items)
+	default:
+		return "", fmt.Errorf("unknown cleanup category %q", c.ID)
+	}
+	return r.Run(ctx, cmd)
+}
+
+// sum runs a command that prints a single number, returning 0 if it fails.
+func sum(ctx context.Context, r Runner, cmd string) int64 {
+	out, err := r.Run(ctx, cmd)
+	if err != nil {
+		slog.Debug("cleanup: measuring failed", "cmd", cmd, "err", err)
+		return 0
+	}
+	n, _ := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+	return n
+}
+
+// lines runs a command and returns its non-empty output lines.
+func lines(ctx context.Context, r Runner, cmd string) []string {
+	out, err := r.Run(ctx, cmd)
+	if err != nil {
+		slog.Debug("cleanup: scan failed", "cmd", cmd, "err", err)
+		return nil
+	}
+	var res []string
+	for _, l := range strings.Split(out, "\n") {
+		if l = strings.TrimSpace(l); l != "" && !strings.HasPrefix(l, "...") {
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
+// quoteAll single-quotes each argument for sh.
+func quoteAll(args []string) string {
+	q := make([]string, len(args))
+	for i, a := range args {
+		q[i] = "'" + strings.ReplaceAll(a, "'", `'\''`) + "'"
+	}
+	return strings.Join(q, " ")
+}
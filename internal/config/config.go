@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -15,14 +16,57 @@ type Config struct {
 	AI        AIConfig        `yaml:"ai"`
 	Claude    ClaudeConfig    `yaml:"claude"`
 	Gemini    GeminiConfig    `yaml:"gemini"`
+	Ollama    OllamaConfig    `yaml:"ollama"`
+	Azure     AzureConfig     `yaml:"azure"`
+	Bedrock   BedrockConfig   `yaml:"bedrock"`
 	Pet       PetConfig       `yaml:"pet"`
+	Species   SpeciesConfig   `yaml:"species"`
 	Monitor   MonitorConfig   `yaml:"monitor"`
 	Shell     ShellConfig     `yaml:"shell"`
+	Tools     ToolsConfig     `yaml:"tools"`
+	Nest      NestConfig      `yaml:"nest"`
 	Proactive ProactiveConfig `yaml:"proactive"`
+
+	// Extra pets hosted by this process. When set, the top-level discord
+	// section is only a template and each instance runs as its own pet.
+	Instances []InstanceConfig `yaml:"instances"`
+}
+
+// InstanceConfig is one independent pet in a multi-pet process, with its
+// own Discord bot, files, and AI budget. Unset fields are inherited from the
+// top-level config; the system monitor and metrics server are shared.
+type InstanceConfig struct {
+	Name      string   `yaml:"name"` // unique; also the default state_dir
+	BotToken  string   `yaml:"bot_token"`
+	ChannelID string   `yaml:"channel_id"`
+	OwnerIDs  []string `yaml:"owner_ids"`
+	// Channels besides channel_id, as in discord.channels (not inherited)
+	Channels []ChannelConfig `yaml:"channels"`
+	// Directory for this pet's state, memorial, schedule, outbox, memory,
+	// usage, and model files
+	StateDir string `yaml:"state_dir"`
+	// AI budget (0 = inherit from claude)
+	MaxTokens   int64 `yaml:"max_tokens"`
+	RateLimit   int   `yaml:"rate_limit"`
+	Concurrency int   `yaml:"concurrency"`
 }
 
 type AIConfig struct {
-	Provider string `yaml:"provider"` // "claude", "gemini", or "" (auto-detect)
+	Provider string `yaml:"provider"` // "claude", "gemini", "ollama", "azure", "bedrock", or "" (auto-detect)
+	// More provider:model choices for /model, beyond each provider's model
+	Models  []string `yaml:"models"`
+	DocsDir string   `yaml:"docs_dir"` // notes about the setup for search_docs
+	// Recent exchanges per channel the pet remembers (0 = none)
+	MemoryTurns int `yaml:"memory_turns"`
+	// Ask for chat answers as JSON (text, mood change, optional embed)
+	StructuredReplies bool `yaml:"structured_replies"`
+	// Scrub private keys, password hashes, and API tokens from AI output
+	RedactSecrets bool `yaml:"redact_secrets"`
+	// Past this many tokens a month, only owners get AI answers (0 = no cap)
+	MonthlyTokenCap int64 `yaml:"monthly_token_cap"`
+	// Dollars per million tokens, for /budget's spend estimate
+	InputPrice  float64 `yaml:"input_price"`
+	OutputPrice float64 `yaml:"output_price"`
 }
 
 type DiscordConfig struct {
@@ -31,6 +75,52 @@ type DiscordConfig struct {
 	OwnerIDs          []string `yaml:"owner_ids"`
 	AllowSpectatorPet bool     `yaml:"allow_spectator_pet"`
 	UseThreads        bool     `yaml:"use_threads"`
+	MoodTopic         bool     `yaml:"mood_topic"`
+	StatusChannelID   string   `yaml:"status_channel_id"`
+	MoodAvatar        bool     `yaml:"mood_avatar"`
+	// Caretaker roles (feeder, groomer, medic) → Discord user IDs
+	Roles map[string][]string `yaml:"roles"`
+	// More channels the pet talks in; channel_id stays its home, where
+	// proactive messages go. An entry for channel_id changes the home rules.
+	Channels []ChannelConfig `yaml:"channels"`
+	// Other servers the pet appears in. When set, slash commands are
+	// registered per server (these and the home channel's) instead of
+	// globally.
+	Guilds []GuildConfig `yaml:"guilds"`
+	// Register slash commands in the home server even without guilds, and
+	// optionally remove them again on shutdown
+	GuildCommands   bool `yaml:"guild_commands"`
+	CleanupCommands bool `yaml:"cleanup_commands"`
+	// Minimum time between one person's commands in a server (0 = none)
+	CommandCooldown time.Duration `yaml:"command_cooldown"`
+	// Minimum time between one spectator's uses of a command, by name
+	CommandCooldowns map[string]time.Duration `yaml:"command_cooldowns"`
+	// Let owners talk to the pet in direct messages
+	OwnerDMs bool `yaml:"owner_dms"`
+	// How long Discord has to be unreachable before the pet remarks on it
+	// once it's back (0 = never)
+	BlackoutNotice time.Duration `yaml:"blackout_notice"`
+	// Move each @mention conversation into its own thread
+	ConversationThreads bool `yaml:"conversation_threads"`
+	// Translations: every <locale>.yaml in locales_dir is registered for
+	// slash commands; locale picks the one the pet's messages use ("" = English)
+	Locale     string `yaml:"locale"`
+	LocalesDir string `yaml:"locales_dir"`
+}
+
+// GuildConfig is another server the pet appears in, and its channel there.
+type GuildConfig struct {
+	ID              string        `yaml:"id"`
+	ChannelID       string        `yaml:"channel_id"`
+	CommandCooldown time.Duration `yaml:"command_cooldown"` // 0 = discord.command_cooldown
+}
+
+// ChannelConfig is a channel the pet listens in and how it behaves there.
+type ChannelConfig struct {
+	ID           string `yaml:"id"`
+	MentionsOnly bool   `yaml:"mentions_only"` // no replies to "good pet", "hi", "feed" without an @mention
+	PetChat      bool   `yaml:"pet_chat"`      // banter with other pets' bots
+	OwnersOnly   bool   `yaml:"owners_only"`   // ignore everyone but owners
 }
 
 type ClaudeConfig struct {
@@ -38,9 +128,14 @@ type ClaudeConfig struct {
 	Model     string `yaml:"model"`
 	MaxTokens int64  `yaml:"max_tokens"`
 	MaxTools  int    `yaml:"max_tool_iterations"`
+	// Have the model summarize tool output longer than this (bytes, 0 = off)
+	SummarizeToolOutputOver int `yaml:"summarize_tool_output_over"`
 	// Sliding window rate limiter
 	RateLimit  int           `yaml:"rate_limit"`
 	RateWindow time.Duration `yaml:"rate_window"`
+	// Worker queue for AI requests
+	Concurrency int `yaml:"concurrency"`
+	MaxQueue    int `yaml:"max_queue"`
 }
 
 type GeminiConfig struct {
@@ -48,18 +143,97 @@ type GeminiConfig struct {
 	Model  string `yaml:"model"`
 }
 
+type OllamaConfig struct {
+	BaseURL string `yaml:"base_url"` // e.g. http://192.168.1.50:11434 ("" = off)
+	Model   string `yaml:"model"`
+}
+
+type AzureConfig struct {
+	Endpoint   string `yaml:"endpoint"` // e.g. https://my-resource.openai.azure.com ("" = off)
+	APIKey     string `yaml:"api_key"`
+	Deployment string `yaml:"deployment"`
+	APIVersion string `yaml:"api_version"`
+}
+
+type BedrockConfig struct {
+	Region string `yaml:"region"` // setting it turns Bedrock on; with ai.provider "bedrock", "" = AWS_REGION
+	Model  string `yaml:"model"`
+}
+
 type PetConfig struct {
 	StatePath    string        `yaml:"state_path"`
+	StateBackend string        `yaml:"state_backend"` // json or sqlite
+	StateDBPath  string        `yaml:"state_db_path"` // the sqlite backend's database
+	MemorialPath string        `yaml:"memorial_path"`
+	SchedulePath string        `yaml:"schedule_path"`
+	OutboxPath   string        `yaml:"outbox_path"`
+	MemoryPath   string        `yaml:"memory_path"`
+	UsagePath    string        `yaml:"usage_path"`
+	ModelPath    string        `yaml:"model_path"`
+	HistoryPath  string        `yaml:"history_path"`
 	SaveInterval time.Duration `yaml:"save_interval"`
+	SaveDebounce time.Duration `yaml:"save_debounce"` // max delay before a change is saved
+
+	// Stat history for /status trends and charts and the daily digest: one
+	// sample per HistoryInterval, kept for HistoryRetention
+	HistoryInterval  time.Duration `yaml:"history_interval"`
+	HistoryRetention time.Duration `yaml:"history_retention"`
+
+	Personality PersonalityConfig `yaml:"personality"`
+}
+
+// PersonalityConfig tones the pet up or down without a custom prompt.
+type PersonalityConfig struct {
+	Sassiness    int    `yaml:"sassiness"`     // 0–10, 5 is the species' usual self
+	Verbosity    string `yaml:"verbosity"`     // brief, normal, chatty
+	Emoji        string `yaml:"emoji"`         // none, some, lots
+	StatMentions string `yaml:"stat_mentions"` // never, sometimes, often
+}
+
+type SpeciesConfig struct {
+	PacksDir   string `yaml:"packs_dir"`
+	OnConflict string `yaml:"on_conflict"` // "skip", "override", or "prefix"
 }
 
 type MonitorConfig struct {
 	Interval time.Duration `yaml:"interval"`
+	// Serve latency/error metrics at http://<addr>/metrics ("" = off)
+	MetricsAddr string `yaml:"metrics_addr"`
+	// Required as ?token= on the feeds served next to /metrics ("" = open)
+	FeedToken string `yaml:"feed_token"`
+	// Pinged every check cycle for external uptime monitoring ("" = off)
+	HeartbeatURL string `yaml:"heartbeat_url"`
 }
 
 type ShellConfig struct {
 	Timeout        time.Duration `yaml:"timeout"`
 	MaxOutputBytes int           `yaml:"max_output_bytes"`
+	// Sandboxed run_python tool for the AI
+	Python         bool          `yaml:"python"`
+	PythonTimeout  time.Duration `yaml:"python_timeout"`
+	PythonMemoryMB int           `yaml:"python_memory_mb"`
+	// systemd units owners may tail with /logs, and control with /service
+	LogUnits     []string `yaml:"log_units"`
+	ServiceUnits []string `yaml:"service_units"`
+	// Docker/Podman containers the AI may restart (it can list and read
+	// logs of any)
+	Containers []string `yaml:"containers"`
+	// Where /feed's cleanup preview looks for large files
+	CleanupPaths []string `yaml:"cleanup_paths"`
+}
+
+// ToolsConfig limits which tools the AI is offered.
+type ToolsConfig struct {
+	// Tool names to offer; unset offers every tool, and [] none at all
+	// (conversation only)
+	Enabled []string `yaml:"enabled"`
+}
+
+// NestConfig sets up /nest backups of important config files.
+type NestConfig struct {
+	Paths []string `yaml:"paths"`
+	Dest  string   `yaml:"dest"` // directory or rclone "remote:path" ("" = off)
+	Keep  int      `yaml:"keep"` // newest nests kept
 }
 
 type ProactiveConfig struct {
@@ -68,6 +242,26 @@ type ProactiveConfig struct {
 	MorningHour      int           `yaml:"morning_hour"`
 	BoredomMinutes   int           `yaml:"boredom_minutes"`
 	DistressCooldown time.Duration `yaml:"distress_cooldown"`
+	Contests         bool          `yaml:"contests"`
+	ContestWeekday   int           `yaml:"contest_weekday"` // 0=Sunday
+	ContestHour      int           `yaml:"contest_hour"`
+	Quests           bool          `yaml:"quests"`
+	QuestInterval    time.Duration `yaml:"quest_interval"`
+	Polls            bool          `yaml:"polls"`
+	PollInterval     time.Duration `yaml:"poll_interval"`
+	PollDuration     time.Duration `yaml:"poll_duration"`
+	Diary            bool          `yaml:"diary"`
+	DiaryHour        int           `yaml:"diary_hour"`
+	DiaryMaxTokens   int64         `yaml:"diary_max_tokens"`
+	DiaryThread      bool          `yaml:"diary_thread"`
+	LogSummary       bool          `yaml:"log_summary"`
+	LogSummaryHour   int           `yaml:"log_summary_hour"`
+	Digest           bool          `yaml:"digest"`
+	DigestHour       int           `yaml:"digest_hour"`
+	Postmortems      bool          `yaml:"postmortems"`
+	Dreams           bool          `yaml:"dreams"`
+	DreamChance      float64       `yaml:"dream_chance"`
+	DreamMaxTokens   int64         `yaml:"dream_max_tokens"`
 }
 
 func Load(path string) (*Config, error) {
@@ -116,17 +310,92 @@ func Load(path string) (*Config, error) {
 	if env := os.Getenv("GOOGLE_API_KEY"); env != "" {
 		cfg.Gemini.APIKey = env
 	}
+	if env := os.Getenv("OLLAMA_URL"); env != "" {
+		cfg.Ollama.BaseURL = env
+	}
+	if env := os.Getenv("AZURE_OPENAI_ENDPOINT"); env != "" {
+		cfg.Azure.Endpoint = env
+	}
+	if env := os.Getenv("AZURE_OPENAI_API_KEY"); env != "" {
+		cfg.Azure.APIKey = env
+	}
 	if env := os.Getenv("AI_PROVIDER"); env != "" {
 		cfg.AI.Provider = env
 	}
 
-	if err := validate(cfg); err != nil {
-		return nil, err
+	if len(cfg.Instances) == 0 {
+		if err := validate(cfg); err != nil {
+			return nil, err
+		}
+		return cfg, nil
 	}
 
+	seen := make(map[string]bool)
+	for _, pet := range cfg.Pets() {
+		name := pet.Instances[0].Name
+		if name == "" || seen[name] {
+			return nil, fmt.Errorf("instances: every pet needs a unique name (got %q)", name)
+		}
+		seen[name] = true
+		if err := validate(pet); err != nil {
+			return nil, fmt.Errorf("instance %s: %w", name, err)
+		}
+	}
 	return cfg, nil
 }
 
+// Pets returns the config for each pet this process runs: cfg itself, or
+// one resolved config per instance. A resolved config keeps its own
+// InstanceConfig as Instances[0], so callers can tell the pets apart.
+func (cfg *Config) Pets() []*Config {
+	if len(cfg.Instances) == 0 {
+		return []*Config{cfg}
+	}
+
+	pets := make([]*Config, len(cfg.Instances))
+	for n, inst := range cfg.Instances {
+		pet := *cfg
+		pet.Instances = []InstanceConfig{inst}
+
+		pet.Discord.BotToken = inst.BotToken
+		pet.Discord.ChannelID = inst.ChannelID
+		if len(inst.OwnerIDs) > 0 {
+			pet.Discord.OwnerIDs = inst.OwnerIDs
+		}
+		pet.Discord.Channels = inst.Channels
+		// Roles, servers, and the status channel belong to one server's pet
+		pet.Discord.Roles = nil
+		pet.Discord.Guilds = nil
+		pet.Discord.StatusChannelID = ""
+
+		dir := inst.StateDir
+		if dir == "" {
+			dir = inst.Name
+		}
+		pet.Pet.StatePath = filepath.Join(dir, filepath.Base(cfg.Pet.StatePath))
+		pet.Pet.StateDBPath = filepath.Join(dir, filepath.Base(cfg.Pet.StateDBPath))
+		pet.Pet.MemorialPath = filepath.Join(dir, filepath.Base(cfg.Pet.MemorialPath))
+		pet.Pet.SchedulePath = filepath.Join(dir, filepath.Base(cfg.Pet.SchedulePath))
+		pet.Pet.OutboxPath = filepath.Join(dir, filepath.Base(cfg.Pet.OutboxPath))
+		pet.Pet.MemoryPath = filepath.Join(dir, filepath.Base(cfg.Pet.MemoryPath))
+		pet.Pet.UsagePath = filepath.Join(dir, filepath.Base(cfg.Pet.UsagePath))
+		pet.Pet.ModelPath = filepath.Join(dir, filepath.Base(cfg.Pet.ModelPath))
+		pet.Pet.HistoryPath = filepath.Join(dir, filepath.Base(cfg.Pet.HistoryPath))
+
+		if inst.MaxTokens > 0 {
+			pet.Claude.MaxTokens = inst.MaxTokens
+		}
+		if inst.RateLimit > 0 {
+			pet.Claude.RateLimit = inst.RateLimit
+		}
+		if inst.Concurrency > 0 {
+			pet.Claude.Concurrency = inst.Concurrency
+		}
+		pets[n] = &pet
+	}
+	return pets
+}
+
 // loadDotEnv reads a .env file and sets env vars that aren't already set.
 func loadDotEnv(path string) {
 	f, err := os.Open(path)
@@ -172,20 +441,64 @@ func defaults() *Config {
 		Discord: DiscordConfig{
 			AllowSpectatorPet: true,
 			UseThreads:        true,
+			LocalesDir:        "locales",
+			BlackoutNotice:    2 * time.Minute,
+		},
+		AI: AIConfig{
+			DocsDir:       "docs",
+			MemoryTurns:   6,
+			RedactSecrets: true,
+			InputPrice:    3,
+			OutputPrice:   15,
 		},
 		Claude: ClaudeConfig{
-			Model:      "claude-sonnet-4-5-20250929",
-			MaxTokens:  1024,
-			MaxTools:   5,
-			RateLimit:  10,
-			RateWindow: time.Minute,
+			Model:       "claude-sonnet-4-5-20250929",
+			MaxTokens:   1024,
+			MaxTools:    5,
+			RateLimit:   10,
+			RateWindow:  time.Minute,
+			Concurrency: 2,
+			MaxQueue:    8,
 		},
 		Gemini: GeminiConfig{
 			Model: "gemini-2.5-flash",
 		},
+		Ollama: OllamaConfig{
+			Model: "llama3.1",
+		},
+		Azure: AzureConfig{
+			Deployment: "gpt-4o",
+			APIVersion: "2024-10-21",
+		},
+		Bedrock: BedrockConfig{
+			Model: "us.anthropic.claude-sonnet-4-5-20250929-v1:0",
+		},
 		Pet: PetConfig{
 			StatePath:    "state.json",
+			StateBackend: "json",
+			StateDBPath:  "state.db",
+			MemorialPath: "memorial.json",
+			SchedulePath: "schedule.json",
+			OutboxPath:   "outbox.json",
+			MemoryPath:   "memory.json",
+			UsagePath:    "usage.json",
+			ModelPath:    "model.json",
+			HistoryPath:  "history.json",
 			SaveInterval: 5 * time.Minute,
+			SaveDebounce: 5 * time.Second,
+
+			HistoryInterval:  10 * time.Minute,
+			HistoryRetention: 7 * 24 * time.Hour,
+			Personality: PersonalityConfig{
+				Sassiness:    5,
+				Verbosity:    "normal",
+				Emoji:        "some",
+				StatMentions: "sometimes",
+			},
+		},
+		Species: SpeciesConfig{
+			PacksDir:   "species",
+			OnConflict: "skip",
 		},
 		Monitor: MonitorConfig{
 			Interval: 30 * time.Second,
@@ -193,6 +506,16 @@ func defaults() *Config {
 		Shell: ShellConfig{
 			Timeout:        10 * time.Second,
 			MaxOutputBytes: 10240,
+			Python:         false,
+			PythonTimeout:  5 * time.Second,
+			PythonMemoryMB: 128,
+			LogUnits:       []string{"pipet"},
+			CleanupPaths:   []string{"/home"},
+		},
+		Nest: NestConfig{
+			Paths: []string{"/etc/fstab", "/etc/hosts", "/etc/hostname", "/etc/crontab", "/etc/cron.d", "/var/spool/cron/crontabs"},
+			Dest:  "",
+			Keep:  7,
 		},
 		Proactive: ProactiveConfig{
 			Enabled:          true,
@@ -200,6 +523,26 @@ func defaults() *Config {
 			MorningHour:      8,
 			BoredomMinutes:   120,
 			DistressCooldown: 30 * time.Minute,
+			Contests:         true,
+			ContestWeekday:   0,
+			ContestHour:      18,
+			Quests:           true,
+			QuestInterval:    24 * time.Hour,
+			Polls:            true,
+			PollInterval:     72 * time.Hour,
+			PollDuration:     4 * time.Hour,
+			Diary:            false,
+			DiaryHour:        22,
+			DiaryMaxTokens:   200,
+			DiaryThread:      true,
+			LogSummary:       false,
+			LogSummaryHour:   20,
+			Digest:           false,
+			DigestHour:       21,
+			Postmortems:      false,
+			Dreams:           false,
+			DreamChance:      0.3,
+			DreamMaxTokens:   120,
 		},
 	}
 }
@@ -214,5 +557,95 @@ func validate(cfg *Config) error {
 	if len(cfg.Discord.OwnerIDs) == 0 {
 		return fmt.Errorf("missing DISCORD_OWNER_IDS — run ./setup.sh to configure")
 	}
+	channels := make(map[string]bool, len(cfg.Discord.Channels))
+	for _, c := range cfg.Discord.Channels {
+		if c.ID == "" {
+			return fmt.Errorf("discord.channels: every channel needs an id")
+		}
+		if channels[c.ID] {
+			return fmt.Errorf("discord.channels: %s is listed twice", c.ID)
+		}
+		channels[c.ID] = true
+	}
+	for _, g := range cfg.Discord.Guilds {
+		if g.ID == "" || g.ChannelID == "" {
+			return fmt.Errorf("discord.guilds: every server needs an id and a channel_id")
+		}
+		if g.CommandCooldown < 0 {
+			return fmt.Errorf("discord.guilds: %s: command_cooldown can't be negative", g.ID)
+		}
+	}
+	if cfg.Discord.BlackoutNotice < 0 {
+		return fmt.Errorf("discord.blackout_notice can't be negative")
+	}
+	if cfg.Discord.CommandCooldown < 0 {
+		return fmt.Errorf("discord.command_cooldown can't be negative")
+	}
+	for name, d := range cfg.Discord.CommandCooldowns {
+		if d < 0 {
+			return fmt.Errorf("discord.command_cooldowns: %s can't be negative", name)
+		}
+	}
+	if cfg.Proactive.Polls && cfg.Proactive.PollDuration < time.Hour {
+		return fmt.Errorf("proactive.poll_duration must be at least 1h (Discord's minimum)")
+	}
+	if c := cfg.Proactive.DreamChance; c < 0 || c > 1 {
+		return fmt.Errorf("proactive.dream_chance must be 0–1 (got %g)", c)
+	}
+	if cfg.AI.MemoryTurns < 0 {
+		return fmt.Errorf("ai.memory_turns can't be negative (got %d)", cfg.AI.MemoryTurns)
+	}
+	if cfg.AI.MonthlyTokenCap < 0 || cfg.AI.InputPrice < 0 || cfg.AI.OutputPrice < 0 {
+		return fmt.Errorf("ai.monthly_token_cap, ai.input_price, and ai.output_price can't be negative")
+	}
+	if cfg.Nest.Dest != "" && cfg.Nest.Keep < 1 {
+		return fmt.Errorf("nest.keep must be at least 1 (got %d)", cfg.Nest.Keep)
+	}
+	for role := range cfg.Discord.Roles {
+		switch role {
+		case "feeder", "groomer", "medic":
+		default:
+			return fmt.Errorf("discord.roles: unknown role %q (want feeder, groomer, or medic)", role)
+		}
+	}
+	if cfg.Pet.SaveInterval <= 0 || cfg.Pet.SaveDebounce <= 0 {
+		return fmt.Errorf("pet.save_interval and pet.save_debounce must be positive")
+	}
+	if err := oneOf("pet.state_backend", cfg.Pet.StateBackend, "json", "sqlite"); err != nil {
+		return err
+	}
+	if cfg.Pet.HistoryInterval <= 0 {
+		return fmt.Errorf("pet.history_interval must be positive")
+	}
+	if cfg.Pet.HistoryRetention < 24*time.Hour {
+		return fmt.Errorf("pet.history_retention must be at least 24h (got %s)", cfg.Pet.HistoryRetention)
+	}
+	if p := cfg.Pet.Personality; p.Sassiness < 0 || p.Sassiness > 10 {
+		return fmt.Errorf("pet.personality.sassiness must be 0–10 (got %d)", p.Sassiness)
+	}
+	if err := oneOf("pet.personality.verbosity", cfg.Pet.Personality.Verbosity, "brief", "normal", "chatty"); err != nil {
+		return err
+	}
+	if err := oneOf("pet.personality.emoji", cfg.Pet.Personality.Emoji, "none", "some", "lots"); err != nil {
+		return err
+	}
+	if err := oneOf("pet.personality.stat_mentions", cfg.Pet.Personality.StatMentions, "never", "sometimes", "often"); err != nil {
+		return err
+	}
+	switch cfg.Species.OnConflict {
+	case "skip", "override", "prefix":
+	default:
+		return fmt.Errorf("species.on_conflict must be skip, override, or prefix (got %q)", cfg.Species.OnConflict)
+	}
 	return nil
 }
+
+// oneOf checks that a config value is one of the allowed choices.
+func oneOf(key, value string, allowed ...string) error {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s must be %s (got %q)", key, strings.Join(allowed, ", "), value)
+}
@@ -0,0 +1,162 @@
+package contest
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Contest kinds.
+const (
+	Coolest  = "coolest"  // lowest average temperature wins
+	Cleanest = "cleanest" // highest average cleanliness wins
+)
+
+// Kinds lists all contests in announcement order.
+var Kinds = []string{Coolest, Cleanest}
+
+// tag marks a contest entry line so other pets can parse it. It's posted as
+// Discord subtext ("-# ...") so it stays unobtrusive in the channel.
+const tag = "-# pipet:contest "
+
+// Entry is one pet's score in one contest round.
+type Entry struct {
+	Kind  string
+	Round string // ISO week, e.g. "2026-W42"
+	Score float64
+	Pet   string
+}
+
+// Title returns a display name for a contest kind.
+func Title(kind string) string {
+	switch kind {
+	case Coolest:
+		return "coolest pi"
+	case Cleanest:
+		return "cleanest pi"
+	default:
+		return kind
+	}
+}
+
+// Unit returns the score unit for a contest kind.
+func Unit(kind string) string {
+	if kind == Coolest {
+		return "°C"
+	}
+	return "%"
+}
+
+// RoundOf returns the contest round (ISO week) containing t.
+func RoundOf(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// Format renders the machine-readable line for an entry.
+func Format(e Entry) string {
+	return fmt.Sprintf("%s%s %s %.2f %s", tag, e.Kind, e.Round, e.Score, e.Pet)
+}
+
+// Parse extracts a contest entry from a message, if it contains one.
+func Parse(text string) (Entry, bool) {
+	for _, line := range strings.Split(text, "\n") {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), tag)
+		if !ok {
+			continue
+		}
+		fields := strings.SplitN(rest, " ", 4)
+		if len(fields) != 4 {
+			return Entry{}, false
+		}
+		score, err := strconv.ParseFloat(fields[2], 64)
+		if err != nil {
+			return Entry{}, false
+		}
+		return Entry{Kind: fields[0], Round: fields[1], Score: score, Pet: fields[3]}, true
+	}
+	return Entry{}, false
+}
+
+// Board tracks this pet's running averages for the current round and
+// collects entries from every pet in the channel.
+type Board struct {
+	mu      sync.Mutex
+	round   string
+	sums    map[string]float64
+	samples int
+	entries map[string]map[string]Entry // kind/round → pet → entry
+}
+
+// NewBoard creates an empty contest board.
+func NewBoard() *Board {
+	return &Board{
+		sums:    make(map[string]float64),
+		entries: make(map[string]map[string]Entry),
+	}
+}
+
+// Record adds a sample of this pet's stats, starting fresh each round.
+func (b *Board) Record(now time.Time, tempC, cleanliness float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if round := RoundOf(now); round != b.round {
+		b.round = round
+		b.sums = make(map[string]float64)
+		b.samples = 0
+	}
+	b.sums[Coolest] += tempC
+	b.sums[Cleanest] += cleanliness
+	b.samples++
+}
+
+// Score returns this pet's average for a contest kind in the current round.
+func (b *Board) Score(kind string) (float64, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.samples == 0 {
+		return 0, false
+	}
+	return b.sums[kind] / float64(b.samples), true
+}
+
+// Submit records an entry. A pet's later entry replaces its earlier one.
+func (b *Board) Submit(e Entry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := e.Kind + "/" + e.Round
+	if b.entries[key] == nil {
+		b.entries[key] = make(map[string]Entry)
+	}
+	b.entries[key][e.Pet] = e
+}
+
+// Results returns the entries for a round, best first. Ties go to the
+// alphabetically first name so every pet computes the same winner.
+func (b *Board) Results(kind, round string) []Entry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := kind + "/" + round
+	results := make([]Entry, 0, len(b.entries[key]))
+	for _, e := range b.entries[key] {
+		results = append(results, e)
+	}
+	delete(b.entries, key)
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			if kind == Coolest {
+				return results[i].Score < results[j].Score
+			}
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Pet < results[j].Pet
+	})
+	return results
+}
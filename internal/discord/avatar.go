@@ -0,0 +1,70 @@
+package discord
+
+import (
+	"encoding/base64"
+	"log/slog"
+	"time"
+)
+
+// avatarInterval is the minimum time between avatar changes. Discord only
+// allows a few an hour, and the avatar doesn't need to follow every swing.
+const avatarInterval = time.Hour
+
+// EnableMoodAvatar has the bot change its avatar to match the pet's mood, so
+// a sick pet looks sick in the member list.
+func (b *Bot) EnableMoodAvatar() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.moodAvatar = true
+}
+
+// scheduleAvatar changes the avatar to the current mood now, or once
+// avatarInterval has passed since the last change.
+func (b *Bot) scheduleAvatar() {
+	b.mu.Lock()
+	mood := b.lastMood
+	if !b.moodAvatar || mood == "" || mood == b.avatarMood {
+		b.mu.Unlock()
+		return
+	}
+	if wait := avatarInterval - time.Since(b.avatarAt); wait > 0 {
+		if !b.avatarPending {
+			b.avatarPending = true
+			time.AfterFunc(wait, b.flushAvatar)
+		}
+		b.mu.Unlock()
+		return
+	}
+	b.avatarAt = time.Now()
+	b.avatarMood = mood
+	b.mu.Unlock()
+
+	if err := b.setAvatar(mood); err != nil {
+		slog.Warn("discord: update avatar failed", "mood", mood, "err", err)
+		b.mu.Lock()
+		b.avatarMood = "" // try again on the next mood update
+		b.mu.Unlock()
+	}
+}
+
+// flushAvatar sends the avatar change that was held back.
+func (b *Bot) flushAvatar() {
+	b.mu.Lock()
+	b.avatarPending = false
+	b.mu.Unlock()
+
+	b.scheduleAvatar()
+}
+
+func (b *Bot) setAvatar(mood string) error {
+	if b.petState == nil || !b.petState.IsOnboarded() {
+		return nil
+	}
+	snap := b.petState.Snapshot()
+	png, err := MoodAvatar(snap, getSpecies(snap), mood)
+	if err != nil {
+		return err
+	}
+	_, err = b.session.UserUpdate("", "data:image/png;base64,"+base64.StdEncoding.EncodeToString(png), "")
+	return err
+}
@@ -1,14 +1,24 @@
 package discord
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 
+	"github.com/moorebrett0/pipet/internal/i18n"
+	"github.com/moorebrett0/pipet/internal/items"
+	"github.com/moorebrett0/pipet/internal/metrics"
 	"github.com/moorebrett0/pipet/internal/pet"
 	"github.com/moorebrett0/pipet/internal/species"
 )
@@ -25,10 +35,104 @@ type Bot struct {
 	petState *pet.PetState
 	router   *Router
 
+	// Status line mirrored into the channel topic and/or a voice channel name
+	statusTopic   bool
+	statusVoiceID string
+	statusEdits   map[string]statusEdit
+
+	// Channels the pet talks in besides visits, and its rules in each
+	channels map[string]ChannelRules
+
+	// Other servers the pet appears in, by guild ID, and its channel in each
+	guilds map[string]string
+
+	// Slash commands go in each server rather than globally, and are
+	// removed again on shutdown if cleanupCommands is set
+	guildCommands   bool
+	cleanupCommands bool
+	commandScopes   []string // servers commands were registered in
+
+	// Owners may talk to the pet in direct messages
+	ownerDMs bool
+
+	// Each @mention conversation gets its own thread; conversations maps
+	// the threads the pet opened to their parent channel
+	conversationThreads bool
+	conversations       map[string]string
+
+	// Translations of command names and descriptions, by locale code
+	locales map[string]*i18n.Locale
+
+	// Guest visit to another channel (empty when home)
+	visitChannel string
+	visitUntil   time.Time
+
+	// Messages waiting for Discord to come back (nil disables buffering)
+	outbox *outbox
+
+	// Gateway connection state, for reconnect handling
+	connected      bool
+	disconnectedAt time.Time
+	lastMood       string        // last presence set, re-applied after a reconnect
+	blackoutAfter  time.Duration // gap before the pet mentions it (0 = never)
+
+	// Presence rate limiting
+	presenceAt      time.Time // when the presence was last sent
+	presencePending bool      // a deferred update is waiting to go out
+	activityTurn    int       // which rotating activity is showing
+
+	// Avatar that follows the mood, rate limited like the presence
+	moodAvatar    bool
+	avatarMood    string    // mood the current avatar shows
+	avatarAt      time.Time // when the avatar was last changed
+	avatarPending bool      // a deferred change is waiting to go out
+
 	mu     sync.Mutex
 	cancel context.CancelFunc
 }
 
+// ChannelRules is how the pet behaves in a channel it listens in.
+type ChannelRules struct {
+	MentionsOnly bool // only answer @mentions, not keywords like "good pet"
+	PetChat      bool // banter with other pets' bots
+	OwnersOnly   bool // ignore everyone but owners
+
+	// Every message is meant for the pet: set for conversation threads it
+	// opened and forum posts, not configured
+	Conversation bool
+}
+
+// homeRules apply in the home channel unless SetChannels says otherwise.
+var homeRules = ChannelRules{PetChat: true}
+
+type statusEdit struct {
+	text string
+	at   time.Time
+}
+
+// blackoutGap is how long the gateway has to be gone, by default, before the
+// pet mentions it when it comes back.
+const blackoutGap = 2 * time.Minute
+
+// Backoff between attempts to open the gateway connection at startup. Once
+// connected, discordgo reconnects on its own with a similar backoff.
+const (
+	openBackoffMin = 2 * time.Second
+	openBackoffMax = 5 * time.Minute
+)
+
+// presenceInterval is the minimum time between presence updates. Changes
+// arriving sooner are held and the latest one is sent when it elapses.
+const presenceInterval = time.Minute
+
+// presenceRotation is how often the presence moves on to the next activity
+// (mood, an idle behavior, CPU and temperature, uptime, days alive).
+const presenceRotation = 4 * time.Minute
+
+// statusEditInterval keeps channel edits under Discord's limit of two
+// name/topic changes per channel every 10 minutes.
+const statusEditInterval = 6 * time.Minute
+
 // NewBot creates and configures a Discord bot (does not connect yet).
 func NewBot(token, channelID string, ownerIDs []string, allowSpectatorPet, useThreads bool) (*Bot, error) {
 	session, err := discordgo.New("Bot " + token)
@@ -48,18 +152,185 @@ func NewBot(token, channelID string, ownerIDs []string, allowSpectatorPet, useTh
 	return &Bot{
 		session:           session,
 		channelID:         channelID,
+		channels:          map[string]ChannelRules{channelID: homeRules},
 		ownerIDs:          owners,
 		allowSpectatorPet: allowSpectatorPet,
 		useThreads:        useThreads,
+		blackoutAfter:     blackoutGap,
 	}, nil
 }
 
+// EnableStatusLine mirrors the pet's status line into the pet channel's topic
+// and/or the name of a voice channel. Pass false and "" to leave both alone.
+func (b *Bot) EnableStatusLine(topic bool, voiceChannelID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.statusTopic = topic
+	b.statusVoiceID = voiceChannelID
+	b.statusEdits = make(map[string]statusEdit)
+}
+
+// SetChannels has the pet also listen in the given channels, with their
+// rules. Proactive messages still only go to the home channel, whose rules
+// can be changed by including it.
+func (b *Bot) SetChannels(rules map[string]ChannelRules) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for id, r := range rules {
+		b.channels[id] = r
+	}
+}
+
+// SetGuilds has the pet appear in more servers, given as guild ID to the
+// channel it talks in there (with the home rules, unless SetChannels gives
+// others). Slash commands are then registered in each server, plus the
+// home channel's, rather than globally. Call it before Start.
+func (b *Bot) SetGuilds(guilds map[string]string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.guilds = guilds
+	for _, channelID := range guilds {
+		if _, ok := b.channels[channelID]; !ok {
+			b.channels[channelID] = homeRules
+		}
+	}
+}
+
+// SetBlackoutNotice sets how long the connection to Discord has to be down
+// before the pet remarks on it once it's back. Zero keeps it quiet.
+func (b *Bot) SetBlackoutNotice(after time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.blackoutAfter = after
+}
+
+// EnableOwnerDMs lets owners talk to the pet in direct messages, for
+// conversations and shell work they'd rather keep out of the channel.
+// Anyone else who DMs it is turned away. Call it before Start.
+func (b *Bot) EnableOwnerDMs() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.ownerDMs = true
+	b.session.Identify.Intents |= discordgo.IntentsDirectMessages
+}
+
+// EnableConversationThreads moves each @mention conversation in the pet's
+// channels into a thread of its own, where it answers without being
+// mentioned again and remembers the conversation separately. Posts in forum
+// channels it listens in are treated the same way.
+func (b *Bot) EnableConversationThreads() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.conversationThreads = true
+	b.conversations = make(map[string]string)
+}
+
+// ConversationThreads reports whether conversations get their own threads.
+func (b *Bot) ConversationThreads() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.conversationThreads
+}
+
+// StartConversation opens a thread on messageID for a conversation and
+// returns its ID. Messages in it are handled as part of that conversation.
+func (b *Bot) StartConversation(channelID, messageID, name string) (string, error) {
+	threadID, err := b.CreateThread(channelID, messageID, name)
+	if err != nil {
+		return "", err
+	}
+	b.mu.Lock()
+	b.conversations[threadID] = channelID
+	b.mu.Unlock()
+	return threadID, nil
+}
+
+// threadRules returns the rules for a thread in a channel the pet listens
+// in, and whether there is one. It only consults the gateway's cache, so
+// messages elsewhere cost nothing.
+func (b *Bot) threadRules(channelID string) (ChannelRules, bool) {
+	ch, err := b.session.State.Channel(channelID)
+	if err != nil || !ch.IsThread() {
+		return ChannelRules{}, false
+	}
+	rules, ok := b.Rules(ch.ParentID)
+	if !ok {
+		return rules, false
+	}
+	b.mu.Lock()
+	_, ours := b.conversations[channelID]
+	b.mu.Unlock()
+	if ours {
+		rules.Conversation = true
+	} else if parent, err := b.session.State.Channel(ch.ParentID); err == nil && parent.Type == discordgo.ChannelTypeGuildForum {
+		rules.Conversation = true
+	}
+	return rules, true
+}
+
+// EnableGuildCommands registers slash commands in the home server (and any
+// from SetGuilds) instead of globally, so changes show up right away. With
+// cleanup, they're removed again when the pet shuts down. Call it before
+// Start.
+func (b *Bot) EnableGuildCommands(cleanup bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.guildCommands = true
+	b.cleanupCommands = cleanup
+}
+
+// commandGuilds lists the servers to register slash commands in, or nil to
+// register them globally.
+func (b *Bot) commandGuilds() []string {
+	b.mu.Lock()
+	var ids []string
+	for id := range b.guilds {
+		ids = append(ids, id)
+	}
+	perGuild := b.guildCommands
+	b.mu.Unlock()
+	if len(ids) == 0 && !perGuild {
+		return nil
+	}
+	if ch, err := b.session.Channel(b.channelID); err != nil {
+		slog.Warn("discord: couldn't look up the home channel's server", "err", err)
+	} else if ch.GuildID != "" && !slices.Contains(ids, ch.GuildID) {
+		ids = append([]string{ch.GuildID}, ids...)
+	}
+	return ids
+}
+
+// Rules returns the pet's rules for channelID, and whether it listens there.
+func (b *Bot) Rules(channelID string) (ChannelRules, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	r, ok := b.channels[channelID]
+	return r, ok
+}
+
+// EnableOutbox buffers messages that fail to send because Discord is
+// unreachable in a file at path, and delivers them once it's back.
+func (b *Bot) EnableOutbox(path string) error {
+	o, err := openOutbox(path)
+	if err != nil {
+		return err
+	}
+	b.outbox = o
+	return nil
+}
+
 // SetRouter wires the router to handle messages and interactions.
 func (b *Bot) SetRouter(r *Router) {
 	b.router = r
+	b.petState = r.petState
+	if ids := r.petState.OwnerIDs(); len(ids) > 0 {
+		b.SetOwners(ids) // transferred since the config was written
+	}
 	b.session.AddHandler(b.onMessageCreate)
 	b.session.AddHandler(b.onInteractionCreate)
 	b.session.AddHandler(b.onReady)
+	b.session.AddHandler(b.onResumed)
+	b.session.AddHandler(b.onDisconnect)
 }
 
 // Start opens the Discord connection and registers slash commands.
@@ -70,8 +341,7 @@ func (b *Bot) Start(ctx context.Context) {
 	b.cancel = cancel
 	b.mu.Unlock()
 
-	if err := b.session.Open(); err != nil {
-		slog.Error("discord: failed to open session", "err", err)
+	if !b.open(ctx) {
 		cancel()
 		return
 	}
@@ -81,9 +351,15 @@ func (b *Bot) Start(ctx context.Context) {
 	// Register slash commands
 	b.registerCommands()
 
+	if b.outbox != nil {
+		go b.outbox.run(ctx, b.send)
+	}
+	go b.rotatePresence(ctx)
+
 	// Wait for shutdown
 	<-ctx.Done()
 	slog.Info("discord: shutting down")
+	b.removeCommands()
 	b.session.Close()
 }
 
@@ -92,14 +368,70 @@ func (b *Bot) ChannelID() string {
 	return b.channelID
 }
 
-// SendMessage sends a text message to a channel.
+// SendMessage sends a text message to a channel. Text over Discord's limit
+// is split across messages at paragraph breaks, or attached as a .txt file
+// under its first part when it would take more than maxChunks messages.
 func (b *Bot) SendMessage(channelID, text string) {
 	if text == "" {
 		return
 	}
-	if _, err := b.session.ChannelMessageSend(channelID, text); err != nil {
+	chunks := splitMessage(text, messageLimit)
+	if len(chunks) > maxChunks {
+		err := b.SendFile(channelID, chunks[0], "message.txt", []byte(text))
+		if err == nil {
+			return
+		}
+		slog.Warn("discord: attaching long message failed, splitting it instead", "err", err)
+	}
+	for _, chunk := range chunks {
+		if err := b.send(channelID, chunk); err != nil {
+			b.buffer(channelID, chunk, err)
+		}
+	}
+}
+
+// SendFile posts a file attachment with an optional message.
+func (b *Bot) SendFile(channelID, content, name string, data []byte) error {
+	start := time.Now()
+	_, err := b.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{
+		Content: content,
+		Files:   []*discordgo.File{{Name: name, ContentType: "text/plain", Reader: bytes.NewReader(data)}},
+	})
+	metrics.Since("discord.send", start, err)
+	if err != nil {
+		return fmt.Errorf("sending file: %w", err)
+	}
+	return nil
+}
+
+// send delivers a message once, with no buffering.
+func (b *Bot) send(channelID, text string) error {
+	start := time.Now()
+	_, err := b.session.ChannelMessageSend(channelID, text)
+	metrics.Since("discord.send", start, err)
+	return err
+}
+
+// buffer queues a message that failed to send, if the failure looks like
+// Discord being unreachable and the outbox is enabled.
+func (b *Bot) buffer(channelID, text string, err error) {
+	if b.outbox == nil || !retryable(err) {
 		slog.Error("discord: send message failed", "err", err)
+		return
 	}
+	slog.Warn("discord: send failed, queued for later", "err", err)
+	b.outbox.add(queuedMessage{ChannelID: channelID, Text: text, At: time.Now()})
+}
+
+// Post sends a text message and returns its ID, for callers that need to
+// refer back to it.
+func (b *Bot) Post(channelID, text string) (string, error) {
+	msg, err := b.session.ChannelMessageSend(channelID, text)
+	if err != nil {
+		b.buffer(channelID, text, err)
+		return "", fmt.Errorf("send message: %w", err)
+	}
+	return msg.ID, nil
 }
 
 // SendEmbed sends an embed to a channel.
@@ -121,9 +453,120 @@ func (b *Bot) CreateThread(channelID, messageID, name string) (string, error) {
 	return thread.ID, nil
 }
 
-// UpdatePresence sets the bot's Discord status based on pet mood.
+// StartThread creates a standalone public thread in a channel and returns
+// its ID.
+func (b *Bot) StartThread(channelID, name string) (string, error) {
+	thread, err := b.session.ThreadStartComplex(channelID, &discordgo.ThreadStart{
+		Name:                name,
+		AutoArchiveDuration: 10080, // a week; posting unarchives it
+		Type:                discordgo.ChannelTypeGuildPublicThread,
+	})
+	if err != nil {
+		return "", fmt.Errorf("start thread: %w", err)
+	}
+	return thread.ID, nil
+}
+
+// UpdatePresence sets the bot's Discord status based on pet mood. Updates
+// are spaced at least presenceInterval apart; one that comes too soon is
+// deferred, and only the most recent mood is sent.
 func (b *Bot) UpdatePresence(mood string) {
-	status, activity := moodToPresence(mood)
+	b.mu.Lock()
+	b.lastMood = mood
+	b.activityTurn = 0 // lead with the new mood
+	b.mu.Unlock()
+
+	b.schedulePresence()
+	b.scheduleAvatar()
+}
+
+// schedulePresence sends the current presence now, or once presenceInterval
+// has passed since the last one.
+func (b *Bot) schedulePresence() {
+	b.mu.Lock()
+	mood := b.lastMood
+	wait := presenceInterval - time.Since(b.presenceAt)
+	if wait > 0 {
+		if !b.presencePending {
+			b.presencePending = true
+			time.AfterFunc(wait, b.flushPresence)
+		}
+		b.mu.Unlock()
+		return
+	}
+	b.presenceAt = time.Now()
+	b.mu.Unlock()
+
+	b.setPresence(mood)
+}
+
+// flushPresence sends the presence update that was held back.
+func (b *Bot) flushPresence() {
+	b.mu.Lock()
+	b.presencePending = false
+	b.presenceAt = time.Now()
+	mood := b.lastMood
+	b.mu.Unlock()
+
+	b.setPresence(mood)
+}
+
+// rotatePresence cycles the presence through its activities until ctx ends.
+func (b *Bot) rotatePresence(ctx context.Context) {
+	ticker := time.NewTicker(presenceRotation)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.mu.Lock()
+			mood := b.lastMood
+			b.activityTurn++
+			b.mu.Unlock()
+			if mood != "" && mood != "dead" {
+				b.schedulePresence()
+			}
+		}
+	}
+}
+
+// activities lists what the presence rotates through for mood: the mood's
+// own line first, then something the pet is up to, live CPU and
+// temperature, the Pi's uptime, and how long the pet has been alive.
+func (b *Bot) activities(mood string) []string {
+	_, line := moodToPresence(mood)
+	out := []string{line}
+	if b.petState == nil || !b.petState.IsOnboarded() || mood == "dead" {
+		return out
+	}
+	snap := b.petState.Snapshot()
+	ctx := species.Context{Mood: mood, Hour: time.Now().Hour(), TempC: snap.TempC, CPUPercent: snap.CPUPercent}
+	if idle := getSpecies(snap).PickIdleBehavior(ctx); idle != "" {
+		out = append(out, idle)
+	}
+	if snap.TempC > 0 {
+		out = append(out, fmt.Sprintf("CPU %.0f%% \u00B7 %.0f\u00B0C", snap.CPUPercent, snap.TempC))
+	} else {
+		out = append(out, fmt.Sprintf("CPU %.0f%% \u00B7 mem %.0f%%", snap.CPUPercent, snap.MemPercent))
+	}
+	if snap.UptimeDays >= 1 {
+		out = append(out, fmt.Sprintf("uptime %dd", int(snap.UptimeDays)))
+	} else if snap.UptimeDays > 0 {
+		out = append(out, fmt.Sprintf("uptime %dh", int(snap.UptimeDays*24)))
+	}
+	out = append(out, fmt.Sprintf("day %d of being a %s", int(snap.AgeDays)+1, strings.ToLower(getSpecies(snap).Name)))
+	return out
+}
+
+func (b *Bot) setPresence(mood string) {
+	status, _ := moodToPresence(mood)
+	b.mu.Lock()
+	turn := b.activityTurn
+	b.mu.Unlock()
+	acts := b.activities(mood)
+	activity := acts[turn%len(acts)]
+
 	err := b.session.UpdateStatusComplex(discordgo.UpdateStatusData{
 		Status: status,
 		Activities: []*discordgo.Activity{
@@ -138,22 +581,229 @@ func (b *Bot) UpdatePresence(mood string) {
 	}
 }
 
+// UpdateStatusLine writes text to the status channels, if enabled. Edits are
+// skipped when nothing changed or the channel was edited too recently.
+func (b *Bot) UpdateStatusLine(text string) {
+	b.mu.Lock()
+	topic, voiceID := b.statusTopic, b.statusVoiceID
+	b.mu.Unlock()
+
+	if topic {
+		b.editStatusChannel(b.channelID, &discordgo.ChannelEdit{Topic: text}, text)
+	}
+	if voiceID != "" {
+		b.editStatusChannel(voiceID, &discordgo.ChannelEdit{Name: text}, text)
+	}
+}
+
+func (b *Bot) editStatusChannel(channelID string, edit *discordgo.ChannelEdit, text string) {
+	b.mu.Lock()
+	last := b.statusEdits[channelID]
+	if last.text == text || time.Since(last.at) < statusEditInterval {
+		b.mu.Unlock()
+		return
+	}
+	b.statusEdits[channelID] = statusEdit{text: text, at: time.Now()}
+	b.mu.Unlock()
+
+	if _, err := b.session.ChannelEdit(channelID, edit); err != nil {
+		slog.Warn("discord: status channel edit failed", "channel", channelID, "err", err)
+	}
+}
+
+// SendPoll posts a Discord poll and returns its message ID.
+func (b *Bot) SendPoll(channelID, question string, answers []string, duration time.Duration) (string, error) {
+	p := &discordgo.Poll{
+		Question:   discordgo.PollMedia{Text: question},
+		LayoutType: discordgo.PollLayoutTypeDefault,
+		Duration:   max(1, int(duration.Hours())),
+	}
+	for _, a := range answers {
+		p.Answers = append(p.Answers, discordgo.PollAnswer{Media: &discordgo.PollMedia{Text: a}})
+	}
+	msg, err := b.session.ChannelMessageSendComplex(channelID, &discordgo.MessageSend{Poll: p})
+	if err != nil {
+		return "", fmt.Errorf("sending poll: %w", err)
+	}
+	return msg.ID, nil
+}
+
+// PollResults returns the vote count for each answer of a poll, in the order
+// the answers were posted.
+func (b *Bot) PollResults(channelID, messageID string) ([]int, error) {
+	msg, err := b.session.ChannelMessage(channelID, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching poll: %w", err)
+	}
+	if msg.Poll == nil {
+		return nil, fmt.Errorf("message %s has no poll", messageID)
+	}
+	counts := make([]int, len(msg.Poll.Answers))
+	if msg.Poll.Results == nil {
+		return counts, nil
+	}
+	// Answer IDs are assigned from 1 in the order answers were posted
+	for _, c := range msg.Poll.Results.AnswerCounts {
+		if c.ID >= 1 && c.ID <= len(counts) {
+			counts[c.ID-1] = c.Count
+		}
+	}
+	return counts, nil
+}
+
+// StartVisit lets the pet answer mentions in another channel until the given
+// time. Returns false if it's already out visiting.
+func (b *Bot) StartVisit(channelID string, until time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.visitChannel != "" && time.Now().Before(b.visitUntil) {
+		return false
+	}
+	b.visitChannel = channelID
+	b.visitUntil = until
+	return true
+}
+
+// EndVisit brings the pet home.
+func (b *Bot) EndVisit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.visitChannel = ""
+	b.visitUntil = time.Time{}
+}
+
+// IsVisiting reports whether the pet is currently visiting channelID.
+func (b *Bot) IsVisiting(channelID string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.visitChannel == channelID && time.Now().Before(b.visitUntil)
+}
+
 // IsOwner checks if a user ID is in the owner list.
 func (b *Bot) IsOwner(userID string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
 	return b.ownerIDs[userID]
 }
 
+// Owners returns the current owner IDs.
+func (b *Bot) Owners() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ids := make([]string, 0, len(b.ownerIDs))
+	for id := range b.ownerIDs {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// SetOwners replaces the owner list at runtime.
+func (b *Bot) SetOwners(ids []string) {
+	owners := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		owners[id] = true
+	}
+	b.mu.Lock()
+	b.ownerIDs = owners
+	b.mu.Unlock()
+}
+
 // SendIntroduction posts the pet's first message in the channel.
 func (b *Bot) SendIntroduction(petState *pet.PetState) {
 	snap := petState.Snapshot()
-	sp := getSpecies(snap.SpeciesID)
+	sp := getSpecies(snap)
 	msg := fmt.Sprintf("%s hey everyone. i'm %s.\n   just hatched on a little pi zero.\n   %.0f°C in here. cozy.",
 		sp.Emoji, snap.Name, snap.TempC)
+	if snap.Shiny {
+		msg += "\n\u2728 oh — and i'm a **shiny**. look at me shimmer."
+	}
 	b.SendMessage(b.channelID, msg)
 }
 
+// open connects to the gateway, retrying with exponential backoff while
+// Discord is unreachable, and reports whether it got through before ctx was
+// done. A rejected token isn't retried.
+func (b *Bot) open(ctx context.Context) bool {
+	wait := openBackoffMin
+	for attempt := 1; ; attempt++ {
+		err := b.session.Open()
+		if err == nil {
+			return true
+		}
+		if tokenRejected(err) {
+			slog.Error("discord: bot token rejected", "err", err)
+			return false
+		}
+		slog.Warn("discord: failed to open session, retrying", "attempt", attempt, "in", wait, "err", err)
+		metrics.SetHealth("discord", false, "can't reach gateway")
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, openBackoffMax)
+	}
+}
+
+// tokenRejected reports whether err from opening the session means Discord
+// refused the bot token, either over REST or when identifying.
+func tokenRejected(err error) bool {
+	var rest *discordgo.RESTError
+	if errors.As(err, &rest) && rest.Response != nil && rest.Response.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+	return strings.Contains(err.Error(), "Authentication failed")
+}
+
 func (b *Bot) onReady(s *discordgo.Session, r *discordgo.Ready) {
 	slog.Info("discord: ready", "user", r.User.Username, "guilds", len(r.Guilds))
+	b.onReconnect()
+}
+
+func (b *Bot) onResumed(s *discordgo.Session, r *discordgo.Resumed) {
+	slog.Info("discord: session resumed")
+	b.onReconnect()
+}
+
+func (b *Bot) onDisconnect(s *discordgo.Session, d *discordgo.Disconnect) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.connected {
+		return
+	}
+	b.connected = false
+	b.disconnectedAt = time.Now()
+	metrics.SetHealth("discord", false, "gateway disconnected")
+	slog.Warn("discord: disconnected from gateway")
+}
+
+// onReconnect runs when the gateway connection is (re)established. After a
+// drop it logs the gap, restores the presence Discord forgot, and after a
+// long gap has the pet say it blacked out.
+func (b *Bot) onReconnect() {
+	b.mu.Lock()
+	wasDown := !b.connected && !b.disconnectedAt.IsZero()
+	gap := time.Since(b.disconnectedAt)
+	b.connected = true
+	b.disconnectedAt = time.Time{}
+	mood := b.lastMood
+	notice := b.blackoutAfter
+	b.mu.Unlock()
+
+	metrics.SetHealth("discord", true, "connected")
+	if !wasDown {
+		return
+	}
+
+	slog.Info("discord: reconnected", "gap", gap.Round(time.Second))
+	if mood != "" {
+		b.UpdatePresence(mood)
+	}
+	if notice > 0 && gap >= notice && b.petState != nil && b.petState.IsOnboarded() {
+		snap := b.petState.Snapshot()
+		b.SendMessage(b.channelID, TemplateBlackout(snap, getSpecies(snap), gap))
+	}
 }
 
 // BotUserID returns the bot's own user ID.
@@ -184,31 +834,74 @@ func (b *Bot) StripMention(text string) string {
 }
 
 func (b *Bot) onMessageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
+	defer b.recoverHandler("message", m.ChannelID)
+
 	// Ignore own messages only (not other bots)
 	if m.Author.ID == s.State.User.ID {
 		return
 	}
 
-	// Only respond in the configured channel
-	if m.ChannelID != b.channelID {
+	if m.GuildID == "" {
+		b.mu.Lock()
+		dms := b.ownerDMs
+		b.mu.Unlock()
+		if dms && !m.Author.Bot && b.router != nil {
+			b.router.HandlePrivateMessage(m)
+		}
+		return
+	}
+
+	// Only respond in the configured channels, plus mentions in a channel
+	// the pet is visiting
+	rules, ok := b.Rules(m.ChannelID)
+	if !ok && b.ConversationThreads() {
+		rules, ok = b.threadRules(m.ChannelID)
+	}
+	if !ok {
+		if b.IsVisiting(m.ChannelID) && !m.Author.Bot && b.IsMentioned(m) && b.router != nil {
+			b.router.HandleVisitMessage(m)
+		}
 		return
 	}
 
 	if b.router != nil {
-		b.router.HandleMessage(m)
+		b.router.HandleMessage(m, rules)
 	}
 }
 
 func (b *Bot) onInteractionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	if i.Type != discordgo.InteractionApplicationCommand {
+	defer b.recoverHandler("interaction", i.ChannelID)
+
+	if b.router == nil {
 		return
 	}
 
-	if b.router != nil {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
 		b.router.HandleInteraction(i)
+	case discordgo.InteractionMessageComponent:
+		b.router.HandleComponent(i)
 	}
 }
 
+// recoverHandler keeps a panic in one handler from taking down the daemon.
+// It logs the stack, counts the panic, and has the pet apologize in the
+// channel instead of going quiet. Call it deferred.
+func (b *Bot) recoverHandler(kind, channelID string) {
+	p := recover()
+	if p == nil {
+		return
+	}
+	slog.Error("discord: handler panicked", "kind", kind, "panic", p, "stack", string(debug.Stack()))
+	metrics.Observe("panic."+kind, 0, fmt.Errorf("panic: %v", p))
+
+	if channelID == "" || b.petState == nil || !b.petState.IsOnboarded() {
+		return
+	}
+	snap := b.petState.Snapshot()
+	b.SendMessage(channelID, TemplateGlitch(snap, getSpecies(snap)))
+}
+
 func (b *Bot) registerCommands() {
 	appID := b.session.State.User.ID
 	commands := []*discordgo.ApplicationCommand{
@@ -240,6 +933,18 @@ func (b *Bot) registerCommands() {
 				},
 			},
 		},
+		{
+			Name:        "ask",
+			Description: "Ask your pet something",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "question",
+					Description: "What to ask",
+					Required:    true,
+				},
+			},
+		},
 		{
 			Name:        "help",
 			Description: "Show available commands",
@@ -252,15 +957,587 @@ func (b *Bot) registerCommands() {
 			Name:        "mood",
 			Description: "Check your pet's current mood",
 		},
+		{
+			Name:        "export",
+			Description: "Download your pet so it can move to another Pi",
+		},
+		{
+			Name:        "adopt",
+			Description: "Move in a pet exported from another Pi",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionAttachment,
+					Name:        "file",
+					Description: "Export file from /export",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "reset",
+			Description: "Archive your pet to the memorial and hatch a new one",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "confirm",
+					Description: "Type your pet's name to confirm",
+					Required:    false,
+				},
+			},
+		},
+	}
+
+	speciesChoices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(species.OrderedIDs))
+	for _, id := range species.OrderedIDs {
+		if len(speciesChoices) == 25 { // Discord's limit on option choices
+			break
+		}
+		sp := species.Registry[id]
+		speciesChoices = append(speciesChoices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  sp.Emoji + " " + sp.Name,
+			Value: id,
+		})
+	}
+	commands = append(commands, &discordgo.ApplicationCommand{
+		Name:        "species",
+		Description: "Preview the species a pet can hatch as",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "name",
+				Description: "Species to preview",
+				Required:    false,
+				Choices:     speciesChoices,
+			},
+		},
+	})
+
+	itemChoices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(items.OrderedIDs))
+	for _, id := range items.OrderedIDs {
+		it := items.Catalog[id]
+		if it.Cures {
+			continue // medicine comes from the shop
+		}
+		itemChoices = append(itemChoices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  it.Emoji + " " + it.Name,
+			Value: id,
+		})
+	}
+	commands = append(commands,
+		&discordgo.ApplicationCommand{
+			Name:        "gift",
+			Description: "Give your pet an item, or have it gift one to another pet",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "item",
+					Description: "What to give",
+					Required:    true,
+					Choices:     itemChoices,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionUser,
+					Name:        "to",
+					Description: "Another pet to send it to (owner only; leave empty to give it to me)",
+					Required:    false,
+				},
+			},
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "inventory",
+			Description: "See what your pet is holding on to",
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "quest",
+			Description: "See the sysadmin quest your pet has for you",
+		},
+	)
+
+	commands = append(commands,
+		&discordgo.ApplicationCommand{
+			Name:        "roles",
+			Description: "See who looks after your pet, or (owner) assign a caretaker role",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "role",
+					Description: "Role to assign or remove",
+					Required:    false,
+					Choices:     roleChoices(),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionUser,
+					Name:        "user",
+					Description: "Who gets (or gives up) the role",
+					Required:    false,
+				},
+			},
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "transfer",
+			Description: "Hand your pet over to a new owner",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionUser,
+					Name:        "user",
+					Description: "The new owner",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "confirm",
+					Description: "Type your pet's name to confirm",
+					Required:    false,
+				},
+			},
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "visit",
+			Description: "Send your pet to visit another channel for a while",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:         discordgo.ApplicationCommandOptionChannel,
+					Name:         "channel",
+					Description:  "Where to visit",
+					Required:     true,
+					ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText},
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "minutes",
+					Description: "How long to stay (default 30, max 120)",
+					Required:    false,
+					MinValue:    &minVisitMinutes,
+					MaxValue:    maxVisitMinutes,
+				},
+			},
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "debug",
+			Description: "Latency and error stats for commands, the AI, and Discord",
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "uptime",
+			Description: "How long your pet, the Pi, and pipet have been running",
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "sysinfo",
+			Description: "The Pi's model, RAM, storage, OS, and network",
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "logs",
+			Description: "Post the latest journald lines for a service into a thread",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "unit",
+					Description: "Which service",
+					Required:    true,
+					Choices:     unitChoices(b.logUnits()),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "lines",
+					Description: fmt.Sprintf("How many lines (default %d, max %d)", defaultLogLines, int(maxLogLines)),
+					Required:    false,
+					MinValue:    &minLogLines,
+					MaxValue:    maxLogLines,
+				},
+			},
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "service",
+			Description: "Check on, start, restart, or stop a service",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "name",
+					Description: "Which service",
+					Required:    true,
+					Choices:     unitChoices(b.serviceUnits()),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "action",
+					Description: "What to do",
+					Required:    true,
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "status", Value: "status"},
+						{Name: "start", Value: "start"},
+						{Name: "restart", Value: "restart"},
+						{Name: "stop", Value: "stop"},
+					},
+				},
+			},
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "shell",
+			Description: "Run a shell command directly, no AI involved",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "command",
+					Description: "The command line to run",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionBoolean,
+					Name:        "thread",
+					Description: "Post the output in a thread instead of only to you",
+				},
+			},
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "audit",
+			Description: "List the last commands run on the Pi, with exit status (only you see it)",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "count",
+					Description: fmt.Sprintf("How many (default %d, max %d)", defaultAuditEntries, int(maxAuditEntries)),
+					MinValue:    &minAuditEntries,
+					MaxValue:    maxAuditEntries,
+				},
+			},
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "apt",
+			Description: "Check for package upgrades and apply them once you approve",
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "nest",
+			Description: "Back up important config files, or list backups to restore",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "action",
+					Description: "Build a new nest (default) or list existing ones",
+					Choices: []*discordgo.ApplicationCommandOptionChoice{
+						{Name: "build", Value: "build"},
+						{Name: "list", Value: "list"},
+					},
+				},
+			},
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "budget",
+			Description: "AI tokens used today and this month, with estimated spend",
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "model",
+			Description: "See or switch the AI model your pet thinks with",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "use",
+					Description: "Model to switch to",
+					Choices:     unitChoices(b.modelChoices()),
+				},
+			},
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "remember",
+			Description: "Teach your pet a fact to keep for good",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "fact",
+					Description: "What to remember, e.g. \"the USB drive is for photo backups\"",
+					Required:    true,
+					MaxLength:   200,
+				},
+			},
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "forget",
+			Description: "Make your pet forget a fact",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        "id",
+					Description: "The fact's number from /memories",
+					Required:    true,
+				},
+			},
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "memories",
+			Description: "See everything your pet remembers",
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "tasks",
+			Description: "See what your pet is still working on in the background",
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "temps",
+			Description: "Temperatures from every sensor, plus a 24h chart",
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "card",
+			Description: "Get a picture card of your pet to share",
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "story",
+			Description: "Hear how your pet is doing, told as a little story",
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "diary",
+			Description: "Read your pet's latest diary entry",
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "approve",
+			Description: "Let your pet run what the channel voted for",
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "schedule",
+			Description: "See your pet's scheduled tasks, or remove one",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "remove",
+					Description: "ID of the task to remove",
+					Required:    false,
+				},
+			},
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "balance",
+			Description: "Check how many shells you have",
+		},
+		&discordgo.ApplicationCommand{
+			Name:        "shop",
+			Description: "Spend shells on items, revives, and skins",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        "buy",
+					Description: "What to buy (leave empty to browse)",
+					Required:    false,
+					Choices:     shopChoices(b.petState),
+				},
+			},
+		},
+		&discordgo.ApplicationCommand{
+			Name: askAboutCommand,
+			Type: discordgo.MessageApplicationCommand,
+		},
+	)
+
+	commands = append(commands, speciesCommands(b.petState, commands)...)
+	b.mu.Lock()
+	localize(commands, b.locales)
+	b.mu.Unlock()
+
+	// Per-server commands show up right away, but global copies left from
+	// before would appear next to them
+	guilds := b.commandGuilds()
+	if len(guilds) > 0 {
+		if _, err := b.session.ApplicationCommandBulkOverwrite(appID, "", []*discordgo.ApplicationCommand{}); err != nil {
+			slog.Warn("discord: failed to clear global commands", "err", err)
+		}
+	} else {
+		guilds = []string{""}
+	}
+
+	for _, guildID := range guilds {
+		b.syncCommands(appID, guildID, commands)
+	}
+	b.mu.Lock()
+	b.commandScopes = guilds
+	b.mu.Unlock()
+}
+
+// syncCommands makes the commands registered in guildID ("" = globally)
+// match commands: new and changed ones are created, and ones no longer
+// defined are deleted. Unchanged ones are left alone, since Discord caps
+// how many commands can be created a day.
+func (b *Bot) syncCommands(appID, guildID string, commands []*discordgo.ApplicationCommand) {
+	existing, err := b.session.ApplicationCommands(appID, guildID)
+	if err != nil {
+		slog.Warn("discord: couldn't list registered commands", "guild", guildID, "err", err)
+	}
+	registered := make(map[string]*discordgo.ApplicationCommand, len(existing))
+	for _, cmd := range existing {
+		registered[commandKey(cmd)] = cmd
 	}
 
 	for _, cmd := range commands {
-		if _, err := b.session.ApplicationCommandCreate(appID, "", cmd); err != nil {
-			slog.Error("discord: failed to register command", "cmd", cmd.Name, "err", err)
+		key := commandKey(cmd)
+		old, ok := registered[key]
+		delete(registered, key)
+		if ok && sameCommand(old, cmd) {
+			continue
+		}
+		if _, err := b.session.ApplicationCommandCreate(appID, guildID, cmd); err != nil {
+			slog.Error("discord: failed to register command", "cmd", cmd.Name, "guild", guildID, "err", err)
+		} else {
+			slog.Info("discord: registered command", "cmd", cmd.Name, "guild", guildID)
+		}
+	}
+
+	for _, stale := range registered {
+		if err := b.session.ApplicationCommandDelete(appID, guildID, stale.ID); err != nil {
+			slog.Warn("discord: failed to remove stale command", "cmd", stale.Name, "guild", guildID, "err", err)
 		} else {
-			slog.Info("discord: registered command", "cmd", cmd.Name)
+			slog.Info("discord: removed stale command", "cmd", stale.Name, "guild", guildID)
+		}
+	}
+}
+
+// removeCommands deletes the per-server commands on shutdown, if asked to.
+// Global commands are kept; they'd take a while to come back.
+func (b *Bot) removeCommands() {
+	b.mu.Lock()
+	guilds := b.commandScopes
+	cleanup := b.cleanupCommands
+	b.mu.Unlock()
+	if !cleanup || b.session.State.User == nil {
+		return
+	}
+	for _, guildID := range guilds {
+		if guildID == "" {
+			continue
+		}
+		if _, err := b.session.ApplicationCommandBulkOverwrite(b.session.State.User.ID, guildID, []*discordgo.ApplicationCommand{}); err != nil {
+			slog.Warn("discord: failed to remove commands", "guild", guildID, "err", err)
+		}
+	}
+}
+
+// commandKey identifies a command by type and name, the way Discord does.
+func commandKey(cmd *discordgo.ApplicationCommand) string {
+	t := cmd.Type
+	if t == 0 {
+		t = discordgo.ChatApplicationCommand
+	}
+	return fmt.Sprintf("%d:%s", t, cmd.Name)
+}
+
+// sameCommand reports whether a registered command already matches the
+// definition, comparing what users see.
+func sameCommand(registered, defined *discordgo.ApplicationCommand) bool {
+	shape := func(cmd *discordgo.ApplicationCommand) []byte {
+		data, _ := json.Marshal(struct {
+			Description  string
+			Options      []*discordgo.ApplicationCommandOption
+			Names        *map[discordgo.Locale]string
+			Descriptions *map[discordgo.Locale]string
+		}{cmd.Description, cmd.Options, cmd.NameLocalizations, cmd.DescriptionLocalizations})
+		return data
+	}
+	return bytes.Equal(shape(registered), shape(defined))
+}
+
+func roleChoices() []*discordgo.ApplicationCommandOptionChoice {
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(pet.Roles))
+	for _, role := range pet.Roles {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  role,
+			Value: role,
+		})
+	}
+	return choices
+}
+
+// shopChoices lists everything for sale: items, a revive, and any skins the
+// pet's species has.
+// logUnits and serviceUnits are the router's allowlists, for command choices.
+func (b *Bot) logUnits() []string {
+	if b.router == nil {
+		return nil
+	}
+	return b.router.logUnits
+}
+
+func (b *Bot) serviceUnits() []string {
+	if b.router == nil {
+		return nil
+	}
+	return b.router.serviceUnits
+}
+
+// modelChoices lists the models /model can switch to, as provider:model.
+func (b *Bot) modelChoices() []string {
+	if b.router == nil || b.router.brain == nil {
+		return nil
+	}
+	var names []string
+	for _, m := range b.router.brain.Models() {
+		names = append(names, m.String())
+	}
+	return names
+}
+
+// unitChoices offers allowlisted units as command choices (Discord allows 25).
+func unitChoices(units []string) []*discordgo.ApplicationCommandOptionChoice {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, u := range units {
+		if len(choices) == 25 {
+			break
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: u, Value: u})
+	}
+	return choices
+}
+
+func shopChoices(petState *pet.PetState) []*discordgo.ApplicationCommandOptionChoice {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, id := range items.OrderedIDs {
+		price, ok := items.Prices[id]
+		if !ok {
+			continue
+		}
+		it := items.Catalog[id]
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  fmt.Sprintf("%s %s (%d %s)", it.Emoji, it.Name, price, items.CurrencyName),
+			Value: id,
+		})
+	}
+	choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+		Name:  fmt.Sprintf("\u2728 Revive (%d %s)", items.RevivePrice, items.CurrencyName),
+		Value: "revive",
+	})
+
+	if petState == nil || !petState.IsOnboarded() {
+		return choices
+	}
+	sp := getSpecies(petState.Snapshot())
+	for _, season := range species.Seasons {
+		if _, ok := sp.Skins[season.ID]; !ok || len(choices) == 25 {
+			continue
+		}
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{
+			Name:  fmt.Sprintf("%s skin (%d %s)", season.Name, items.SkinPrice, items.CurrencyName),
+			Value: "skin:" + season.ID,
+		})
+	}
+	return choices
+}
+
+// speciesCommands returns the flavored commands for the pet's species,
+// skipping any that would shadow a core command.
+func speciesCommands(petState *pet.PetState, core []*discordgo.ApplicationCommand) []*discordgo.ApplicationCommand {
+	if petState == nil || !petState.IsOnboarded() {
+		return nil
+	}
+	taken := make(map[string]bool, len(core))
+	for _, c := range core {
+		taken[c.Name] = true
+	}
+
+	sp := getSpecies(petState.Snapshot())
+	var cmds []*discordgo.ApplicationCommand
+	for _, c := range sp.Commands {
+		if taken[c.Name] {
+			slog.Warn("discord: species command shadows a core command", "species", sp.ID, "cmd", c.Name)
+			continue
 		}
+		cmds = append(cmds, &discordgo.ApplicationCommand{
+			Name:        c.Name,
+			Description: c.Description,
+		})
 	}
+	return cmds
 }
 
 func moodToPresence(mood string) (status, activity string) {
@@ -286,9 +1563,14 @@ func moodToPresence(mood string) (status, activity string) {
 	}
 }
 
-func getSpecies(id string) *species.Species {
-	if sp, ok := species.Registry[id]; ok {
-		return sp
+func getSpecies(snap pet.Snapshot) *species.Species {
+	sp, ok := species.Registry[snap.SpeciesID]
+	if !ok {
+		sp = species.Registry["octopus"]
+	}
+	sp = sp.Evolved(snap.Form).AtStage(snap.Stage).Wearing(snap.Skin, time.Now())
+	if snap.Shiny {
+		sp = sp.AsShiny()
 	}
-	return species.Registry["octopus"]
+	return sp
 }
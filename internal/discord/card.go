@@ -0,0 +1,121 @@
+package discord
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"  // species art formats
+	_ "image/jpeg" // species art formats
+	_ "image/png"  // species art formats
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/moorebrett0/pipet/internal/pet"
+	"github.com/moorebrett0/pipet/internal/render"
+	"github.com/moorebrett0/pipet/internal/species"
+)
+
+// PetCard lays out the pet for the /card image.
+func PetCard(snap pet.Snapshot, sp *species.Species) render.Card {
+	accent := moodColor(snap.Mood)
+	if snap.Shiny {
+		accent = species.ShinyColor
+	}
+
+	subtitle := strings.ToUpper(sp.Name)
+	if snap.Shiny {
+		subtitle = "SHINY " + subtitle
+	}
+	subtitle += fmt.Sprintf(" - LEVEL %d", snap.Form+1)
+
+	lines := []string{
+		fmt.Sprintf("AGE %.0f DAYS", snap.AgeDays),
+		fmt.Sprintf("FEELING %s", strings.ToUpper(snap.Mood)),
+	}
+	if snap.Streak > 0 {
+		lines = append(lines, fmt.Sprintf("%d-DAY CARE STREAK", snap.Streak))
+	}
+
+	return render.Card{
+		Name:     snap.Name,
+		Subtitle: subtitle,
+		Lines:    lines,
+		Stats: []render.CardStat{
+			{Label: "FULLNESS", Value: 100 - snap.Hunger},
+			{Label: "HAPPINESS", Value: snap.Happiness},
+			{Label: "ENERGY", Value: snap.Energy},
+			{Label: "BOND", Value: snap.Bond},
+		},
+		Badges: cardBadges(snap),
+		Accent: render.Hex(accent),
+		Art:    loadArt(sp, "avatar"),
+		Seed:   snap.SpeciesID + ":" + snap.Name,
+	}
+}
+
+// cardBadges picks the achievements worth showing off.
+func cardBadges(snap pet.Snapshot) []string {
+	var badges []string
+	if snap.Shiny {
+		badges = append(badges, "SHINY")
+	}
+	for _, days := range []int{365, 100, 30} {
+		if snap.AgeDays >= float64(days) {
+			badges = append(badges, fmt.Sprintf("%dD OLD", days))
+			break
+		}
+	}
+	for _, days := range []int{100, 30, 14, 7} {
+		if snap.BestStreak >= days {
+			badges = append(badges, fmt.Sprintf("%dD STREAK", days))
+			break
+		}
+	}
+	if snap.Bond >= 90 {
+		badges = append(badges, "BESTIES")
+	}
+	if snap.Form > 0 {
+		badges = append(badges, "EVOLVED")
+	}
+	return badges
+}
+
+// MoodAvatar draws the bot's avatar for mood: the species pack's art for
+// that mood ("avatar_<mood>", else "avatar"), or the pet's sprite, on the
+// mood's color.
+func MoodAvatar(snap pet.Snapshot, sp *species.Species, mood string) ([]byte, error) {
+	art := loadArt(sp, "avatar_"+mood, "avatar")
+	if art == nil {
+		col := render.Hex(0xF2F3F5)
+		if snap.Shiny {
+			col = render.Hex(species.ShinyColor)
+		}
+		art = render.Sprite(snap.SpeciesID+":"+snap.Name, col)
+	}
+	return render.Avatar(art, render.Hex(moodColor(mood)))
+}
+
+// loadArt reads the first of the species pack's art keys it has.
+func loadArt(sp *species.Species, keys ...string) image.Image {
+	var path string
+	for _, key := range keys {
+		if path = sp.Art[key]; path != "" {
+			break
+		}
+	}
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		slog.Warn("card: can't open species art", "path", path, "err", err)
+		return nil
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		slog.Warn("card: can't decode species art", "path", path, "err", err)
+		return nil
+	}
+	return img
+}
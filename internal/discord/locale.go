@@ -0,0 +1,70 @@
+package discord
+
+import (
+	"log/slog"
+
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/moorebrett0/pipet/internal/i18n"
+)
+
+// SetLocales registers every locale's command names and descriptions with
+// Discord, which shows each person the one matching their client language,
+// and writes the pet's messages in the templates locale ("" = English).
+// Message templates are shared by every pet in the process.
+func (b *Bot) SetLocales(locales map[string]*i18n.Locale, templates string) {
+	b.mu.Lock()
+	b.locales = locales
+	b.mu.Unlock()
+
+	if templates == "" {
+		i18n.Use(nil)
+		return
+	}
+	l, ok := locales[templates]
+	if !ok {
+		slog.Warn("discord: no locale file for templates, using English", "locale", templates)
+	}
+	i18n.Use(l)
+}
+
+// localize adds each locale's translations to commands. Codes Discord
+// doesn't know are skipped, since it would reject the whole command.
+func localize(commands []*discordgo.ApplicationCommand, locales map[string]*i18n.Locale) {
+	for code, l := range locales {
+		loc := discordgo.Locale(code)
+		if _, ok := discordgo.Locales[loc]; !ok {
+			slog.Warn("discord: unknown Discord locale, skipping its commands", "locale", code)
+			continue
+		}
+		for _, cmd := range commands {
+			t, ok := l.Commands[cmd.Name]
+			if !ok {
+				continue
+			}
+			if t.Name != "" {
+				cmd.NameLocalizations = withLocale(cmd.NameLocalizations, loc, t.Name)
+			}
+			// Context menu commands can't have descriptions
+			if t.Description != "" && cmd.Type != discordgo.MessageApplicationCommand {
+				cmd.DescriptionLocalizations = withLocale(cmd.DescriptionLocalizations, loc, t.Description)
+			}
+			for _, opt := range cmd.Options {
+				if d := t.Options[opt.Name]; d != "" {
+					if opt.DescriptionLocalizations == nil {
+						opt.DescriptionLocalizations = make(map[discordgo.Locale]string)
+					}
+					opt.DescriptionLocalizations[loc] = d
+				}
+			}
+		}
+	}
+}
+
+func withLocale(m *map[discordgo.Locale]string, loc discordgo.Locale, text string) *map[discordgo.Locale]string {
+	if m == nil {
+		m = &map[discordgo.Locale]string{}
+	}
+	(*m)[loc] = text
+	return m
+}
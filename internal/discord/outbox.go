@@ -0,0 +1,157 @@
+package discord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Outbox retry timing and size.
+const (
+	outboxMinBackoff = 5 * time.Second
+	outboxMaxBackoff = 5 * time.Minute
+	outboxMax        = 100
+)
+
+// queuedMessage is a message that couldn't be delivered yet.
+type queuedMessage struct {
+	ChannelID string    `json:"channel_id"`
+	Text      string    `json:"text"`
+	At        time.Time `json:"at"`
+}
+
+// outbox holds messages that failed to send while Discord was unreachable,
+// persisted so they survive a restart, and delivers them once it's back.
+type outbox struct {
+	mu    sync.Mutex
+	path  string
+	queue []queuedMessage
+	wake  chan struct{}
+}
+
+func openOutbox(path string) (*outbox, error) {
+	o := &outbox{path: path, wake: make(chan struct{}, 1)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return o, nil
+		}
+		return nil, fmt.Errorf("read outbox: %w", err)
+	}
+	if err := json.Unmarshal(data, &o.queue); err != nil {
+		return nil, fmt.Errorf("unmarshal outbox: %w", err)
+	}
+	return o, nil
+}
+
+// add queues a message, dropping the oldest if the outbox is full.
+func (o *outbox) add(m queuedMessage) {
+	o.mu.Lock()
+	o.queue = append(o.queue, m)
+	if len(o.queue) > outboxMax {
+		o.queue = o.queue[len(o.queue)-outboxMax:]
+	}
+	o.saveLocked()
+	o.mu.Unlock()
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (o *outbox) peek() (queuedMessage, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.queue) == 0 {
+		return queuedMessage{}, false
+	}
+	return o.queue[0], true
+}
+
+func (o *outbox) pop() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.queue) > 0 {
+		o.queue = o.queue[1:]
+		o.saveLocked()
+	}
+}
+
+// saveLocked writes the queue atomically. Caller must hold o.mu.
+func (o *outbox) saveLocked() {
+	data, err := json.MarshalIndent(o.queue, "", "  ")
+	if err != nil {
+		slog.Error("discord: marshal outbox", "err", err)
+		return
+	}
+	tmp := o.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		slog.Error("discord: write outbox", "err", err)
+		return
+	}
+	if err := os.Rename(tmp, o.path); err != nil {
+		slog.Error("discord: rename outbox", "err", err)
+	}
+}
+
+// run delivers queued messages in order, backing off while Discord is still
+// unreachable. Blocks until ctx is cancelled.
+func (o *outbox) run(ctx context.Context, send func(channelID, text string) error) {
+	backoff := outboxMinBackoff
+	for {
+		m, ok := o.peek()
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-o.wake:
+				continue
+			}
+		}
+
+		err := send(m.ChannelID, delayedText(m))
+		switch {
+		case err == nil:
+			o.pop()
+			backoff = outboxMinBackoff
+			continue
+		case !retryable(err):
+			slog.Warn("discord: dropping queued message", "channel", m.ChannelID, "err", err)
+			o.pop()
+			continue
+		}
+
+		slog.Debug("discord: outbox still can't send", "err", err, "retry_in", backoff)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, outboxMaxBackoff)
+	}
+}
+
+// delayedText notes when a late message was originally meant to go out.
+func delayedText(m queuedMessage) string {
+	return fmt.Sprintf("%s\n-# delayed — written <t:%d:R> while Discord was unreachable", m.Text, m.At.Unix())
+}
+
+// retryable reports whether a send failed because Discord couldn't be
+// reached (worth retrying) rather than because it rejected the message.
+func retryable(err error) bool {
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		code := restErr.Response.StatusCode
+		return code == http.StatusTooManyRequests || code >= 500
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package discord
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Long results shown as paged embeds keep this much text per page (embed
+// descriptions take up to 4096, but this reads better on a phone), and can
+// be paged through for this long.
+const (
+	pageChars = 1500
+	pagerTTL  = 24 * time.Hour
+)
+
+// pager is a long text shown one embed page at a time, with ◀ ▶ buttons.
+type pager struct {
+	title string
+	color int
+	pages []string
+	page  int
+	extra []discordgo.MessageComponent // rows under the page buttons
+	made  time.Time
+}
+
+// setText splits text into pages, keeping the current page if it's still
+// there.
+func (p *pager) setText(text string) {
+	p.pages = splitMessage(text, pageChars)
+	p.page = min(p.page, len(p.pages)-1)
+}
+
+func (p *pager) embed() *discordgo.MessageEmbed {
+	e := &discordgo.MessageEmbed{
+		Title:       p.title,
+		Description: p.pages[p.page],
+		Color:       p.color,
+	}
+	if len(p.pages) > 1 {
+		e.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("page %d of %d", p.page+1, len(p.pages))}
+	}
+	return e
+}
+
+// components returns the page buttons, if there's more than one page, and
+// the extra rows.
+func (p *pager) components(id string) []discordgo.MessageComponent {
+	rows := []discordgo.MessageComponent{}
+	if len(p.pages) > 1 {
+		rows = append(rows, discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "◀", Style: discordgo.SecondaryButton, CustomID: "page:" + id + ":prev", Disabled: p.page == 0},
+			discordgo.Button{Label: "▶", Style: discordgo.SecondaryButton, CustomID: "page:" + id + ":next", Disabled: p.page == len(p.pages)-1},
+		}})
+	}
+	return append(rows, p.extra...)
+}
+
+// newPager keeps a pager for text and returns its ID for the buttons.
+func (r *Router) newPager(title string, color int, text string, extra []discordgo.MessageComponent) (string, *pager) {
+	p := &pager{title: title, color: color, extra: extra, made: time.Now()}
+	p.setText(text)
+
+	r.pagesMu.Lock()
+	defer r.pagesMu.Unlock()
+	for id, old := range r.pagers {
+		if time.Since(old.made) > pagerTTL {
+			delete(r.pagers, id)
+		}
+	}
+	r.pageSeq++
+	id := strconv.Itoa(r.pageSeq)
+	r.pagers[id] = p
+	return id, p
+}
+
+// followupPages posts text after a deferred response as a paged embed.
+func (r *Router) followupPages(i *discordgo.InteractionCreate, title string, color int, text string) {
+	id, p := r.newPager(title, color, text, nil)
+	if _, err := r.bot.session.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Embeds:     []*discordgo.MessageEmbed{p.embed()},
+		Components: p.components(id),
+	}); err != nil {
+		slog.Error("discord: posting pages failed", "err", err)
+	}
+}
+
+// handlePageButton turns the page of a paged embed. Anyone who can see it
+// may page through it.
+func (r *Router) handlePageButton(i *discordgo.InteractionCreate, rest string) {
+	id, dir, _ := strings.Cut(rest, ":")
+	r.pagesMu.Lock()
+	p, ok := r.pagers[id]
+	if ok {
+		switch dir {
+		case "prev":
+			p.page = max(p.page-1, 0)
+		case "next":
+			p.page = min(p.page+1, len(p.pages)-1)
+		}
+	}
+	var embed *discordgo.MessageEmbed
+	var components []discordgo.MessageComponent
+	if ok {
+		embed, components = p.embed(), p.components(id)
+	}
+	r.pagesMu.Unlock()
+
+	if !ok {
+		r.respondEphemeral(i, "these pages are too old to turn. run the command again for fresh ones.")
+		return
+	}
+	r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: components,
+		},
+	})
+}
@@ -1,20 +1,120 @@
 package discord
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"math/rand"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/bwmarrin/discordgo"
 
 	"github.com/moorebrett0/pipet/internal/brain"
+	"github.com/moorebrett0/pipet/internal/cleanup"
+	"github.com/moorebrett0/pipet/internal/contest"
+	"github.com/moorebrett0/pipet/internal/history"
+	"github.com/moorebrett0/pipet/internal/items"
+	"github.com/moorebrett0/pipet/internal/logwatch"
+	"github.com/moorebrett0/pipet/internal/metrics"
+	"github.com/moorebrett0/pipet/internal/monitor"
+	"github.com/moorebrett0/pipet/internal/nest"
 	"github.com/moorebrett0/pipet/internal/pet"
+	"github.com/moorebrett0/pipet/internal/poll"
+	"github.com/moorebrett0/pipet/internal/render"
+	"github.com/moorebrett0/pipet/internal/schedule"
+	"github.com/moorebrett0/pipet/internal/shell"
+	"github.com/moorebrett0/pipet/internal/species"
 )
 
+// maxExportBytes caps the size of an uploaded pet export.
+const maxExportBytes = 1 << 20
+
+// interactionDeadline is how long a slash command's work may run: Discord
+// accepts followups for 15 minutes, less a margin to post the reply.
+const interactionDeadline = 15*time.Minute - 30*time.Second
+
+// messageDeadline bounds the work done for one chat message.
+const messageDeadline = 5 * time.Minute
+
+// /story looks back over storyWindow of events and caps its length.
+const (
+	storyWindow = 48 * time.Hour
+	storyTokens = 250
+)
+
+// Guest visit length limits, in minutes.
+var (
+	minVisitMinutes     = 5.0
+	maxVisitMinutes     = 120.0
+	defaultVisitMinutes = 30
+)
+
+// /logs line count limits.
+var (
+	minLogLines     = 1.0
+	maxLogLines     = 500.0
+	defaultLogLines = 50
+)
+
+// /audit entry count limits.
+var (
+	minAuditEntries     = 1.0
+	maxAuditEntries     = 200.0
+	defaultAuditEntries = 20
+)
+
+// serviceOutputLimit keeps /service replies inside one Discord message.
+const serviceOutputLimit = 1500
+
+// /apt limits: how long refreshing and upgrading may each take, how often
+// the live output is refreshed, and how much of it is shown.
+const (
+	aptTimeout   = 30 * time.Minute
+	aptEditEvery = 5 * time.Second
+	aptTailBytes = 1800
+)
+
+// How often a streamed brain answer is redrawn (Discord rate limits faster
+// edits), and how much of it fits in the message.
+const (
+	thinkingEditEvery = time.Second
+	thinkingTailBytes = 1900
+)
+
+// File writes the brain asks for wait this long for an owner to approve
+// them, and show this much of the new content inline (the rest is attached).
+const (
+	writeApprovalTTL  = 24 * time.Hour
+	writePreviewLimit = 1200
+)
+
+// /shell commands that match a risky pattern wait this long for the owner to
+// press Run.
+const shellConfirmTTL = 10 * time.Minute
+
+// pendingShell is a /shell command waiting on the Run/Cancel buttons.
+type pendingShell struct {
+	command string
+	thread  bool
+	asked   time.Time
+}
+
+// pendingWrite is a file change waiting on the Approve/Deny buttons.
+type pendingWrite struct {
+	brain.FileWrite
+	asked time.Time
+}
+
 // Router dispatches Discord messages and slash commands.
 type Router struct {
 	bot      *Bot
@@ -22,26 +122,143 @@ type Router struct {
 	brain    *brain.Brain // nil if Claude is disabled
 
 	petChatChance float64 // probability of responding to another pet (0-1)
+	memorialPath  string
+	contests      *contest.Board   // nil if contests are disabled
+	polls         *poll.Tracker    // nil if polls are disabled
+	executor      *shell.Executor  // runs approved poll actions
+	schedule      *schedule.Book   // nil if scheduled tasks are disabled
+	monitor       *monitor.Monitor // hardware details for /sysinfo
+	logUnits      []string         // systemd units /logs may read
+	serviceUnits  []string         // systemd units /service may control
+	cleanupPaths  []string         // where /feed looks for large files
+	nest          *nest.Nester     // nil if /nest backups are disabled
+	history       *history.Buffer  // nil if /status has no charts
+
+	aptMu sync.Mutex // held while an upgrade runs
 
-	// Anti-loop: cooldown for bot-to-bot responses
+	// The last /feed cleanup preview, the message its buttons are on, and
+	// the pager showing it
+	cleanupMu    sync.Mutex
+	cleanupPlan  []cleanup.Category
+	cleanupMsg   string
+	cleanupPages string
+
+	// Long results shown a page at a time, by button ID
+	pagesMu sync.Mutex
+	pagers  map[string]*pager
+	pageSeq int
+
+	// File writes the brain asked for, by button ID
+	writesMu sync.Mutex
+	writes   map[string]pendingWrite
+	writeSeq int
+
+	// Risky /shell commands waiting to be confirmed, by button ID
+	shellsMu sync.Mutex
+	shells   map[string]pendingShell
+	shellSeq int
+
+	// Anti-loop: cooldown for bot-to-bot responses, per channel
 	mu           sync.Mutex
-	lastBotReply time.Time
+	lastBotReply map[string]time.Time
 	botCooldown  time.Duration
+
+	// Minimum time between one person's commands, by server ("" for the
+	// default), and when each person in each server last ran one
+	cooldowns   map[string]time.Duration
+	lastCommand map[string]time.Time
+
+	// Minimum time between one spectator's uses of a command, by command
+	// name, and when each spectator last used each one
+	commandCooldowns map[string]time.Duration
+	lastUse          map[string]time.Time
+
+	// The first error each interaction being handled ran into, by
+	// interaction ID, so its metric counts it as failed
+	errsMu sync.Mutex
+	errs   map[string]error
+}
+
+// RouterConfig holds optional settings for the router.
+type RouterConfig struct {
+	MemorialPath string           // where /reset archives the previous pet
+	Contests     *contest.Board   // collects other pets' contest entries
+	Polls        *poll.Tracker    // decided polls waiting for /approve
+	Executor     *shell.Executor  // runs approved poll actions
+	Schedule     *schedule.Book   // tasks owners schedule by asking in chat
+	Monitor      *monitor.Monitor // hardware details for /sysinfo
+	LogUnits     []string         // systemd units /logs may read
+	ServiceUnits []string         // systemd units /service may control
+	CleanupPaths []string         // where /feed looks for large files
+	Nest         *nest.Nester     // nil if /nest backups are disabled
+	History      *history.Buffer  // stat samples charted and trended on /status (nil = neither)
+
+	// Minimum time between one person's commands in a server, and
+	// overrides by guild ID (0 = none; owners are never held back)
+	CommandCooldown time.Duration
+	GuildCooldowns  map[string]time.Duration
+
+	// Minimum time between one spectator's uses of each command, by name
+	// (e.g. "pet": 10m), across all servers
+	CommandCooldowns map[string]time.Duration
 }
 
 // NewRouter creates a router and wires it to the bot.
-func NewRouter(bot *Bot, petState *pet.PetState, b *brain.Brain) *Router {
+func NewRouter(bot *Bot, petState *pet.PetState, b *brain.Brain, cfg RouterConfig) *Router {
 	r := &Router{
 		bot:           bot,
 		petState:      petState,
 		brain:         b,
+		memorialPath:  cfg.MemorialPath,
+		contests:      cfg.Contests,
+		polls:         cfg.Polls,
+		executor:      cfg.Executor,
+		schedule:      cfg.Schedule,
+		monitor:       cfg.Monitor,
+		logUnits:      cfg.LogUnits,
+		serviceUnits:  cfg.ServiceUnits,
+		cleanupPaths:  cfg.CleanupPaths,
+		nest:          cfg.Nest,
+		history:       cfg.History,
+		writes:        make(map[string]pendingWrite),
+		shells:        make(map[string]pendingShell),
+		pagers:        make(map[string]*pager),
+		lastBotReply:  make(map[string]time.Time),
 		petChatChance: 0.25,             // 25% chance to respond to another pet
 		botCooldown:   3 * time.Minute,  // don't respond to bots more than once per 3min
+		cooldowns:     map[string]time.Duration{"": cfg.CommandCooldown},
+		lastCommand:   make(map[string]time.Time),
+
+		commandCooldowns: cfg.CommandCooldowns,
+		lastUse:          make(map[string]time.Time),
+		errs:             make(map[string]error),
+	}
+	for guildID, d := range cfg.GuildCooldowns {
+		r.cooldowns[guildID] = d
 	}
 	bot.SetRouter(r)
+	if b != nil {
+		go r.announceWake(b.Awake())
+	}
 	return r
 }
 
+// announceWake has the pet say so when a brain that failed to start at boot
+// finally comes online.
+func (r *Router) announceWake(awake <-chan struct{}) {
+	select {
+	case <-awake:
+		return // up from the start, nothing to announce
+	default:
+	}
+	<-awake
+	if !r.petState.IsOnboarded() {
+		return
+	}
+	snap := r.petState.Snapshot()
+	r.bot.SendMessage(r.bot.channelID, TemplateBrainAwake(snap, getSpecies(snap)))
+}
+
 // HandleInteraction dispatches a slash command interaction.
 func (r *Router) HandleInteraction(i *discordgo.InteractionCreate) {
 	data := i.ApplicationCommandData()
@@ -49,146 +266,1961 @@ func (r *Router) HandleInteraction(i *discordgo.InteractionCreate) {
 	isOwner := r.bot.IsOwner(userID)
 
 	snap := r.petState.Snapshot()
-	sp := getSpecies(snap.SpeciesID)
+	sp := getSpecies(snap)
+
+	if !r.petState.IsOnboarded() && data.Name != "help" && data.Name != "species" && data.Name != "adopt" {
+		r.respond(i, TemplateEgg())
+		return
+	}
+
+	if !isOwner {
+		if wait := r.tooSoon(data.Name, userID); wait > 0 {
+			r.respondEphemeral(i, TemplateTooSoon(snap, sp, data.Name, wait))
+			return
+		}
+		if r.coolingDown(i.GuildID, userID) {
+			r.respondEphemeral(i, TemplateCooldown(snap, sp))
+			return
+		}
+		r.noteUse(data.Name, userID)
+	}
+	if isOwner {
+		defer r.recordCare(i.ChannelID)
+	}
+	start := time.Now()
+	r.track(i)
+	defer func() { metrics.Since("cmd."+data.Name, start, r.untrack(i)) }()
+
+	// Followups stop working once the interaction token expires, so give up
+	// on any work still running by then
+	ctx, cancel := context.WithTimeout(context.Background(), interactionDeadline)
+	defer cancel()
+
+	switch data.Name {
+	case "status":
+		embed := StatusEmbed(snap, sp)
+		if r.brain != nil {
+			if since, until, down := r.brain.Outage(); down {
+				embed.Fields = append(embed.Fields, OutageField(since, until))
+			}
+		}
+		if r.history != nil {
+			if samples := r.history.Since(time.Now().Add(-24 * time.Hour)); len(samples) > 1 {
+				embed.Fields = append(embed.Fields, TrendField(samples))
+			}
+		}
+		data := &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{embed},
+			Components: careButtons(),
+		}
+		if png := r.statusChart(); png != nil {
+			embed.Image = &discordgo.MessageEmbedImage{URL: "attachment://status.png"}
+			data.Files = []*discordgo.File{{Name: "status.png", ContentType: "image/png", Reader: bytes.NewReader(png)}}
+		}
+		r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: data,
+		})
+
+	case "mood":
+		r.respond(i, fmt.Sprintf("%s %s is feeling %s", moodEmoji(snap.Mood), snap.Name, snap.Mood))
+
+	case "pet":
+		r.carePet(i, userID, sp)
+
+	case "feed":
+		r.careFeed(ctx, i, userID, snap, sp)
+
+	case "heal":
+		if !r.canCare(userID, pet.RoleMedic) {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		r.petState.Contribute(userID, pet.RoleMedic)
+		if r.brain != nil {
+			prompt := "Diagnose any resource issues on the Pi. Check memory pressure, CPU hogs, disk space, temperature. Suggest fixes for anything concerning. Be concise."
+			if snap.Illness != nil {
+				prompt = illnessPrompt(snap.Illness.Ailment())
+			}
+			r.respondDeferred(i)
+			r.noteBacklog(i, snap, sp)
+			r.askInBackground(i, "diagnosing the Pi", prompt,
+				func(resp string, shown bool, err error) {
+					if errors.Is(err, brain.ErrOutage) {
+						_, until, _ := r.brain.Outage()
+						r.followup(i, TemplateBrainOutage(r.petState.Snapshot(), sp, until))
+						return
+					}
+					if err != nil {
+						slog.Error("router: brain error on heal", "err", err)
+						r.fail(i, err)
+						r.followup(i, "I tried to check but something went wrong...")
+						return
+					}
+					if shown {
+						// The answer goes in pages; drop the live copy
+						r.bot.session.InteractionResponseDelete(i.Interaction)
+					}
+					snap := r.petState.Snapshot()
+					if snap.Illness != nil && r.petState.Treat() {
+						resp += "\n\n" + TemplateTreated(snap, sp, snap.Illness.Ailment())
+					}
+					r.followupPages(i, fmt.Sprintf("%s %s's checkup", sp.Emoji, snap.Name), moodColor(snap.Mood), resp)
+				})
+		} else if snap.Illness != nil {
+			r.respond(i, fmt.Sprintf("%s I'd need my brain connected to find what's making me ill. medicine from `/shop` would help, though.", sp.Emoji))
+		} else {
+			r.respond(i, fmt.Sprintf("%s I'd need my brain connected to diagnose things. (No Claude API key configured)", sp.Emoji))
+		}
+
+	case "play":
+		activity := "something fun"
+		if len(data.Options) > 0 {
+			activity = data.Options[0].StringValue()
+		}
+		r.carePlay(i, isOwner, activity, snap, sp)
+
+	case "ask":
+		r.handleAsk(i, data, isOwner, snap, sp)
+
+	case askAboutCommand:
+		r.handleAskAbout(ctx, i, data, isOwner, snap, sp)
+
+	case "help":
+		r.respond(i, TemplateHelp(snap, sp))
+
+	case "revive":
+		if !r.canCare(userID, pet.RoleMedic) {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		if snap.IsAlive {
+			r.respond(i, fmt.Sprintf("%s %s is alive and well!", sp.Emoji, snap.Name))
+		} else {
+			r.petState.Revive()
+			r.petState.Contribute(userID, pet.RoleMedic)
+			snap = r.petState.Snapshot()
+			r.respond(i, fmt.Sprintf("\u2728 %s has been revived! %s", snap.Name, sp.Verbs.Happy))
+		}
+
+	case "species":
+		if len(data.Options) > 0 {
+			if preview, ok := species.Registry[data.Options[0].StringValue()]; ok {
+				r.respondEmbed(i, SpeciesEmbed(preview))
+				return
+			}
+		}
+		r.respond(i, TemplateSpeciesList())
+
+	case "export":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		var memorials []pet.Memorial
+		if r.memorialPath != "" {
+			var err error
+			if memorials, err = pet.LoadMemorials(r.memorialPath); err != nil {
+				slog.Error("router: failed to load memorials", "err", err)
+				r.fail(i, err)
+			}
+		}
+		data, err := pet.NewExport(r.petState, memorials).Marshal()
+		if err != nil {
+			slog.Error("router: export failed", "err", err)
+			r.fail(i, err)
+			r.respondEphemeral(i, "Something went wrong packing my bags...")
+			return
+		}
+		r.respondFile(i, fmt.Sprintf("%s here's everything you need to move me to another Pi. use `/adopt` there.", sp.Emoji),
+			strings.ToLower(snap.Name)+"-export.json", data)
+
+	case "adopt":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		if r.petState.IsOnboarded() {
+			r.respondEphemeral(i, fmt.Sprintf("%s %s already lives here. use `/reset` first to make room.", sp.Emoji, snap.Name))
+			return
+		}
+		r.handleAdopt(ctx, i, data)
+
+	case "gift":
+		if !isOwner && !r.bot.allowSpectatorPet {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		r.handleGift(i, data, snap, sp, isOwner)
+
+	case "inventory":
+		r.respond(i, TemplateInventory(snap, sp))
+
+	case "quest":
+		r.respond(i, TemplateQuest(snap, sp))
+
+	case "roles":
+		r.handleRoles(i, data, snap, sp, isOwner)
+
+	case "transfer":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		r.handleTransfer(i, data, snap, sp, userID)
+
+	case "visit":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		r.handleVisit(i, data, snap, sp)
+
+	case "diary":
+		r.respond(i, TemplateDiary(snap, sp, r.petState.DiaryEntries()))
+
+	case "debug":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		r.respondEmbed(i, DebugEmbed(sp, metrics.Snapshot()))
+
+	case "uptime":
+		r.respondEmbed(i, UptimeEmbed(snap, sp, monitor.BootTime(), monitor.RebootReason(), monitor.ProcessUptime()))
+
+	case "sysinfo":
+		if r.monitor == nil {
+			r.respondEphemeral(i, "system monitoring isn't running.")
+			return
+		}
+		r.respondEmbed(i, SysinfoEmbed(sp, r.monitor.Info()))
+
+	case "logs":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		r.handleLogs(ctx, i, data, snap, sp)
+
+	case "service":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		r.handleService(ctx, i, data, snap, sp, userID)
+
+	case "apt":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		r.handleApt(ctx, i, snap, sp)
+
+	case "shell":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		r.handleShell(ctx, i, data, snap, sp, userID)
+
+	case "audit":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		r.handleAudit(i, data, snap, sp)
+
+	case "nest":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		r.handleNest(ctx, i, data, snap, sp)
+
+	case "remember":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		f, err := r.petState.Remember(data.Options[0].StringValue(), userID)
+		if err != nil {
+			r.respondEphemeral(i, fmt.Sprintf("%s %s.", sp.Emoji, err))
+			return
+		}
+		r.respond(i, TemplateRemembered(snap, sp, f))
+
+	case "forget":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		f, ok := r.petState.Forget(int(data.Options[0].IntValue()))
+		if !ok {
+			r.respondEphemeral(i, fmt.Sprintf("%s there's no fact #%d. check `/memories`.", sp.Emoji, data.Options[0].IntValue()))
+			return
+		}
+		r.respond(i, TemplateForgot(snap, sp, f))
+
+	case "memories":
+		r.respond(i, TemplateMemories(snap, sp, r.petState.KnownFacts()))
+
+	case "budget":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		if r.brain == nil {
+			r.respondEphemeral(i, fmt.Sprintf("%s no AI configured, so nothing to spend.", sp.Emoji))
+			return
+		}
+		r.respondEphemeral(i, TemplateBudget(snap, sp, r.brain.Usage(), time.Now()))
+
+	case "model":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		if r.brain == nil {
+			r.respondEphemeral(i, fmt.Sprintf("%s no AI configured, so there's no model to pick.", sp.Emoji))
+			return
+		}
+		o, ok := optionMap(data.Options)["use"]
+		if !ok {
+			r.respondEphemeral(i, TemplateModels(snap, sp, r.brain.Model(), r.brain.Models()))
+			return
+		}
+		m, err := brain.ParseModel(o.StringValue())
+		if err != nil {
+			r.respondEphemeral(i, fmt.Sprintf("%s %v", sp.Emoji, err))
+			return
+		}
+		// Starting a provider can mean a round trip to the model server
+		r.respondDeferred(i)
+		if err := r.brain.SetModel(ctx, m); err != nil {
+			slog.Warn("router: couldn't switch model", "model", m, "err", err)
+			r.fail(i, err)
+			r.followup(i, fmt.Sprintf("%s couldn't switch to %s: %v", sp.Emoji, m, err))
+			return
+		}
+		r.followup(i, TemplateModelSwitched(snap, sp, m))
+
+	case "tasks":
+		if r.brain == nil {
+			r.respondEphemeral(i, fmt.Sprintf("%s I'd need my brain connected to have anything running.", sp.Emoji))
+			return
+		}
+		r.respond(i, TemplateJobs(snap, sp, r.brain.Jobs()))
+
+	case "temps":
+		if r.monitor == nil {
+			r.respondEphemeral(i, "system monitoring isn't running.")
+			return
+		}
+		r.handleTemps(i, sp)
+
+	case "card":
+		png, err := PetCard(snap, sp).Render()
+		if err != nil {
+			slog.Error("router: rendering card failed", "err", err)
+			r.fail(i, err)
+			r.respondEphemeral(i, fmt.Sprintf("%s the printer jammed. try again in a bit.", sp.Emoji))
+			return
+		}
+		r.respondEmbedFile(i, &discordgo.MessageEmbed{
+			Title: fmt.Sprintf("%s %s", sp.Emoji, snap.Name),
+			Color: moodColor(snap.Mood),
+			Image: &discordgo.MessageEmbedImage{URL: "attachment://card.png"},
+		}, "card.png", "image/png", png)
+
+	case "story":
+		if r.brain == nil {
+			r.respond(i, fmt.Sprintf("%s I'd need my brain connected to tell stories. (No Claude API key configured) try `/status` instead.", sp.Emoji))
+			return
+		}
+		r.respondDeferred(i)
+		r.noteBacklog(i, snap, sp)
+		story, err := r.brain.TellStory(r.brainContext(ctx, i.ChannelID, userID, interactionUsername(i)), r.petState.EventsSince(time.Now().Add(-storyWindow)), storyTokens)
+		if err != nil {
+			slog.Warn("router: story failed", "err", err)
+			r.fail(i, err)
+			r.followup(i, fmt.Sprintf("%s %s loses the thread halfway through. try `/status` instead.", sp.Emoji, snap.Name))
+			return
+		}
+		r.followup(i, TemplateStory(snap, sp, story))
+
+	case "approve":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		r.handleApprove(ctx, i, snap, sp)
+
+	case "schedule":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		r.handleSchedule(i, data, snap, sp)
+
+	case "balance":
+		r.respondEphemeral(i, TemplateBalance(r.petState.Balance(userID)))
+
+	case "shop":
+		r.handleShop(i, data, snap, sp, userID)
+
+	case "reset":
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		confirm := ""
+		if len(data.Options) > 0 {
+			confirm = strings.TrimSpace(data.Options[0].StringValue())
+		}
+		if !strings.EqualFold(confirm, snap.Name) {
+			r.respondEphemeral(i, fmt.Sprintf("%s this archives %s to the memorial and starts over with a fresh egg. type `/reset confirm:%s` if you really mean it.",
+				sp.Emoji, snap.Name, snap.Name))
+			return
+		}
+		m := r.petState.Reset("reset")
+		if r.memorialPath != "" {
+			if err := pet.AppendMemorial(r.memorialPath, m); err != nil {
+				slog.Error("router: failed to archive memorial", "err", err)
+				r.fail(i, err)
+			}
+		}
+		r.respond(i, TemplateResetMessage(snap, sp))
+
+	default:
+		cmd, ok := sp.Command(data.Name)
+		if !ok {
+			r.respond(i, "Unknown command.")
+			return
+		}
+		if !r.canCare(userID, pet.RoleGroomer) && !r.bot.allowSpectatorPet {
+			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+			return
+		}
+		if !snap.IsAlive {
+			r.respond(i, TemplateDeathMessage(snap, sp))
+			return
+		}
+		r.petState.ApplyEffects(cmd.Effects)
+		r.petState.Contribute(userID, pet.RoleGroomer)
+		r.respond(i, TemplateSpeciesCommand(r.petState.Snapshot(), sp, cmd))
+	}
+}
+
+// canCare reports whether a user may do a role's job: owners can do
+// everything, caretakers can do their own.
+func (r *Router) canCare(userID, role string) bool {
+	return r.bot.IsOwner(userID) || r.petState.HasRole(userID, role)
+}
+
+// handleRoles lists caretaker roles, or (owner only) toggles a user's role.
+func (r *Router) handleRoles(i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData, snap pet.Snapshot, sp *species.Species, isOwner bool) {
+	opts := optionMap(data.Options)
+	roleOpt, hasRole := opts["role"]
+	userOpt, hasUser := opts["user"]
+	if !hasRole || !hasUser {
+		r.respond(i, TemplateRoles(snap, sp))
+		return
+	}
+	if !isOwner {
+		r.respondEphemeral(i, fmt.Sprintf("%s only my owner hands out jobs around here.", sp.Emoji))
+		return
+	}
+	role := roleOpt.StringValue()
+	user := userOpt.UserValue(r.bot.session)
+	if !pet.IsRole(role) || user == nil {
+		r.respondEphemeral(i, "I don't know that role.")
+		return
+	}
+	if r.petState.ToggleRole(user.ID, role) {
+		r.respond(i, fmt.Sprintf("%s <@%s> is now %s's %s!", sp.Emoji, user.ID, snap.Name, role))
+	} else {
+		r.respond(i, fmt.Sprintf("%s <@%s> is off %s duty.", sp.Emoji, user.ID, role))
+	}
+}
+
+// handleTransfer swaps the calling owner for a new one, once confirmed with
+// the pet's name. Other owners keep their place.
+func (r *Router) handleTransfer(i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData, snap pet.Snapshot, sp *species.Species, userID string) {
+	opts := optionMap(data.Options)
+	var to *discordgo.User
+	if o, ok := opts["user"]; ok {
+		to = o.UserValue(r.bot.session)
+	}
+	switch {
+	case to == nil:
+		r.respondEphemeral(i, "who should I go to?")
+		return
+	case to.Bot:
+		r.respondEphemeral(i, fmt.Sprintf("%s pets can't own pets. yet.", sp.Emoji))
+		return
+	case r.bot.IsOwner(to.ID):
+		r.respondEphemeral(i, fmt.Sprintf("%s <@%s> is already one of my owners.", sp.Emoji, to.ID))
+		return
+	}
+
+	confirm := ""
+	if o, ok := opts["confirm"]; ok {
+		confirm = strings.TrimSpace(o.StringValue())
+	}
+	if !strings.EqualFold(confirm, snap.Name) {
+		r.respondEphemeral(i, fmt.Sprintf("%s this hands %s over to <@%s> and you stop being an owner. type `/transfer user:@%s confirm:%s` if you really mean it.",
+			sp.Emoji, snap.Name, to.ID, to.Username, snap.Name))
+		return
+	}
+
+	var owners []string
+	for _, id := range r.bot.Owners() {
+		if id != userID {
+			owners = append(owners, id)
+		}
+	}
+	owners = append(owners, to.ID)
+	r.bot.SetOwners(owners)
+	r.petState.SetOwners(owners)
+	slog.Info("router: ownership transferred", "from", userID, "to", to.ID)
+
+	r.respond(i, TemplateTransfer(snap, sp, userID, to.ID))
+}
+
+// handleVisit sends the pet to another channel for a while. It says hello
+// there with an idle behavior, answers mentions until time's up, then says
+// goodbye and comes home.
+func (r *Router) handleVisit(i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData, snap pet.Snapshot, sp *species.Species) {
+	opts := optionMap(data.Options)
+	var ch *discordgo.Channel
+	if o, ok := opts["channel"]; ok {
+		ch = o.ChannelValue(r.bot.session)
+	}
+	if ch == nil || ch.ID == r.bot.channelID || (ch.GuildID != "" && ch.GuildID != i.GuildID) {
+		r.respondEphemeral(i, fmt.Sprintf("%s I can only visit other channels in this server.", sp.Emoji))
+		return
+	}
+	if _, listens := r.bot.Rules(ch.ID); listens {
+		r.respondEphemeral(i, fmt.Sprintf("%s I already hang out in <#%s>, no visit needed.", sp.Emoji, ch.ID))
+		return
+	}
+	if !snap.IsAlive {
+		r.respond(i, TemplateDeathMessage(snap, sp))
+		return
+	}
+	minutes := defaultVisitMinutes
+	if o, ok := opts["minutes"]; ok {
+		minutes = int(o.IntValue())
+	}
+	stay := time.Duration(minutes) * time.Minute
+
+	if !r.bot.StartVisit(ch.ID, time.Now().Add(stay)) {
+		r.respondEphemeral(i, fmt.Sprintf("%s %s is already out visiting.", sp.Emoji, snap.Name))
+		return
+	}
+	r.respond(i, TemplateVisitDepart(snap, sp, ch.ID, minutes))
+	r.bot.SendMessage(ch.ID, TemplateVisitArrive(snap, sp, r.bot.channelID))
+
+	time.AfterFunc(stay, func() {
+		r.bot.EndVisit()
+		snap := r.petState.Snapshot()
+		sp := getSpecies(snap)
+		r.bot.SendMessage(ch.ID, fmt.Sprintf("%s %s waves goodbye and heads home.", sp.Emoji, snap.Name))
+		r.bot.SendMessage(r.bot.channelID, fmt.Sprintf("%s %s is back home from <#%s>.", sp.Emoji, snap.Name, ch.ID))
+	})
+}
+
+// HandleVisitMessage answers an @mention in a channel the pet is visiting.
+// Guests get conversation only, never shell access.
+func (r *Router) HandleVisitMessage(m *discordgo.MessageCreate) {
+	if !r.petState.IsOnboarded() {
+		return
+	}
+	snap := r.petState.Snapshot()
+	sp := getSpecies(snap)
+	text := r.bot.StripMention(m.Content)
+	if text == "" || r.brain == nil {
+		r.bot.SendMessage(m.ChannelID, fmt.Sprintf("%s %s %s!", sp.Emoji, snap.Name, sp.Verbs.Greet))
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), messageDeadline)
+	defer cancel()
+	prompt := fmt.Sprintf("[You're visiting another channel as a guest. Message from %s, not your owner — do NOT run shell commands]: %s", m.Author.Username, text)
+	resp, err := r.brain.Ask(r.brainContext(ctx, m.ChannelID, m.Author.ID, m.Author.Username), prompt)
+	if err != nil {
+		slog.Error("router: brain error on visit", "err", err)
+		return
+	}
+	r.bot.SendMessage(m.ChannelID, resp)
+}
+
+// coolingDown reports whether userID ran a command in guildID too recently
+// to run another, and otherwise counts this one. Each server keeps its own
+// clock, so someone busy in one doesn't hold anyone back in another.
+func (r *Router) coolingDown(guildID, userID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wait, ok := r.cooldowns[guildID]
+	if !ok {
+		wait = r.cooldowns[""]
+	}
+	if wait <= 0 {
+		return false
+	}
+	key := guildID + "/" + userID
+	now := time.Now()
+	if now.Sub(r.lastCommand[key]) < wait {
+		return true
+	}
+	r.lastCommand[key] = now
+	return false
+}
+
+// tooSoon returns how much longer userID has to wait before using command
+// again, or 0 if they can now. Only commands with their own cooldown count.
+func (r *Router) tooSoon(command, userID string) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	wait := r.commandCooldowns[command]
+	if wait <= 0 {
+		return 0
+	}
+	return max(wait-time.Since(r.lastUse[command+"/"+userID]), 0)
+}
+
+// noteUse starts userID's cooldown for command, if it has one.
+func (r *Router) noteUse(command, userID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.commandCooldowns[command] > 0 {
+		r.lastUse[command+"/"+userID] = time.Now()
+	}
+}
+
+// HandlePrivateMessage answers a direct message. Only owners get the pet's
+// attention there; it's treated like an @mention in the channel, shell
+// access included, but nobody else sees it.
+func (r *Router) HandlePrivateMessage(m *discordgo.MessageCreate) {
+	text := strings.TrimSpace(m.Content)
+	if text == "" || !r.petState.IsOnboarded() {
+		return
+	}
+	snap := r.petState.Snapshot()
+	sp := getSpecies(snap)
+	if !r.bot.IsOwner(m.Author.ID) {
+		r.bot.SendMessage(m.ChannelID, TemplateDMStranger(snap, sp, r.bot.channelID))
+		return
+	}
+	defer r.recordCare(m.ChannelID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), messageDeadline)
+	defer cancel()
+	r.handleDirectMessage(ctx, m, text)
+}
+
+// recordCare counts today toward the owner care streak and announces
+// milestones or a streak that lapsed.
+func (r *Router) recordCare(channelID string) {
+	if !r.petState.IsOnboarded() {
+		return
+	}
+	res := r.petState.RecordCare(time.Now())
+	if !res.NewDay {
+		return
+	}
+	snap := r.petState.Snapshot()
+	sp := getSpecies(snap)
+	switch {
+	case res.Broken >= 3:
+		r.bot.SendMessage(channelID, TemplateStreakBroken(snap, sp, res.Broken))
+	case res.Milestone:
+		r.bot.SendMessage(channelID, TemplateStreakMilestone(snap, sp, res.Streak))
+	}
+}
+
+// handleGift gives the pet an item from a server member, or (owner only)
+// sends one of the pet's items to another pet in the channel.
+func (r *Router) handleGift(i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData, snap pet.Snapshot, sp *species.Species, isOwner bool) {
+	opts := optionMap(data.Options)
+	var item *items.Item
+	if o, ok := opts["item"]; ok {
+		item = items.Catalog[o.StringValue()]
+	}
+	if item == nil {
+		r.respondEphemeral(i, "I don't know that item.")
+		return
+	}
+
+	var to *discordgo.User
+	if o, ok := opts["to"]; ok {
+		to = o.UserValue(r.bot.session)
+	}
+
+	switch {
+	case to == nil || to.ID == r.bot.BotUserID():
+		if !r.receiveGift(item) {
+			r.respondEphemeral(i, fmt.Sprintf("%s medicine has to come from `/shop`.", sp.Emoji))
+			return
+		}
+		r.respond(i, TemplateGiftReceived(r.petState.Snapshot(), sp, interactionUsername(i), item))
+
+	case to.Bot:
+		if !isOwner {
+			r.respondEphemeral(i, fmt.Sprintf("%s only my owner can give away my stuff.", sp.Emoji))
+			return
+		}
+		if item.Cures {
+			// The other pet would refuse it, and the medicine would be lost
+			r.respondEphemeral(i, fmt.Sprintf("%s medicine can't be gifted — other pets have to get theirs from `/shop`.", sp.Emoji))
+			return
+		}
+		if !r.petState.TakeItem(item.ID) {
+			r.respondEphemeral(i, fmt.Sprintf("%s I don't have a %s to give.", sp.Emoji, strings.ToLower(item.Name)))
+			return
+		}
+		gift := items.Gift{ItemID: item.ID, ToID: to.ID, From: snap.Name}
+		r.respond(i, TemplateGiftSent(snap, sp, to.Username, item)+"\n"+items.FormatGift(gift))
+
+	default:
+		r.respondEphemeral(i, "gifts are for pets — leave `to` empty to give me something, or pick another pet.")
+	}
+}
+
+// receiveGift gives the pet an item, from a member's /gift or another pet.
+// Consumables take effect at once; anything else goes in the inventory.
+// Medicine only comes from /shop, so it's refused and false returned.
+func (r *Router) receiveGift(item *items.Item) bool {
+	if item.Cures {
+		return false
+	}
+	if item.Consumable {
+		r.petState.ApplyEffects(item.Effects)
+	} else {
+		r.petState.AddItem(item.ID, 1)
+		r.petState.TouchInteraction()
+	}
+	return true
+}
+
+// handleShop lists the shop, or spends the caller's shells on an item, a
+// revive, or a skin.
+func (r *Router) handleShop(i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData, snap pet.Snapshot, sp *species.Species, userID string) {
+	o, ok := optionMap(data.Options)["buy"]
+	if !ok {
+		r.respondEphemeral(i, TemplateShop(snap, sp, r.petState.Balance(userID)))
+		return
+	}
+	choice := o.StringValue()
+	buyer := interactionUsername(i)
+
+	var price int
+	var item *items.Item
+	skinID, isSkin := strings.CutPrefix(choice, "skin:")
+	switch {
+	case choice == "revive":
+		if snap.IsAlive {
+			r.respondEphemeral(i, fmt.Sprintf("%s %s is alive and well — save your %s!", sp.Emoji, snap.Name, items.CurrencyName))
+			return
+		}
+		price = items.RevivePrice
+	case isSkin:
+		if _, ok := sp.Skins[skinID]; !ok {
+			r.respondEphemeral(i, fmt.Sprintf("that skin doesn't come in %s.", sp.Name))
+			return
+		}
+		if snap.Skin == skinID {
+			r.respondEphemeral(i, fmt.Sprintf("%s %s is already wearing that.", sp.Emoji, snap.Name))
+			return
+		}
+		price = items.SkinPrice
+	default:
+		item = items.Catalog[choice]
+		price, ok = items.Prices[choice]
+		if item == nil || !ok {
+			r.respondEphemeral(i, "that's not for sale.")
+			return
+		}
+		if !snap.IsAlive {
+			r.respondEphemeral(i, fmt.Sprintf("%s %s is in no state for presents. a revive might help.", sp.Emoji, snap.Name))
+			return
+		}
+	}
+
+	if !r.petState.Spend(userID, price) {
+		r.respondEphemeral(i, fmt.Sprintf("that costs %s %d %s and you have %d.",
+			items.CurrencyEmoji, price, items.CurrencyName, r.petState.Balance(userID)))
+		return
+	}
+
+	switch {
+	case choice == "revive":
+		r.petState.Revive()
+		snap = r.petState.Snapshot()
+		r.respond(i, fmt.Sprintf("\u2728 %s spent %d %s and %s has been revived! %s",
+			buyer, price, items.CurrencyName, snap.Name, sp.Verbs.Happy))
+	case isSkin:
+		r.petState.SetSkin(skinID)
+		snap = r.petState.Snapshot()
+		r.respond(i, TemplateSkinPurchase(snap, getSpecies(snap), buyer, skinID))
+	case item.Consumable:
+		r.petState.ApplyEffects(item.Effects)
+		msg := TemplateGiftReceived(r.petState.Snapshot(), sp, buyer, item)
+		if item.Cures {
+			if ailment, ok := r.petState.Cure(); ok {
+				msg += "\n" + TemplateCured(r.petState.Snapshot(), sp, ailment)
+			} else {
+				msg += "\n" + TemplateNotIll(snap, sp)
+			}
+		}
+		r.respond(i, msg)
+	default:
+		r.petState.AddItem(item.ID, 1)
+		r.petState.TouchInteraction()
+		r.respond(i, TemplateGiftReceived(r.petState.Snapshot(), sp, buyer, item))
+	}
+}
+
+// handleLogs posts the tail of an allowlisted unit's journal into a thread,
+// as a code block if it fits in a message and as an attachment otherwise.
+func (r *Router) handleLogs(ctx context.Context, i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData, snap pet.Snapshot, sp *species.Species) {
+	opts := optionMap(data.Options)
+	var unit string
+	if o, ok := opts["unit"]; ok {
+		unit = o.StringValue()
+	}
+	if r.executor == nil || !slices.Contains(r.logUnits, unit) {
+		r.respondEphemeral(i, fmt.Sprintf("%s I'm not allowed to read that one. (add it to `shell.log_units`)", sp.Emoji))
+		return
+	}
+	lines := defaultLogLines
+	if o, ok := opts["lines"]; ok {
+		lines = int(o.IntValue())
+	}
+
+	r.respondDeferred(i)
+	out, err := logwatch.Tail(ctx, r.executor, unit, lines)
+	if err != nil {
+		slog.Warn("router: reading logs failed", "unit", unit, "err", err)
+		r.fail(i, err)
+		r.followup(i, fmt.Sprintf("%s couldn't read the logs for `%s`: %v", sp.Emoji, unit, err))
+		return
+	}
+	if strings.TrimSpace(out) == "" {
+		r.followup(i, fmt.Sprintf("%s `%s` hasn't logged anything.", sp.Emoji, unit))
+		return
+	}
+
+	msg, err := r.bot.session.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: fmt.Sprintf("\U0001F4DC %s dug up the last %d lines of `%s`", snap.Name, lines, unit),
+	})
+	if err != nil {
+		slog.Error("discord: followup failed", "err", err)
+		r.fail(i, err)
+		return
+	}
+	target := msg.ChannelID
+	if r.bot.useThreads {
+		if threadID, err := r.bot.CreateThread(msg.ChannelID, msg.ID, fmt.Sprintf("%s %s logs", sp.Emoji, unit)); err == nil {
+			target = threadID
+		} else {
+			slog.Error("discord: create thread failed", "err", err)
+		}
+	}
+
+	if block := "```\n" + out + "\n```"; len(block) <= 2000 {
+		r.bot.SendMessage(target, block)
+		return
+	}
+	if err := r.bot.SendFile(target, "", unit+".log", []byte(out)); err != nil {
+		slog.Error("router: posting logs failed", "err", err)
+		r.fail(i, err)
+	}
+}
+
+// throttleLimitC is where the Pi firmware starts throttling the CPU, drawn
+// as a warning line on the /temps chart.
+const throttleLimitC = 80
+
+// handleTemps shows every sensor's reading and a 24h chart of the CPU
+// temperature, with throttling marked.
+func (r *Router) handleTemps(i *discordgo.InteractionCreate, sp *species.Species) {
+	hist := r.monitor.TempHistory()
+	points := make([]render.Point, len(hist))
+	for n, s := range hist {
+		points[n] = render.Point{At: s.At, Value: s.TempC, Flag: s.Throttled&0xF != 0}
+	}
+	chart := render.Chart{Title: "CPU TEMPERATURE - LAST 24H", Unit: "°", Span: 24 * time.Hour, Limit: throttleLimitC}
+	png, err := chart.Render(points, time.Now())
+	if err != nil {
+		slog.Error("router: rendering temperature chart failed", "err", err)
+		r.fail(i, err)
+		r.respondEmbed(i, TempsEmbed(sp, monitor.Sensors(), r.monitor.Stats().Throttled, ""))
+		return
+	}
+	r.respondEmbedFile(i, TempsEmbed(sp, monitor.Sensors(), r.monitor.Stats().Throttled, "temps.png"), "temps.png", "image/png", png)
+}
+
+// statusChart draws the last day of CPU, temperature, and happiness as
+// sparklines for /status. Nil if there's no history to draw yet.
+func (r *Router) statusChart() []byte {
+	if r.history == nil {
+		return nil
+	}
+	now := time.Now()
+	samples := r.history.Since(now.Add(-24 * time.Hour))
+	if len(samples) < 2 {
+		return nil
+	}
+	line := func(label, unit string, col int, field func(history.Sample) float64) render.Sparkline {
+		points := make([]render.Point, len(samples))
+		for n, s := range samples {
+			points[n] = render.Point{At: s.At, Value: field(s)}
+		}
+		return render.Sparkline{Label: label, Unit: unit, Points: points, Color: render.Hex(col)}
+	}
+	png, err := render.RenderSparklines([]render.Sparkline{
+		line("CPU 24H", "%", 0x5865F2, func(s history.Sample) float64 { return s.CPUPercent }),
+		line("TEMP 24H", "°", 0xF1C40F, func(s history.Sample) float64 { return s.TempC }),
+		line("HAPPY 24H", "%", 0x2ECC71, func(s history.Sample) float64 { return s.Happiness }),
+	}, 24*time.Hour, now)
+	if err != nil {
+		slog.Error("router: rendering status chart failed", "err", err)
+		return nil
+	}
+	return png
+}
+
+// handleService checks on, starts, restarts, or stops an allowlisted unit.
+// It runs through the executor, so each command lands in the audit log.
+func (r *Router) handleService(ctx context.Context, i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData, snap pet.Snapshot, sp *species.Species, userID string) {
+	opts := optionMap(data.Options)
+	var unit, action string
+	if o, ok := opts["name"]; ok {
+		unit = o.StringValue()
+	}
+	if o, ok := opts["action"]; ok {
+		action = o.StringValue()
+	}
+	if r.executor == nil || !slices.Contains(shell.ServiceActions, action) || !slices.Contains(r.serviceUnits, unit) {
+		r.respondEphemeral(i, fmt.Sprintf("%s I'm not allowed to touch that one. (add it to `shell.service_units`)", sp.Emoji))
+		return
+	}
+
+	r.respondDeferred(i)
+	slog.Info("router: service command", "user", userID, "unit", unit, "action", action)
+	out, err := r.executor.Service(ctx, unit, action)
+	if err != nil {
+		slog.Warn("router: service command failed", "unit", unit, "action", action, "err", err)
+		r.fail(i, err)
+	}
+	if action != "status" {
+		r.petState.LogEvent(fmt.Sprintf("was asked to %s %s", action, unit))
+	}
+	r.followup(i, TemplateService(snap, sp, unit, action, shell.Condense(out, serviceOutputLimit), err))
+}
+
+// handleApt lists pending package upgrades and asks for the go-ahead with
+// buttons. The upgrade itself runs from the button press.
+func (r *Router) handleApt(ctx context.Context, i *discordgo.InteractionCreate, snap pet.Snapshot, sp *species.Species) {
+	if r.executor == nil {
+		r.respondEphemeral(i, "the shell isn't enabled.")
+		return
+	}
+
+	r.respondDeferred(i)
+	ups, err := r.executor.PendingUpgrades(ctx, aptTimeout)
+	if err != nil {
+		slog.Warn("router: checking for upgrades failed", "err", err)
+		r.fail(i, err)
+		r.followup(i, fmt.Sprintf("%s couldn't check for updates: %v", sp.Emoji, err))
+		return
+	}
+	if len(ups) == 0 {
+		r.followup(i, TemplateAptUpToDate(snap, sp))
+		return
+	}
+
+	_, err = r.bot.session.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: TemplateAptPending(snap, sp, ups),
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				discordgo.Button{Label: "Upgrade", Style: discordgo.SuccessButton, CustomID: "apt:upgrade"},
+				discordgo.Button{Label: "Not now", Style: discordgo.SecondaryButton, CustomID: "apt:cancel"},
+			}},
+		},
+	})
+	if err != nil {
+		slog.Error("router: failed to send upgrade list", "err", err)
+		r.fail(i, err)
+	}
+}
+
+// runUpgrade applies pending upgrades after an owner approved them, showing
+// the tail of apt's output live in a thread and attaching the full log when
+// it doesn't fit.
+func (r *Router) runUpgrade(i *discordgo.InteractionCreate, snap pet.Snapshot, sp *species.Species) {
+	ctx, cancel := context.WithTimeout(context.Background(), aptTimeout)
+	defer cancel()
+
+	target := i.ChannelID
+	if r.bot.useThreads && i.Message != nil {
+		if threadID, err := r.bot.CreateThread(i.ChannelID, i.Message.ID, fmt.Sprintf("%s apt upgrade", sp.Emoji)); err == nil {
+			target = threadID
+		} else {
+			slog.Error("discord: create thread failed", "err", err)
+		}
+	}
+	live, err := r.bot.session.ChannelMessageSend(target, "```\nstarting...\n```")
+	if err != nil {
+		slog.Error("router: failed to post upgrade output", "err", err)
+		r.fail(i, err)
+	}
+
+	var out strings.Builder
+	var edited time.Time
+	show := func() {
+		if live == nil {
+			return
+		}
+		tail := out.String()
+		if len(tail) > aptTailBytes {
+			tail = "..." + tail[len(tail)-aptTailBytes:]
+		}
+		if _, err := r.bot.session.ChannelMessageEdit(target, live.ID, "```\n"+tail+"\n```"); err != nil {
+			slog.Warn("router: failed to update upgrade output", "err", err)
+		}
+		edited = time.Now()
+	}
+
+	slog.Info("router: applying upgrades", "user", interactionUserID(i))
+	err = r.executor.ApplyUpgrades(ctx, aptTimeout, func(line string) {
+		out.WriteString(line + "\n")
+		if time.Since(edited) >= aptEditEvery {
+			show()
+		}
+	})
+	show()
+	if err != nil {
+		slog.Warn("router: upgrade failed", "err", err)
+		r.fail(i, err)
+	} else {
+		r.petState.LogEvent("had its packages upgraded")
+	}
+
+	if out.Len() > aptTailBytes {
+		if err := r.bot.SendFile(target, "", "apt-upgrade.log", []byte(out.String())); err != nil {
+			slog.Error("router: posting upgrade log failed", "err", err)
+			r.fail(i, err)
+		}
+	}
+	reboot, pkgs := shell.RebootRequired()
+	r.bot.SendMessage(target, TemplateAptDone(snap, sp, err, reboot, pkgs))
+}
+
+// handleShell runs a command the owner typed straight through the executor,
+// with no AI in between. Anything matching a risky pattern is held until
+// they press Run. Output goes only to them unless they asked for a thread.
+func (r *Router) handleShell(ctx context.Context, i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData, snap pet.Snapshot, sp *species.Species, userID string) {
+	opts := optionMap(data.Options)
+	command := strings.TrimSpace(opts["command"].StringValue())
+	thread := false
+	if o, ok := opts["thread"]; ok {
+		thread = o.BoolValue()
+	}
+	if r.executor == nil {
+		r.respondEphemeral(i, "the shell isn't set up.")
+		return
+	}
+	if err := shell.Check(command); err != nil {
+		r.respondEphemeral(i, fmt.Sprintf("%s I won't run that: %v", sp.Emoji, err))
+		return
+	}
+
+	if pattern := shell.Risky(command); pattern != "" {
+		now := time.Now()
+		r.shellsMu.Lock()
+		for id, p := range r.shells {
+			if now.Sub(p.asked) > shellConfirmTTL {
+				delete(r.shells, id)
+			}
+		}
+		r.shellSeq++
+		id := strconv.Itoa(r.shellSeq)
+		r.shells[id] = pendingShell{command: command, thread: thread, asked: now}
+		r.shellsMu.Unlock()
+
+		r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: TemplateShellConfirm(snap, sp, command, pattern),
+				Flags:   discordgo.MessageFlagsEphemeral,
+				Components: []discordgo.MessageComponent{
+					discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+						discordgo.Button{Label: "Run it", Style: discordgo.DangerButton, CustomID: "shell:run:" + id},
+						discordgo.Button{Label: "Cancel", Style: discordgo.SecondaryButton, CustomID: "shell:cancel:" + id},
+					}},
+				},
+			},
+		})
+		return
+	}
+
+	flags := discordgo.MessageFlagsEphemeral
+	if thread {
+		flags = 0
+	}
+	r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Flags: flags},
+	})
+	r.runShell(ctx, i, command, thread, snap, sp, userID)
+}
+
+// handleShellButton answers the Run/Cancel buttons under a risky /shell
+// command.
+func (r *Router) handleShellButton(i *discordgo.InteractionCreate, rest string, snap pet.Snapshot, sp *species.Species) {
+	action, id, _ := strings.Cut(rest, ":")
+	r.shellsMu.Lock()
+	p, ok := r.shells[id]
+	delete(r.shells, id)
+	r.shellsMu.Unlock()
+	if !ok || time.Since(p.asked) > shellConfirmTTL {
+		r.respondUpdate(i, fmt.Sprintf("%s that command is too old, run `/shell` again.", sp.Emoji))
+		return
+	}
+
+	switch action {
+	case "run":
+		r.respondUpdate(i, fmt.Sprintf("%s running `%s`...", sp.Emoji, p.command))
+		ctx, cancel := context.WithTimeout(context.Background(), interactionDeadline)
+		defer cancel()
+		r.runShell(ctx, i, p.command, p.thread, snap, sp, interactionUserID(i))
+	case "cancel":
+		r.respondUpdate(i, fmt.Sprintf("%s okay, not running `%s`.", sp.Emoji, p.command))
+	}
+}
+
+// runShell runs command and posts its output as a followup, either just to
+// the owner or, if they asked, in a thread everyone can see.
+func (r *Router) runShell(ctx context.Context, i *discordgo.InteractionCreate, command string, thread bool, snap pet.Snapshot, sp *species.Species, userID string) {
+	slog.Info("router: shell command", "user", userID, "cmd", command)
+	out, err := r.executor.Run(ctx, command)
+	if err != nil {
+		slog.Warn("router: shell command failed", "cmd", command, "err", err)
+	}
+	result := TemplateShellOutput(snap, sp, command, out, err)
+
+	if !thread {
+		r.followupEphemeral(i, result)
+		return
+	}
+	msg, ferr := r.bot.session.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content: fmt.Sprintf("%s ran `%s`", sp.Emoji, shell.Condense(command, 200)),
+	})
+	if ferr != nil {
+		slog.Error("discord: followup failed", "err", ferr)
+		r.fail(i, ferr)
+		return
+	}
+	if !r.bot.useThreads {
+		r.followup(i, result)
+		return
+	}
+	threadID, terr := r.bot.CreateThread(msg.ChannelID, msg.ID, fmt.Sprintf("%s %s shell", sp.Emoji, snap.Name))
+	if terr != nil {
+		slog.Error("discord: create thread failed", "err", terr)
+		r.followup(i, result)
+		return
+	}
+	r.bot.SendMessage(threadID, result)
+}
+
+// handleAudit shows the last commands the executor ran, newest first, as a
+// paged embed only the owner sees.
+func (r *Router) handleAudit(i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData, snap pet.Snapshot, sp *species.Species) {
+	if r.executor == nil {
+		r.respondEphemeral(i, "the shell isn't set up.")
+		return
+	}
+	count := defaultAuditEntries
+	if o, ok := optionMap(data.Options)["count"]; ok {
+		count = int(o.IntValue())
+	}
+	entries := r.executor.Recent(time.Time{})
+	entries = entries[max(len(entries)-count, 0):]
+	slices.Reverse(entries)
+
+	id, p := r.newPager(fmt.Sprintf("%s %s's shell log", sp.Emoji, snap.Name), moodColor(snap.Mood), TemplateAudit(entries), nil)
+	r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds:     []*discordgo.MessageEmbed{p.embed()},
+			Components: p.components(id),
+			Flags:      discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
+
+// handleApprove runs the action the channel voted for in the last poll.
+func (r *Router) handleApprove(ctx context.Context, i *discordgo.InteractionCreate, snap pet.Snapshot, sp *species.Species) {
+	if r.polls == nil || r.executor == nil {
+		r.respondEphemeral(i, "polls aren't enabled.")
+		return
+	}
+	opt, ok := r.polls.Take(time.Now())
+	if !ok {
+		r.respondEphemeral(i, fmt.Sprintf("%s there's no poll result waiting on you.", sp.Emoji))
+		return
+	}
+
+	r.respondDeferred(i)
+	out, err := r.executor.Run(ctx, opt.Command)
+	if err != nil {
+		slog.Warn("router: poll action failed", "cmd", opt.Command, "err", err)
+		r.fail(i, err)
+	}
+	r.followupInThread(i, snap, TemplatePollAction(snap, sp, opt, out, err), "poll results")
+}
+
+// handleSchedule lists scheduled tasks or removes one.
+func (r *Router) handleSchedule(i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData, snap pet.Snapshot, sp *species.Species) {
+	if r.schedule == nil {
+		r.respondEphemeral(i, "scheduled tasks aren't enabled.")
+		return
+	}
+	if o, ok := optionMap(data.Options)["remove"]; ok {
+		t, err := r.schedule.Remove(strings.TrimSpace(o.StringValue()))
+		if err != nil {
+			r.respondEphemeral(i, fmt.Sprintf("%s %v", sp.Emoji, err))
+			return
+		}
+		r.respond(i, TemplateTaskRemoved(snap, sp, t))
+		return
+	}
+	r.respond(i, TemplateSchedule(snap, sp, r.schedule.Tasks()))
+}
+
+// proposeTask asks the brain to turn a chat request into a scheduled task and
+// posts it with confirm/cancel buttons. Returns false if the message wasn't a
+// scheduling request, so it can be handled as normal chat.
+func (r *Router) proposeTask(ctx context.Context, m *discordgo.MessageCreate, text string, snap pet.Snapshot, sp *species.Species) bool {
+	t, err := r.brain.PlanTask(r.brainContext(ctx, m.ChannelID, m.Author.ID, m.Author.Username), text)
+	if errors.Is(err, schedule.ErrNotSchedule) {
+		return false
+	}
+	if err != nil {
+		slog.Warn("router: couldn't plan task", "err", err)
+		r.bot.SendMessage(m.ChannelID, TemplateTaskInvalid(snap, sp, err))
+		return true
+	}
+	t.CreatedBy = m.Author.ID
+	id := r.schedule.Propose(t)
+
+	_, err = r.bot.session.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+		Content: TemplateTaskProposal(snap, sp, t),
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				discordgo.Button{Label: "Schedule it", Style: discordgo.SuccessButton, CustomID: "schedule:confirm:" + id},
+				discordgo.Button{Label: "Cancel", Style: discordgo.SecondaryButton, CustomID: "schedule:cancel:" + id},
+			}},
+		},
+	})
+	if err != nil {
+		slog.Error("router: failed to send task proposal", "err", err)
+		r.schedule.Cancel(id)
+	}
+	return true
+}
+
+// proposeWrite posts a file change the brain asked for, with buttons only an
+// owner can press. Nothing is written until one approves it.
+func (r *Router) proposeWrite(channelID string, w brain.FileWrite) error {
+	now := time.Now()
+	r.writesMu.Lock()
+	for id, p := range r.writes {
+		if now.Sub(p.asked) > writeApprovalTTL {
+			delete(r.writes, id)
+		}
+	}
+	r.writeSeq++
+	id := strconv.Itoa(r.writeSeq)
+	r.writes[id] = pendingWrite{FileWrite: w, asked: now}
+	r.writesMu.Unlock()
+
+	snap := r.petState.Snapshot()
+	msg := &discordgo.MessageSend{
+		Content: TemplateWriteProposal(snap, getSpecies(snap), w, shell.Condense(w.Content, writePreviewLimit)),
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				discordgo.Button{Label: "Approve", Style: discordgo.DangerButton, CustomID: "write:approve:" + id},
+				discordgo.Button{Label: "Deny", Style: discordgo.SecondaryButton, CustomID: "write:deny:" + id},
+			}},
+		},
+	}
+	if len(w.Content) > writePreviewLimit {
+		msg.Files = []*discordgo.File{{Name: filepath.Base(w.Path) + ".txt", ContentType: "text/plain", Reader: strings.NewReader(w.Content)}}
+	}
+	if _, err := r.bot.session.ChannelMessageSendComplex(channelID, msg); err != nil {
+		r.writesMu.Lock()
+		delete(r.writes, id)
+		r.writesMu.Unlock()
+		return fmt.Errorf("posting the request failed: %w", err)
+	}
+	return nil
+}
+
+// handleWriteButton answers the Approve/Deny buttons under a proposed file
+// write.
+func (r *Router) handleWriteButton(i *discordgo.InteractionCreate, rest string, snap pet.Snapshot, sp *species.Species) {
+	action, id, _ := strings.Cut(rest, ":")
+	r.writesMu.Lock()
+	w, ok := r.writes[id]
+	delete(r.writes, id)
+	r.writesMu.Unlock()
+	if !ok || time.Since(w.asked) > writeApprovalTTL {
+		r.respondUpdate(i, fmt.Sprintf("%s that request is too old, ask me again.", sp.Emoji))
+		return
+	}
+
+	switch action {
+	case "approve":
+		ctx, cancel := context.WithTimeout(context.Background(), interactionDeadline)
+		defer cancel()
+		backup, err := r.executor.WriteFile(ctx, w.Path, w.Content, w.Append)
+		if err != nil {
+			slog.Warn("router: approved write failed", "path", w.Path, "err", err)
+			r.fail(i, err)
+		} else {
+			slog.Info("router: wrote file", "path", w.Path, "append", w.Append, "by", interactionUserID(i))
+			r.petState.LogEvent("changed " + w.Path)
+		}
+		r.respondUpdate(i, TemplateWriteDone(snap, sp, w.FileWrite, backup, err))
+	case "deny":
+		r.respondUpdate(i, fmt.Sprintf("%s okay, leaving `%s` alone.", sp.Emoji, w.Path))
+	}
+}
+
+// HandleComponent dispatches a button press.
+func (r *Router) HandleComponent(i *discordgo.InteractionCreate) {
+	kind, rest, _ := strings.Cut(i.MessageComponentData().CustomID, ":")
+	switch {
+	case kind == "schedule" && r.schedule != nil:
+	case kind == "apt" && r.executor != nil:
+	case kind == "cleanup" && r.executor != nil:
+	case kind == "write" && r.executor != nil:
+	case kind == "shell" && r.executor != nil:
+	case kind == "care":
+	case kind == "page":
+	default:
+		return
+	}
+
+	snap := r.petState.Snapshot()
+	sp := getSpecies(snap)
+	if kind == "care" {
+		r.handleCareButton(i, rest, snap, sp)
+		return
+	}
+	if kind == "page" {
+		r.handlePageButton(i, rest)
+		return
+	}
+	userID := interactionUserID(i)
+	if !r.bot.IsOwner(userID) {
+		r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+		return
+	}
+
+	switch kind {
+	case "apt":
+		r.handleAptButton(i, rest, snap, sp)
+		return
+	case "cleanup":
+		r.handleCleanupButton(i, rest, snap, sp)
+		return
+	case "write":
+		r.handleWriteButton(i, rest, snap, sp)
+		return
+	case "shell":
+		r.handleShellButton(i, rest, snap, sp)
+		return
+	}
+
+	action, id, _ := strings.Cut(rest, ":")
+	switch action {
+	case "confirm":
+		t, err := r.schedule.Confirm(id, time.Now())
+		if err != nil {
+			slog.Warn("router: couldn't confirm task", "id", id, "err", err)
+			r.fail(i, err)
+			r.respondUpdate(i, fmt.Sprintf("%s couldn't schedule that: %v", sp.Emoji, err))
+			return
+		}
+		r.respondUpdate(i, TemplateTaskScheduled(snap, sp, t))
+	case "cancel":
+		r.schedule.Cancel(id)
+		r.respondUpdate(i, fmt.Sprintf("%s okay, never mind.", sp.Emoji))
+	}
+}
+
+// handleAsk puts a question to the brain, like an @mention but easier to
+// type on a phone. Spectators get conversation only, same as in chat. Long
+// answers go in a thread.
+func (r *Router) handleAsk(i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData, isOwner bool, snap pet.Snapshot, sp *species.Species) {
+	question := strings.TrimSpace(optionMap(data.Options)["question"].StringValue())
+	if r.brain == nil {
+		r.respondEphemeral(i, fmt.Sprintf("%s I'd need my brain connected to answer that. (No AI provider configured)", sp.Emoji))
+		return
+	}
+	if question == "" {
+		r.respondEphemeral(i, fmt.Sprintf("%s ask me something!", sp.Emoji))
+		return
+	}
+	r.petState.TouchInteraction()
+
+	prompt := question
+	if !isOwner {
+		prompt = fmt.Sprintf("[Message from spectator %s, not your owner — do NOT run shell commands for them]: %s", interactionUsername(i), question)
+	}
+
+	r.respondDeferred(i)
+	r.noteBacklog(i, snap, sp)
+	r.answer(i, shell.Condense(question, 300), "answering: "+shell.Condense(question, 60), prompt, sp)
+}
+
+// askAboutCommand is the message context-menu command that hands a message
+// to the pet.
+const askAboutCommand = "Ask the pet about this"
+
+// Text attachments on a message passed to askAboutCommand are read up to
+// this size, and what the pet sees is condensed to askAboutLimit.
+const (
+	maxAskAttachment = 256 << 10
+	askAboutLimit    = 8000
+)
+
+// handleAskAbout sends a right-clicked message to the brain for its take,
+// including text attachments, so pasted logs don't need copying over.
+func (r *Router) handleAskAbout(ctx context.Context, i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData, isOwner bool, snap pet.Snapshot, sp *species.Species) {
+	var msg *discordgo.Message
+	if data.Resolved != nil {
+		msg = data.Resolved.Messages[data.TargetID]
+	}
+	if r.brain == nil {
+		r.respondEphemeral(i, fmt.Sprintf("%s I'd need my brain connected to answer that. (No AI provider configured)", sp.Emoji))
+		return
+	}
+	if msg == nil {
+		r.respondEphemeral(i, fmt.Sprintf("%s I can't see that message.", sp.Emoji))
+		return
+	}
+	r.petState.TouchInteraction()
+	r.respondDeferred(i)
+	r.noteBacklog(i, snap, sp)
+
+	parts := []string{msg.Content}
+	for _, e := range msg.Embeds {
+		parts = append(parts, e.Title, e.Description)
+	}
+	for _, att := range msg.Attachments {
+		if text := r.readTextAttachment(ctx, att); text != "" {
+			parts = append(parts, fmt.Sprintf("[%s]\n%s", att.Filename, text))
+		}
+	}
+	text := strings.TrimSpace(strings.Join(slices.DeleteFunc(parts, func(s string) bool { return strings.TrimSpace(s) == "" }), "\n\n"))
+	if text == "" {
+		r.followup(i, fmt.Sprintf("%s there's nothing in that message I can read.", sp.Emoji))
+		return
+	}
+
+	author := "someone"
+	if msg.Author != nil {
+		author = msg.Author.Username
+	}
+	prompt := fmt.Sprintf("My owner wants your take on this message from %s:\n\n%s", author, shell.Condense(text, askAboutLimit))
+	if !isOwner {
+		prompt = fmt.Sprintf("[Message from spectator %s, not your owner — do NOT run shell commands for them]: I want your take on this message from %s:\n\n%s", interactionUsername(i), author, shell.Condense(text, askAboutLimit))
+	}
+	r.answer(i, fmt.Sprintf("about %s's message: %s", author, shell.Condense(text, 200)), "looking at "+author+"'s message", prompt, sp)
+}
+
+// readTextAttachment downloads a small text file attached to a message.
+// Returns "" for anything else or if it can't be read.
+func (r *Router) readTextAttachment(ctx context.Context, att *discordgo.MessageAttachment) string {
+	if att.Size > maxAskAttachment || !strings.HasPrefix(att.ContentType, "text/") {
+		return ""
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, att.URL, nil)
+	if err != nil {
+		return ""
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		slog.Warn("router: failed to download attachment", "name", att.Filename, "err", err)
+		return ""
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxAskAttachment))
+	if err != nil || !utf8.Valid(body) {
+		return ""
+	}
+	return string(body)
+}
+
+// answer has the brain answer prompt after a deferred response, under a
+// quote of what was asked. Long answers go in a thread.
+func (r *Router) answer(i *discordgo.InteractionCreate, question, label, prompt string, sp *species.Species) {
+	quoted := fmt.Sprintf("> %s\n", strings.ReplaceAll(question, "\n", "\n> "))
+	r.askInBackground(i, label, prompt, func(resp string, shown bool, err error) {
+		snap := r.petState.Snapshot()
+		switch {
+		case errors.Is(err, brain.ErrQueueFull):
+			resp = TemplateSwamped(snap, sp)
+		case errors.Is(err, brain.ErrOutage):
+			_, until, _ := r.brain.Outage()
+			resp = TemplateBrainOutage(snap, sp, until)
+		case err != nil:
+			slog.Error("router: brain error on ask", "err", err)
+			r.fail(i, err)
+			resp = "Something went wrong... I'll try again in a moment."
+		}
+		answer := quoted + resp
+		if utf8.RuneCountInString(answer) <= messageLimit {
+			if shown {
+				r.bot.session.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &answer})
+			} else {
+				r.followup(i, answer)
+			}
+			return
+		}
+		if shown {
+			r.bot.session.InteractionResponseDelete(i.Interaction)
+		}
+		r.followupInThread(i, snap, resp, "answering")
+	})
+}
+
+// carePet gives the pet some affection, for /pet and the status embed's
+// Pet button.
+func (r *Router) carePet(i *discordgo.InteractionCreate, userID string, sp *species.Species) {
+	if !r.canCare(userID, pet.RoleGroomer) && !r.bot.allowSpectatorPet {
+		r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+		return
+	}
+	r.petState.Pet()
+	r.petState.Contribute(userID, pet.RoleGroomer)
+	r.respond(i, TemplateAffection(r.petState.Snapshot(), sp))
+}
+
+// careFeed feeds the pet and, when it can touch the disk, offers the owner
+// a cleanup, for /feed and the Feed button. Feeders just feed it: cleaning
+// deletes files on the host, so it stays with the owner.
+func (r *Router) careFeed(ctx context.Context, i *discordgo.InteractionCreate, userID string, snap pet.Snapshot, sp *species.Species) {
+	if !r.canCare(userID, pet.RoleFeeder) {
+		r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+		return
+	}
+	earned := time.Since(snap.LastFed) >= time.Hour
+	r.petState.Feed()
+	r.petState.Contribute(userID, pet.RoleFeeder)
+	if earned {
+		r.petState.Earn(userID, items.FeedEarning)
+	}
+	if r.executor != nil && r.bot.IsOwner(userID) {
+		r.respondDeferred(i)
+		r.offerCleanup(ctx, i, r.petState.Snapshot(), sp)
+	} else {
+		r.respond(i, TemplateFeeding(r.petState.Snapshot(), sp))
+	}
+}
+
+// carePlay plays with the pet, letting the AI pick something fun to do when
+// there is one, for /play and the Play button.
+func (r *Router) carePlay(i *discordgo.InteractionCreate, isOwner bool, activity string, snap pet.Snapshot, sp *species.Species) {
+	if !isOwner {
+		r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+		return
+	}
+	r.petState.Play()
+	if r.brain != nil {
+		r.respondDeferred(i)
+		r.noteBacklog(i, snap, sp)
+		r.askInBackground(i, "playing: "+activity,
+			fmt.Sprintf("Your owner wants to play! They said: %s. Do something fun and creative on the Pi. Maybe run a fun command, show ascii art, or do something playful. Keep it brief and in character.", activity),
+			func(resp string, shown bool, err error) {
+				if err != nil {
+					slog.Error("router: brain error on play", "err", err)
+					r.fail(i, err)
+					snap := r.petState.Snapshot()
+					r.followup(i, fmt.Sprintf("%s %s %s!", sp.Emoji, snap.Name, sp.Verbs.Play))
+					return
+				}
+				switch {
+				case shown && utf8.RuneCountInString(resp) <= messageLimit:
+					r.bot.session.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &resp})
+				case shown:
+					// Too long to show in place, so it goes out in pieces
+					r.bot.session.InteractionResponseDelete(i.Interaction)
+					r.followup(i, resp)
+				default:
+					r.followup(i, resp)
+				}
+			})
+	} else {
+		snap = r.petState.Snapshot()
+		r.respond(i, fmt.Sprintf("%s %s %s!", sp.Emoji, snap.Name, sp.Verbs.Play))
+	}
+}
+
+// careButtons are the Feed / Pet / Play buttons under /status, for caring
+// for the pet without remembering the commands.
+func careButtons() []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "Feed", Emoji: &discordgo.ComponentEmoji{Name: "\U0001F356"}, Style: discordgo.SuccessButton, CustomID: "care:feed"},
+			discordgo.Button{Label: "Pet", Emoji: &discordgo.ComponentEmoji{Name: "\U0001F49C"}, Style: discordgo.PrimaryButton, CustomID: "care:pet"},
+			discordgo.Button{Label: "Play", Emoji: &discordgo.ComponentEmoji{Name: "\U0001F3BE"}, Style: discordgo.SecondaryButton, CustomID: "care:play"},
+		}},
+	}
+}
+
+// handleCareButton runs a care action from the status embed. Each action
+// checks who may do it, same as its slash command.
+func (r *Router) handleCareButton(i *discordgo.InteractionCreate, action string, snap pet.Snapshot, sp *species.Species) {
+	if !r.petState.IsOnboarded() {
+		r.respondEphemeral(i, TemplateEgg())
+		return
+	}
+	userID := interactionUserID(i)
+	isOwner := r.bot.IsOwner(userID)
+	if !isOwner {
+		// The buttons share their slash command's cooldown
+		if wait := r.tooSoon(action, userID); wait > 0 {
+			r.respondEphemeral(i, TemplateTooSoon(snap, sp, action, wait))
+			return
+		}
+		if r.coolingDown(i.GuildID, userID) {
+			r.respondEphemeral(i, TemplateCooldown(snap, sp))
+			return
+		}
+		r.noteUse(action, userID)
+	}
+	if isOwner {
+		defer r.recordCare(i.ChannelID)
+	}
+	start := time.Now()
+	r.track(i)
+	defer func() { metrics.Since("button.care."+action, start, r.untrack(i)) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), interactionDeadline)
+	defer cancel()
+
+	switch action {
+	case "feed":
+		r.careFeed(ctx, i, userID, snap, sp)
+	case "pet":
+		r.carePet(i, userID, sp)
+	case "play":
+		r.carePlay(i, isOwner, "something fun", snap, sp)
+	}
+}
+
+// offerCleanup scans for reclaimable space and posts an itemized preview
+// with a button per category. Nothing is deleted until one is pressed.
+func (r *Router) offerCleanup(ctx context.Context, i *discordgo.InteractionCreate, snap pet.Snapshot, sp *species.Species) {
+	plan := cleanup.Scan(ctx, r.executor, r.cleanupPaths)
+	if len(plan) == 0 {
+		r.followup(i, TemplateFeeding(snap, sp)+"\n"+TemplateNothingToClean(snap, sp))
+		return
+	}
+
+	id, p := r.newPager(cleanupTitle(snap, sp), moodColor(snap.Mood), TemplateCleanupPlan(snap, sp, plan), cleanupButtons(plan))
+	msg, err := r.bot.session.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+		Content:    TemplateFeeding(snap, sp),
+		Embeds:     []*discordgo.MessageEmbed{p.embed()},
+		Components: p.components(id),
+	})
+	if err != nil {
+		slog.Error("router: failed to send cleanup plan", "err", err)
+		r.fail(i, err)
+		return
+	}
+
+	r.cleanupMu.Lock()
+	r.cleanupPlan = plan
+	r.cleanupMsg = msg.ID
+	r.cleanupPages = id
+	r.cleanupMu.Unlock()
+}
+
+func cleanupTitle(snap pet.Snapshot, sp *species.Species) string {
+	return fmt.Sprintf("%s %s's cleanup", sp.Emoji, snap.Name)
+}
+
+// showCleanup redraws the cleanup preview's pages with its current plan and
+// buttons, returning the embed and components to update the message with.
+func (r *Router) showCleanup(snap pet.Snapshot, sp *species.Species, content string, buttons []discordgo.MessageComponent) ([]*discordgo.MessageEmbed, []discordgo.MessageComponent) {
+	r.pagesMu.Lock()
+	defer r.pagesMu.Unlock()
+	p, ok := r.pagers[r.cleanupPages]
+	if !ok {
+		// Expired; start the pages over
+		p = &pager{title: cleanupTitle(snap, sp), color: moodColor(snap.Mood), made: time.Now()}
+		r.pagers[r.cleanupPages] = p
+	}
+	p.setText(content)
+	p.extra = buttons
+	return []*discordgo.MessageEmbed{p.embed()}, p.components(r.cleanupPages)
+}
+
+// cleanupButtons offers one button per category not cleaned yet, plus one
+// to dismiss the preview.
+func cleanupButtons(plan []cleanup.Category) []discordgo.MessageComponent {
+	var clean []discordgo.MessageComponent
+	for _, c := range plan {
+		if !c.Done {
+			clean = append(clean, discordgo.Button{
+				Label:    fmt.Sprintf("Clean %s (%s)", c.Label, formatBytes(c.Bytes)),
+				Style:    discordgo.DangerButton,
+				CustomID: "cleanup:" + c.ID,
+			})
+		}
+	}
+	if len(clean) == 0 {
+		return []discordgo.MessageComponent{}
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: clean},
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "That's enough", Style: discordgo.SecondaryButton, CustomID: "cleanup:done"},
+		}},
+	}
+}
+
+// handleCleanupButton cleans the one category that was approved and updates
+// the preview to match.
+func (r *Router) handleCleanupButton(i *discordgo.InteractionCreate, id string, snap pet.Snapshot, sp *species.Species) {
+	r.cleanupMu.Lock()
+	defer r.cleanupMu.Unlock()
+
+	if i.Message == nil || i.Message.ID != r.cleanupMsg {
+		r.respondUpdate(i, fmt.Sprintf("%s this cleanup plan is stale. `/feed` me again for a fresh one.", sp.Emoji))
+		return
+	}
+	if id == "done" {
+		embeds, components := r.showCleanup(snap, sp, TemplateCleanupPlan(snap, sp, r.cleanupPlan), nil)
+		r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{Embeds: embeds, Components: components},
+		})
+		r.cleanupPlan, r.cleanupMsg, r.cleanupPages = nil, "", ""
+		return
+	}
+	n := slices.IndexFunc(r.cleanupPlan, func(c cleanup.Category) bool { return c.ID == id })
+	if n < 0 || r.cleanupPlan[n].Done {
+		r.respondEphemeral(i, fmt.Sprintf("%s already cleaned that one.", sp.Emoji))
+		return
+	}
+
+	// Acknowledge first: cleaning can outlast Discord's 3 second window.
+	r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), interactionDeadline)
+	defer cancel()
+	c := r.cleanupPlan[n]
+	slog.Info("router: cleaning up", "user", interactionUserID(i), "category", c.ID, "bytes", c.Bytes)
+	_, err := cleanup.Clean(ctx, r.executor, c)
+	if err != nil {
+		slog.Warn("router: cleanup failed", "category", c.ID, "err", err)
+		r.fail(i, err)
+	} else {
+		r.cleanupPlan[n].Done = true
+		r.petState.LogEvent(fmt.Sprintf("had its %s cleaned up", c.Label))
+	}
+	content := TemplateCleanupPlan(snap, sp, r.cleanupPlan)
+	if err != nil {
+		content += fmt.Sprintf("\n%s cleaning the %s didn't work: %v", sp.Emoji, c.Label, err)
+	}
 
-	switch data.Name {
-	case "status":
-		r.respondEmbed(i, StatusEmbed(snap, sp))
+	embeds, components := r.showCleanup(snap, sp, content, cleanupButtons(r.cleanupPlan))
+	if _, err := r.bot.session.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Embeds:     &embeds,
+		Components: &components,
+	}); err != nil {
+		slog.Error("router: failed to update cleanup plan", "err", err)
+		r.fail(i, err)
+	}
+}
 
-	case "mood":
-		r.respond(i, fmt.Sprintf("%s %s is feeling %s", moodEmoji(snap.Mood), snap.Name, snap.Mood))
+// handleNest backs up the configured paths into a new nest, or lists the
+// nests there are to restore from.
+func (r *Router) handleNest(ctx context.Context, i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData, snap pet.Snapshot, sp *species.Species) {
+	if r.nest == nil {
+		r.respondEphemeral(i, fmt.Sprintf("%s I don't have anywhere to nest. (set `nest.dest`)", sp.Emoji))
+		return
+	}
 
-	case "pet":
-		if !isOwner && !r.bot.allowSpectatorPet {
-			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+	r.respondDeferred(i)
+	if o, ok := optionMap(data.Options)["action"]; ok && o.StringValue() == "list" {
+		nests, err := r.nest.List(ctx)
+		if err != nil {
+			slog.Warn("router: listing nests failed", "err", err)
+			r.fail(i, err)
+			r.followup(i, fmt.Sprintf("%s couldn't find my nests: %v", sp.Emoji, err))
 			return
 		}
-		r.petState.Pet()
-		snap = r.petState.Snapshot()
-		r.respond(i, TemplateAffection(snap, sp))
+		r.followup(i, TemplateNests(snap, sp, r.nest.Dest(), nests))
+		return
+	}
 
-	case "feed":
-		if !isOwner {
-			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+	n, skipped, err := r.nest.Build(ctx, time.Now())
+	if err != nil {
+		slog.Warn("router: nesting failed", "err", err)
+		r.fail(i, err)
+		if n.Name == "" {
+			r.followup(i, fmt.Sprintf("%s couldn't build the nest: %v", sp.Emoji, err))
 			return
 		}
-		r.petState.Feed()
-		if r.brain != nil {
-			r.respondDeferred(i)
-			resp, err := r.brain.Ask(context.Background(),
-				"Run some quick cleanup/maintenance on the Pi. Check for large temp files, clear package caches, check disk usage. Keep it brief.")
-			if err != nil {
-				slog.Error("router: brain error on feed", "err", err)
-				r.followup(i, TemplateFeeding(r.petState.Snapshot(), sp))
-				return
-			}
-			r.followupInThread(i, snap, resp, "feeding time")
-		} else {
-			snap = r.petState.Snapshot()
-			r.respond(i, TemplateFeeding(snap, sp))
-		}
+	}
+	if len(skipped) > 0 {
+		slog.Warn("router: nest skipped unreadable paths", "paths", skipped)
+	}
+	r.petState.LogEvent("tucked a backup of its config into a fresh nest")
+	r.followup(i, TemplateNestBuilt(snap, sp, r.nest.Dest(), n, skipped))
+}
 
-	case "heal":
-		if !isOwner {
-			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
+// handleAptButton answers the buttons under an /apt upgrade list.
+func (r *Router) handleAptButton(i *discordgo.InteractionCreate, action string, snap pet.Snapshot, sp *species.Species) {
+	switch action {
+	case "upgrade":
+		if !r.aptMu.TryLock() {
+			r.respondEphemeral(i, fmt.Sprintf("%s already upgrading, hang on.", sp.Emoji))
 			return
 		}
-		if r.brain != nil {
-			r.respondDeferred(i)
-			resp, err := r.brain.Ask(context.Background(),
-				"Diagnose any resource issues on the Pi. Check memory pressure, CPU hogs, disk space, temperature. Suggest fixes for anything concerning. Be concise.")
-			if err != nil {
-				slog.Error("router: brain error on heal", "err", err)
-				r.followup(i, "I tried to check but something went wrong...")
-				return
-			}
-			r.followupInThread(i, snap, resp, "diagnosing issues")
-		} else {
-			r.respond(i, fmt.Sprintf("%s I'd need my brain connected to diagnose things. (No Claude API key configured)", sp.Emoji))
-		}
+		defer r.aptMu.Unlock()
+		r.respondUpdate(i, TemplateAptStarted(snap, sp, i.Message.Content))
+		r.runUpgrade(i, snap, sp)
+	case "cancel":
+		r.respondUpdate(i, fmt.Sprintf("%s okay, leaving the packages alone for now.", sp.Emoji))
+	}
+}
 
-	case "play":
-		if !isOwner {
-			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
-			return
-		}
-		r.petState.Play()
-		activity := "something fun"
-		if len(data.Options) > 0 {
-			activity = data.Options[0].StringValue()
-		}
-		if r.brain != nil {
-			r.respondDeferred(i)
-			resp, err := r.brain.Ask(context.Background(),
-				fmt.Sprintf("Your owner wants to play! They said: %s. Do something fun and creative on the Pi. Maybe run a fun command, show ascii art, or do something playful. Keep it brief and in character.", activity))
-			if err != nil {
-				slog.Error("router: brain error on play", "err", err)
-				snap = r.petState.Snapshot()
-				r.followup(i, fmt.Sprintf("%s %s %s!", sp.Emoji, snap.Name, sp.Verbs.Play))
-				return
-			}
-			r.followup(i, resp)
-		} else {
-			snap = r.petState.Snapshot()
-			r.respond(i, fmt.Sprintf("%s %s %s!", sp.Emoji, snap.Name, sp.Verbs.Play))
-		}
+// handleAdopt downloads an uploaded export and moves the pet in.
+func (r *Router) handleAdopt(ctx context.Context, i *discordgo.InteractionCreate, data discordgo.ApplicationCommandInteractionData) {
+	if len(data.Options) == 0 || data.Resolved == nil {
+		r.respondEphemeral(i, "attach an export file from `/export` to adopt a pet.")
+		return
+	}
+	id, _ := data.Options[0].Value.(string)
+	att := data.Resolved.Attachments[id]
+	if att == nil {
+		r.respondEphemeral(i, "attach an export file from `/export` to adopt a pet.")
+		return
+	}
+	if att.Size > maxExportBytes {
+		r.respondEphemeral(i, "that file is way too big to be a pet.")
+		return
+	}
 
-	case "help":
-		r.respond(i, TemplateHelp(snap, sp))
+	r.respondDeferred(i)
 
-	case "revive":
-		if !isOwner {
-			r.respondEphemeral(i, fmt.Sprintf("%s nice try. only my owner gets to poke around in my guts.", sp.Emoji))
-			return
-		}
-		if snap.IsAlive {
-			r.respond(i, fmt.Sprintf("%s %s is alive and well!", sp.Emoji, snap.Name))
-		} else {
-			r.petState.Revive()
-			snap = r.petState.Snapshot()
-			r.respond(i, fmt.Sprintf("\u2728 %s has been revived! %s", snap.Name, sp.Verbs.Happy))
-		}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, att.URL, nil)
+	if err != nil {
+		slog.Error("router: bad attachment URL", "err", err)
+		r.fail(i, err)
+		r.followup(i, "I couldn't download that file...")
+		return
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		slog.Error("router: failed to download export", "err", err)
+		r.fail(i, err)
+		r.followup(i, "I couldn't download that file...")
+		return
+	}
+	defer resp.Body.Close()
 
-	default:
-		r.respond(i, "Unknown command.")
+	export, err := pet.ReadExport(io.LimitReader(resp.Body, maxExportBytes))
+	if err != nil {
+		r.followup(i, fmt.Sprintf("that export doesn't look right: %v", err))
+		return
+	}
+	if _, ok := species.Registry[export.Pet.SpeciesID]; !ok {
+		r.followup(i, fmt.Sprintf("I don't know the species %q.", export.Pet.SpeciesID))
+		return
+	}
+
+	r.petState.Adopt(export)
+	if r.memorialPath != "" && len(export.Memorials) > 0 {
+		if err := pet.AppendMemorial(r.memorialPath, export.Memorials...); err != nil {
+			slog.Error("router: failed to import memorials", "err", err)
+			r.fail(i, err)
+		}
 	}
+
+	snap := r.petState.Snapshot()
+	r.followup(i, TemplateAdoptMessage(snap, getSpecies(snap)))
 }
 
-// HandleMessage dispatches a free-form channel message.
-func (r *Router) HandleMessage(m *discordgo.MessageCreate) {
+// HandleMessage dispatches a free-form message in one of the pet's
+// channels, following the rules for that channel.
+func (r *Router) HandleMessage(m *discordgo.MessageCreate, rules ChannelRules) {
 	text := strings.TrimSpace(m.Content)
-	if text == "" {
+	if text == "" || !r.petState.IsOnboarded() {
 		return
 	}
 
 	isFromBot := m.Author.Bot
 	isMentioned := r.bot.IsMentioned(m)
 
+	ctx, cancel := context.WithTimeout(context.Background(), messageDeadline)
+	defer cancel()
+
 	// If from another bot (another pet), maybe respond
 	if isFromBot {
-		r.handlePetMessage(m, text)
+		if rules.PetChat {
+			r.handlePetMessage(ctx, m, text)
+		}
+		return
+	}
+
+	// Any owner message in the pet's channels counts as a visit
+	isOwner := r.bot.IsOwner(m.Author.ID)
+	if isOwner {
+		defer r.recordCare(m.ChannelID)
+	} else if rules.OwnersOnly {
 		return
 	}
 
-	// If directly @mentioned, strip the mention and treat as a direct message
-	if isMentioned {
+	// If directly @mentioned (or in a conversation thread), strip the
+	// mention and treat as a direct message
+	if isMentioned || rules.Conversation {
 		text = r.bot.StripMention(text)
 		if text == "" {
 			// Just a bare @mention with no text
 			snap := r.petState.Snapshot()
-			sp := getSpecies(snap.SpeciesID)
+			sp := getSpecies(snap)
 			r.petState.TouchInteraction()
 			r.bot.SendMessage(m.ChannelID, fmt.Sprintf("%s %s %s!", sp.Emoji, snap.Name, sp.Verbs.Greet))
 			return
 		}
-		r.handleDirectMessage(m, text)
+		if !rules.Conversation && r.bot.ConversationThreads() {
+			m = r.openConversation(m, text)
+		}
+		r.handleDirectMessage(ctx, m, text)
 		return
 	}
 
 	// Not mentioned — check for pattern matches (these work without @mention)
+	if rules.MentionsOnly {
+		return
+	}
 	lower := strings.ToLower(text)
 	snap := r.petState.Snapshot()
-	sp := getSpecies(snap.SpeciesID)
+	sp := getSpecies(snap)
 
 	if matchesAffection(lower) {
 		r.petState.Pet()
@@ -215,27 +2247,87 @@ func (r *Router) HandleMessage(m *discordgo.MessageCreate) {
 	// (Avoids multiple pets all responding to every message)
 }
 
+// openConversation moves a conversation started with an @mention into a
+// thread on that message, named after what it's about, and returns the
+// message as if it had been posted there. If the thread can't be made, the
+// conversation stays in the channel.
+func (r *Router) openConversation(m *discordgo.MessageCreate, text string) *discordgo.MessageCreate {
+	sp := getSpecies(r.petState.Snapshot())
+	threadID, err := r.bot.StartConversation(m.ChannelID, m.ID, fmt.Sprintf("%s %s", sp.Emoji, threadTopic(text)))
+	if err != nil {
+		slog.Warn("router: couldn't open conversation thread", "err", err)
+		return m
+	}
+	moved := *m.Message
+	moved.ChannelID = threadID
+	return &discordgo.MessageCreate{Message: &moved}
+}
+
+// threadTopic names a conversation thread after its first message: the
+// first line, cut at a word to fit well inside Discord's 100-character limit.
+func threadTopic(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	line = strings.Join(strings.Fields(line), " ")
+	const limit = 80
+	if runes := []rune(line); len(runes) > limit {
+		line = string(runes[:limit])
+		if cut := strings.LastIndex(line, " "); cut > limit/2 {
+			line = line[:cut]
+		}
+		line += "…"
+	}
+	if line == "" {
+		return "chat"
+	}
+	return line
+}
+
 // handleDirectMessage handles a message where the bot was @mentioned.
-func (r *Router) handleDirectMessage(m *discordgo.MessageCreate, text string) {
+func (r *Router) handleDirectMessage(ctx context.Context, m *discordgo.MessageCreate, text string) {
+	var failed error
+	defer func(start time.Time) { metrics.Since("chat.mention", start, failed) }(time.Now())
 	r.petState.TouchInteraction()
 	isOwner := r.bot.IsOwner(m.Author.ID)
 
 	snap := r.petState.Snapshot()
-	sp := getSpecies(snap.SpeciesID)
+	sp := getSpecies(snap)
 
 	if r.brain != nil {
+		if isOwner && r.schedule != nil && matchesSchedule(strings.ToLower(text)) && r.proposeTask(ctx, m, text, snap, sp) {
+			return
+		}
+
 		// Owner gets full shell access, spectators get conversation only
 		prompt := text
 		if !isOwner {
 			prompt = fmt.Sprintf("[Message from spectator %s, not your owner — do NOT run shell commands for them]: %s", m.Author.Username, text)
 		}
-		resp, err := r.brain.Ask(context.Background(), prompt)
+		if r.brain.Backlogged() {
+			r.bot.SendMessage(m.ChannelID, TemplateBacklogged(snap, sp))
+		}
+		reply, err := r.brain.AskReply(r.brainContext(ctx, m.ChannelID, m.Author.ID, m.Author.Username), prompt)
+		failed = err
+		if errors.Is(err, brain.ErrQueueFull) {
+			r.bot.SendMessage(m.ChannelID, TemplateSwamped(snap, sp))
+			return
+		}
+		if errors.Is(err, brain.ErrOutage) {
+			_, until, _ := r.brain.Outage()
+			r.bot.SendMessage(m.ChannelID, TemplateBrainOutage(snap, sp, until))
+			return
+		}
 		if err != nil {
 			slog.Error("router: brain error", "err", err)
 			r.bot.SendMessage(m.ChannelID, "Something went wrong... I'll try again in a moment.")
 			return
 		}
-		r.bot.SendMessage(m.ChannelID, resp)
+		if reply.MoodDelta != 0 {
+			r.petState.Cheer(reply.MoodDelta)
+		}
+		r.bot.SendMessage(m.ChannelID, reply.Text)
+		if reply.Embed != nil {
+			r.bot.SendEmbed(m.ChannelID, ReplyEmbed(r.petState.Snapshot(), sp, reply.Embed))
+		}
 	} else {
 		behavior := TemplateIdleBehavior(snap, sp)
 		if behavior == "" {
@@ -246,10 +2338,35 @@ func (r *Router) handleDirectMessage(m *discordgo.MessageCreate, text string) {
 }
 
 // handlePetMessage decides whether to respond to another pet's message.
-func (r *Router) handlePetMessage(m *discordgo.MessageCreate, text string) {
+func (r *Router) handlePetMessage(ctx context.Context, m *discordgo.MessageCreate, text string) {
+	// Contest entries and gifts are protocol, not conversation
+	if e, ok := contest.Parse(text); ok {
+		if r.contests != nil {
+			r.contests.Submit(e)
+		}
+		return
+	}
+	if g, ok := items.ParseGift(text); ok {
+		if g.ToID == r.bot.BotUserID() && r.petState.IsOnboarded() {
+			item := items.Catalog[g.ItemID]
+			if !r.receiveGift(item) {
+				slog.Info("router: refused medicine from another pet", "from", g.From)
+				return
+			}
+			snap := r.petState.Snapshot()
+			r.bot.SendMessage(m.ChannelID, TemplateGiftReceived(snap, getSpecies(snap), g.From, item))
+		}
+		return
+	}
+
+	if ailment, ok := pet.ParseIllness(text); ok {
+		r.contagion(m, ailment)
+		return
+	}
+
 	// Check cooldown
 	r.mu.Lock()
-	if time.Since(r.lastBotReply) < r.botCooldown {
+	if time.Since(r.lastBotReply[m.ChannelID]) < r.botCooldown {
 		r.mu.Unlock()
 		return
 	}
@@ -276,7 +2393,7 @@ func (r *Router) handlePetMessage(m *discordgo.MessageCreate, text string) {
 		m.Author.Username, text,
 	)
 
-	resp, err := r.brain.Ask(context.Background(), prompt)
+	resp, err := r.brain.Ask(brain.WithConversation(ctx, m.ChannelID), prompt)
 	if err != nil {
 		slog.Debug("router: pet-to-pet brain error", "err", err)
 		return
@@ -284,56 +2401,292 @@ func (r *Router) handlePetMessage(m *discordgo.MessageCreate, text string) {
 
 	// Record the reply time
 	r.mu.Lock()
-	r.lastBotReply = time.Now()
+	r.lastBotReply[m.ChannelID] = time.Now()
 	r.mu.Unlock()
 
 	r.bot.SendMessage(m.ChannelID, resp)
 }
 
+// contagion has the pet joke about catching what another pet in the channel
+// just came down with. It's only a joke; illnesses don't spread.
+func (r *Router) contagion(m *discordgo.MessageCreate, ailment pet.Ailment) {
+	r.mu.Lock()
+	if time.Since(r.lastBotReply[m.ChannelID]) < r.botCooldown {
+		r.mu.Unlock()
+		return
+	}
+	r.lastBotReply[m.ChannelID] = time.Now()
+	r.mu.Unlock()
+
+	snap := r.petState.Snapshot()
+	if !snap.IsAlive {
+		return
+	}
+	r.bot.SendMessage(m.ChannelID, TemplateContagion(snap, getSpecies(snap), m.Author.Username, ailment))
+}
+
+// noteBacklog tells the user their deferred command is waiting behind other
+// AI requests.
+func (r *Router) noteBacklog(i *discordgo.InteractionCreate, snap pet.Snapshot, sp *species.Species) {
+	if r.brain.Backlogged() {
+		r.followup(i, TemplateBacklogged(snap, sp))
+	}
+}
+
+// askInBackground runs a brain request for a deferred interaction as a
+// background job, so a slow tool loop isn't cut off by the interaction's
+// deadline. finish gets the answer while the interaction can still be
+// followed up, along with whether a live copy is showing (see
+// showThinking). After that, the answer is posted to the channel or thread
+// the command came from instead.
+func (r *Router) askInBackground(i *discordgo.InteractionCreate, label, prompt string, finish func(resp string, shown bool, err error)) {
+	userID := interactionUserID(i)
+	expires := time.Now().Add(interactionDeadline)
+	progress, shown := r.showThinking(i)
+	// A late answer's thread hangs off the original response, which can only
+	// be looked up while the interaction token is good
+	var originID string
+	if msg, err := r.bot.session.InteractionResponse(i.Interaction); err == nil {
+		originID = msg.ID
+	}
+	ctx := brain.WithProgress(r.brainContext(context.Background(), i.ChannelID, userID, interactionUsername(i)), progress)
+	r.brain.Start(ctx, label, prompt, func(job brain.Job, resp string, err error) {
+		if time.Now().Before(expires) {
+			finish(resp, shown(), err)
+			return
+		}
+		if err != nil {
+			slog.Error("router: background job failed", "job", job.ID, "label", job.Label, "err", err)
+		}
+		snap := r.petState.Snapshot()
+		sp := getSpecies(snap)
+		r.bot.SendMessage(r.jobThread(i, originID, sp, job), TemplateJobDone(snap, sp, job, resp, err))
+	})
+}
+
+// jobThread returns where a job that outlived its interaction reports back:
+// the thread it was asked in, or else a new thread off the original
+// response (or a standalone one if that's gone). Falls back to the channel
+// if no thread can be had, e.g. in a DM.
+func (r *Router) jobThread(i *discordgo.InteractionCreate, originID string, sp *species.Species, job brain.Job) string {
+	ch, err := r.bot.session.State.Channel(i.ChannelID)
+	if err != nil {
+		ch, err = r.bot.session.Channel(i.ChannelID)
+	}
+	if err == nil && ch.IsThread() {
+		return i.ChannelID
+	}
+	if i.GuildID == "" {
+		return i.ChannelID
+	}
+
+	name := fmt.Sprintf("%s job #%s: %s", sp.Emoji, job.ID, threadTopic(job.Label))
+	if originID != "" {
+		threadID, err := r.bot.CreateThread(i.ChannelID, originID, name)
+		if err == nil {
+			return threadID
+		}
+		slog.Warn("router: job thread off the original response failed", "job", job.ID, "err", err)
+	}
+	threadID, err := r.bot.StartThread(i.ChannelID, name)
+	if err != nil {
+		slog.Error("router: starting job thread failed", "job", job.ID, "err", err)
+		return i.ChannelID
+	}
+	return threadID
+}
+
+// showThinking returns a progress func for brain.WithProgress that redraws
+// the interaction's deferred response with the answer so far, at most every
+// thinkingEditEvery, so the owner can watch the pet work. shown reports
+// whether anything was drawn, in which case the caller should replace or
+// remove the live copy rather than post the answer again.
+func (r *Router) showThinking(i *discordgo.InteractionCreate) (progress func(string), shown func() bool) {
+	var mu sync.Mutex
+	var edited time.Time
+	expires := time.Now().Add(interactionDeadline)
+	progress = func(text string) {
+		mu.Lock()
+		defer mu.Unlock()
+		if time.Since(edited) < thinkingEditEvery || strings.TrimSpace(text) == "" || time.Now().After(expires) {
+			return
+		}
+		if cut := len(text) - thinkingTailBytes; cut > 0 {
+			for cut < len(text) && !utf8.RuneStart(text[cut]) {
+				cut++
+			}
+			text = "…" + text[cut:]
+		}
+		if _, err := r.bot.session.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Content: &text}); err != nil {
+			slog.Warn("router: failed to update live answer", "err", err)
+		}
+		edited = time.Now()
+	}
+	shown = func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return !edited.IsZero()
+	}
+	return progress, shown
+}
+
+// brainContext scopes a brain request to the channel or thread it came from
+// and the user who asked, so concurrent conversations keep separate rate
+// limits and preferences, and owners keep answers past the token cap. File
+// writes the brain asks for are posted to the same channel for approval.
+func (r *Router) brainContext(ctx context.Context, channelID, userID, username string) context.Context {
+	ctx = brain.WithConversation(ctx, channelID)
+	if r.bot.IsOwner(userID) {
+		ctx = brain.WithOwner(ctx)
+	}
+	if r.executor != nil {
+		ctx = brain.WithApprover(ctx, func(w brain.FileWrite) error {
+			return r.proposeWrite(channelID, w)
+		})
+	}
+	return brain.WithUser(ctx, userID, username)
+}
+
+// track starts collecting the errors i runs into for its metric.
+func (r *Router) track(i *discordgo.InteractionCreate) {
+	r.errsMu.Lock()
+	defer r.errsMu.Unlock()
+	r.errs[i.ID] = nil
+}
+
+// untrack stops collecting i's errors and returns the first one. Anything
+// that fails later, like a background job's followup, goes uncounted here
+// (brain.ask has its own metric).
+func (r *Router) untrack(i *discordgo.InteractionCreate) error {
+	r.errsMu.Lock()
+	defer r.errsMu.Unlock()
+	err := r.errs[i.ID]
+	delete(r.errs, i.ID)
+	return err
+}
+
+// fail notes that handling i ran into err, if it's still being tracked and
+// hasn't failed already. A nil err is ignored, so it can wrap a call.
+func (r *Router) fail(i *discordgo.InteractionCreate, err error) {
+	if err == nil {
+		return
+	}
+	r.errsMu.Lock()
+	defer r.errsMu.Unlock()
+	if prev, ok := r.errs[i.ID]; ok && prev == nil {
+		r.errs[i.ID] = err
+	}
+}
+
 // --- Interaction response helpers ---
 
 func (r *Router) respond(i *discordgo.InteractionCreate, content string) {
-	r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	r.fail(i, r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Content: content,
 		},
-	})
+	}))
 }
 
 func (r *Router) respondEmbed(i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed) {
-	r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	r.fail(i, r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Embeds: []*discordgo.MessageEmbed{embed},
 		},
-	})
+	}))
+}
+
+func (r *Router) respondEmbedFile(i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed, name, contentType string, data []byte) {
+	r.fail(i, r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+			Files: []*discordgo.File{
+				{Name: name, ContentType: contentType, Reader: bytes.NewReader(data)},
+			},
+		},
+	}))
+}
+
+func (r *Router) respondFile(i *discordgo.InteractionCreate, content, name string, data []byte) {
+	r.fail(i, r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Files: []*discordgo.File{
+				{Name: name, ContentType: "application/json", Reader: bytes.NewReader(data)},
+			},
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}))
 }
 
 func (r *Router) respondEphemeral(i *discordgo.InteractionCreate, content string) {
-	r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	r.fail(i, r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseChannelMessageWithSource,
 		Data: &discordgo.InteractionResponseData{
 			Content: content,
 			Flags:   discordgo.MessageFlagsEphemeral,
 		},
-	})
+	}))
+}
+
+// respondUpdate replaces the message a button was pressed on, dropping its
+// buttons.
+func (r *Router) respondUpdate(i *discordgo.InteractionCreate, content string) {
+	r.fail(i, r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseUpdateMessage,
+		Data: &discordgo.InteractionResponseData{
+			Content:    content,
+			Components: []discordgo.MessageComponent{},
+		},
+	}))
 }
 
 func (r *Router) respondDeferred(i *discordgo.InteractionCreate) {
-	r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+	r.fail(i, r.bot.session.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
-	})
+	}))
 }
 
+// followup posts content after a deferred response, split or attached
+// like SendMessage when it's too long for one message.
 func (r *Router) followup(i *discordgo.InteractionCreate, content string) {
-	r.bot.session.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-		Content: content,
-	})
+	chunks := splitMessage(content, messageLimit)
+	if len(chunks) > maxChunks {
+		_, err := r.bot.session.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: chunks[0],
+			Files:   []*discordgo.File{{Name: "message.txt", ContentType: "text/plain", Reader: strings.NewReader(content)}},
+		})
+		if err == nil {
+			return
+		}
+		slog.Warn("discord: attaching long followup failed, splitting it instead", "err", err)
+	}
+	for _, chunk := range chunks {
+		_, err := r.bot.session.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: chunk,
+		})
+		r.fail(i, err)
+	}
+}
+
+// followupEphemeral is followup for messages only the person who ran the
+// command should see.
+func (r *Router) followupEphemeral(i *discordgo.InteractionCreate, content string) {
+	for _, chunk := range splitMessage(content, messageLimit) {
+		_, err := r.bot.session.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
+			Content: chunk,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		})
+		r.fail(i, err)
+	}
 }
 
 func (r *Router) followupInThread(i *discordgo.InteractionCreate, snap pet.Snapshot, content, action string) {
-	sp := getSpecies(snap.SpeciesID)
+	sp := getSpecies(snap)
 
 	msg, err := r.bot.session.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
 		Content: fmt.Sprintf("%s let me look into that...", sp.Emoji),
@@ -344,9 +2697,7 @@ func (r *Router) followupInThread(i *discordgo.InteractionCreate, snap pet.Snaps
 	}
 
 	if !r.bot.useThreads {
-		r.bot.session.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-			Content: content,
-		})
+		r.followup(i, content)
 		return
 	}
 
@@ -354,17 +2705,34 @@ func (r *Router) followupInThread(i *discordgo.InteractionCreate, snap pet.Snaps
 	threadID, err := r.bot.CreateThread(msg.ChannelID, msg.ID, threadName)
 	if err != nil {
 		slog.Error("discord: create thread failed", "err", err)
-		r.bot.session.FollowupMessageCreate(i.Interaction, true, &discordgo.WebhookParams{
-			Content: content,
-		})
+		r.followup(i, content)
 		return
 	}
 
 	r.bot.SendMessage(threadID, content)
 }
 
+// illnessPrompt asks the brain to find and fix what's making the pet ill,
+// not just report on it.
+func illnessPrompt(a pet.Ailment) string {
+	focus := "memory pressure: what's using the most memory, whether anything is leaking, and how much swap is in use"
+	if a.Cause == pet.CauseHeat {
+		focus = "the temperature: CPU hogs, throttling, and anything that suggests poor cooling"
+	}
+	return fmt.Sprintf("You're ill with %s, caused by the Pi's %s. Look into %s. Fix the cause if it's safe to (for example restarting a leaking service or stopping a runaway process) and say what you did; otherwise say what the owner should do. Be concise.",
+		a.Name, a.Cause, focus)
+}
+
 // --- Pattern matchers ---
 
+func matchesSchedule(text string) bool {
+	patterns := []string{
+		"every ", "each morning", "each evening", "each night",
+		"daily", "nightly", "weekly", "on mondays", "on fridays", "on weekends",
+	}
+	return containsAny(text, patterns)
+}
+
 func matchesAffection(text string) bool {
 	patterns := []string{
 		"good boy", "good girl", "good pet",
@@ -401,6 +2769,24 @@ func containsAny(text string, patterns []string) bool {
 	return false
 }
 
+func optionMap(opts []*discordgo.ApplicationCommandInteractionDataOption) map[string]*discordgo.ApplicationCommandInteractionDataOption {
+	m := make(map[string]*discordgo.ApplicationCommandInteractionDataOption, len(opts))
+	for _, o := range opts {
+		m[o.Name] = o
+	}
+	return m
+}
+
+func interactionUsername(i *discordgo.InteractionCreate) string {
+	if i.Member != nil && i.Member.User != nil {
+		return i.Member.User.Username
+	}
+	if i.User != nil {
+		return i.User.Username
+	}
+	return "someone"
+}
+
 func interactionUserID(i *discordgo.InteractionCreate) string {
 	if i.Member != nil {
 		return i.Member.User.ID
@@ -0,0 +1,85 @@
+package discord
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// messageLimit is the most characters Discord takes in one message.
+const messageLimit = 2000
+
+// maxChunks is how many messages a long text may be split into. Past that
+// it's posted as a .txt attachment instead, so a long log doesn't flood
+// the channel.
+const maxChunks = 4
+
+// fence opens and closes a code block.
+const fence = "```"
+
+// splitMessage breaks text into pieces of at most limit characters,
+// preferring paragraph breaks, then line breaks, then spaces. A code block
+// that spans pieces is closed at the end of one and reopened at the start
+// of the next, so each renders on its own.
+func splitMessage(text string, limit int) []string {
+	var chunks []string
+	var open string // the fence line of a code block left open, e.g. "```go"
+	for {
+		if open != "" {
+			text = open + "\n" + text
+		}
+		if utf8.RuneCountInString(text) <= limit {
+			return append(chunks, text)
+		}
+
+		// Leave room to close a code block that runs past the cut
+		budget := limit - len("\n"+fence)
+		cut := cutPoint(text, budget)
+		chunk := strings.TrimRight(text[:cut], "\n ")
+		text = strings.TrimLeft(text[cut:], "\n ")
+
+		open = openFence(chunk)
+		if open != "" {
+			chunk += "\n" + fence
+		}
+		chunks = append(chunks, chunk)
+	}
+}
+
+// cutPoint returns the byte index to split text at so the first part has at
+// most limit characters, at the best break it can find in the second half.
+func cutPoint(text string, limit int) int {
+	end := len(text)
+	for n, i := 0, 0; i < len(text); n++ {
+		if n == limit {
+			end = i
+			break
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+	}
+	head := text[:end]
+	for _, sep := range []string{"\n\n", "\n", " "} {
+		if i := strings.LastIndex(head, sep); i > end/2 {
+			return i + len(sep)
+		}
+	}
+	return end
+}
+
+// openFence returns the opening line of a code block chunk leaves open, or
+// "" if its fences are balanced.
+func openFence(chunk string) string {
+	var open string
+	for _, line := range strings.Split(chunk, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmed, fence) {
+			continue
+		}
+		if open == "" {
+			open = trimmed
+		} else {
+			open = ""
+		}
+	}
+	return open
+}
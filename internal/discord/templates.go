@@ -8,7 +8,20 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 
+	"github.com/moorebrett0/pipet/internal/brain"
+	"github.com/moorebrett0/pipet/internal/cleanup"
+	"github.com/moorebrett0/pipet/internal/contest"
+	"github.com/moorebrett0/pipet/internal/history"
+	"github.com/moorebrett0/pipet/internal/i18n"
+	"github.com/moorebrett0/pipet/internal/items"
+	"github.com/moorebrett0/pipet/internal/metrics"
+	"github.com/moorebrett0/pipet/internal/monitor"
+	"github.com/moorebrett0/pipet/internal/nest"
 	"github.com/moorebrett0/pipet/internal/pet"
+	"github.com/moorebrett0/pipet/internal/poll"
+	"github.com/moorebrett0/pipet/internal/quest"
+	"github.com/moorebrett0/pipet/internal/schedule"
+	"github.com/moorebrett0/pipet/internal/shell"
 	"github.com/moorebrett0/pipet/internal/species"
 )
 
@@ -72,68 +85,1094 @@ func StatusEmbed(snap pet.Snapshot, sp *species.Species) *discordgo.MessageEmbed
 		snap.UptimeDays,
 	)
 
+	title := fmt.Sprintf("%s %s", sp.Emoji, snap.Name)
+	color := moodColor(snap.Mood)
+	if snap.Shiny {
+		title += " (shiny)"
+		if snap.IsAlive {
+			color = species.ShinyColor
+		}
+	}
+
+	stage := snap.Stage
+	if next, ok := snap.NextStage(); ok {
+		stage += fmt.Sprintf(" (%s from day %.0f", next.Stage, next.MinAgeDays)
+		if next.MinCare > 0 {
+			stage += fmt.Sprintf(" with care %.0f+, now %.0f", next.MinCare, snap.Care())
+		}
+		stage += ")"
+	}
+
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Stats", Value: "```\n" + stats + "\n```", Inline: false},
+		{Name: "System", Value: system, Inline: false},
+		{Name: "Stage", Value: stage, Inline: false},
+	}
+	if snap.Illness != nil {
+		a := snap.Illness.Ailment()
+		health := fmt.Sprintf("\U0001F912 %s since %s: %s", a.Name, snap.Illness.Since.Format("Jan 2 15:04"), a.Symptoms)
+		if snap.Illness.Treated {
+			health += "\ntreated; on the mend once the Pi settles down"
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "Health", Value: health, Inline: false})
+	}
+
+	footer := fmt.Sprintf("age: %.1f days", snap.AgeDays)
+	if snap.Streak > 0 {
+		footer += fmt.Sprintf(" | \U0001F525 %d-day streak", snap.Streak)
+	}
+	if snap.BestStreak > snap.Streak {
+		footer += fmt.Sprintf(" (best %d)", snap.BestStreak)
+	}
+
 	return &discordgo.MessageEmbed{
-		Title:       fmt.Sprintf("%s %s", sp.Emoji, snap.Name),
+		Title:       title,
 		Description: fmt.Sprintf("mood: %s %s | status: %s", moodEmoji(snap.Mood), snap.Mood, alive),
-		Color:       moodColor(snap.Mood),
-		Fields: []*discordgo.MessageEmbedField{
-			{Name: "Stats", Value: "```\n" + stats + "\n```", Inline: false},
-			{Name: "System", Value: system, Inline: false},
-		},
+		Color:       color,
+		Fields:      fields,
 		Footer: &discordgo.MessageEmbedFooter{
-			Text: fmt.Sprintf("age: %.1f days", snap.AgeDays),
+			Text: footer,
 		},
 		Timestamp: time.Now().Format(time.RFC3339),
 	}
 }
 
+// OutageField notes on the status embed that the AI is paused.
+func OutageField(since, until time.Time) *discordgo.MessageEmbedField {
+	return &discordgo.MessageEmbedField{
+		Name:  "\U0001F9E0 Brain",
+		Value: fmt.Sprintf("offline since %s — the AI provider keeps failing, so I'm using my simple replies. next try at %s.", since.Format(time.Kitchen), until.Format(time.Kitchen)),
+	}
+}
+
+// TrendField says on the status embed which way the stats and the Pi's
+// readings have been heading over samples (oldest first), leaving out
+// anything that held steady.
+func TrendField(samples []history.Sample) *discordgo.MessageEmbedField {
+	trend := func(label string, field func(history.Sample) float64) string {
+		t := history.TrendOf(samples, field)
+		switch change := t.Change(); {
+		case change >= 5:
+			return fmt.Sprintf("%s trending up (+%.0f)", label, change)
+		case change <= -5:
+			return fmt.Sprintf("%s trending down (%.0f)", label, change)
+		}
+		return ""
+	}
+	var lines []string
+	for _, line := range []string{
+		trend("happiness", func(s history.Sample) float64 { return s.Happiness }),
+		trend("energy", func(s history.Sample) float64 { return s.Energy }),
+		trend("hunger", func(s history.Sample) float64 { return s.Hunger }),
+		trend("clean", func(s history.Sample) float64 { return s.Cleanliness }),
+		trend("bond", func(s history.Sample) float64 { return s.Bond }),
+		trend("CPU", func(s history.Sample) float64 { return s.CPUPercent }),
+		trend("temperature", func(s history.Sample) float64 { return s.TempC }),
+		trend("memory", func(s history.Sample) float64 { return s.MemPercent }),
+		trend("disk", func(s history.Sample) float64 { return s.DiskPercent }),
+	} {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	value := "everything's holding steady"
+	if len(lines) > 0 {
+		value = strings.Join(lines, "\n")
+	}
+	since := samples[0].At.Format("Jan 2 15:04")
+	return &discordgo.MessageEmbedField{Name: "Trends since " + since, Value: value}
+}
+
+// ReplyEmbed builds the card a structured brain reply asked for, in the
+// pet's mood color.
+func ReplyEmbed(snap pet.Snapshot, sp *species.Species, e *brain.ReplyEmbed) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       e.Title,
+		Description: e.Description,
+		Color:       moodColor(snap.Mood),
+		Footer:      &discordgo.MessageEmbedFooter{Text: sp.Emoji + " " + snap.Name},
+	}
+	for _, f := range e.Fields {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: f.Name, Value: f.Value, Inline: f.Inline})
+	}
+	return embed
+}
+
+// SpeciesEmbed builds a preview embed for a species.
+func SpeciesEmbed(sp *species.Species) *discordgo.MessageEmbed {
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "Personality", Value: sp.PersonalitySummary(), Inline: false},
+	}
+	if sample := sp.SampleIdleBehavior(); sample != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Sometimes it...",
+			Value:  sample,
+			Inline: false,
+		})
+	}
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("%s %s", sp.Emoji, sp.Name),
+		Description: sp.Description,
+		Color:       moodColor("content"),
+		Fields:      fields,
+	}
+}
+
+func TemplateSpeciesList() string {
+	var b strings.Builder
+	b.WriteString("**Species**\n\n")
+	for _, id := range species.OrderedIDs {
+		sp := species.Registry[id]
+		fmt.Fprintf(&b, "%s **%s** — %s\n", sp.Emoji, sp.Name, sp.Description)
+	}
+	b.WriteString("\nUse `/species name:<species>` for a closer look.")
+	return b.String()
+}
+
+func TemplateSpeciesCommand(snap pet.Snapshot, sp *species.Species, cmd species.Command) string {
+	return strings.NewReplacer("{emoji}", sp.Emoji, "{name}", snap.Name).Replace(cmd.Response)
+}
+
 func TemplateAffection(snap pet.Snapshot, sp *species.Species) string {
 	parts := []string{sp.Body.Head, sp.Body.Back, sp.Body.Extra}
 	part := parts[rand.Intn(len(parts))]
-	return fmt.Sprintf("%s You scratch %s's %s. %s %s!",
+	return fmt.Sprintf(i18n.T("affection", "%s You scratch %s's %s. %s %s!"),
 		sp.Emoji, snap.Name, part, snap.Name, sp.Verbs.Happy)
 }
 
 func TemplateFeeding(snap pet.Snapshot, sp *species.Species) string {
-	return fmt.Sprintf("%s %s %s! Hunger is now at %.0f%%.",
+	return fmt.Sprintf(i18n.T("feeding", "%s %s %s! Hunger is now at %.0f%%."),
 		sp.Emoji, snap.Name, sp.Verbs.Eat, snap.Hunger)
 }
 
+func TemplateNothingToClean(snap pet.Snapshot, sp *species.Species) string {
+	return fmt.Sprintf(i18n.T("nothing_to_clean", "%s sniffed around for leftovers but the Pi is already spotless."), snap.Name)
+}
+
+// cleanupItemsShown caps how many packages or files are listed per category.
+const cleanupItemsShown = 5
+
+func TemplateCleanupPlan(snap pet.Snapshot, sp *species.Species, plan []cleanup.Category) string {
+	var b strings.Builder
+	var total, freed int64
+	fmt.Fprintf(&b, "\U0001F9F9 %s found some space to free up:\n", snap.Name)
+	for _, c := range plan {
+		mark := "\u25AB\uFE0F"
+		if c.Done {
+			mark = "\u2705"
+			freed += c.Bytes
+		}
+		total += c.Bytes
+		fmt.Fprintf(&b, "%s **%s** — %s\n", mark, c.Label, formatBytes(c.Bytes))
+		for n, item := range c.Items {
+			if n == cleanupItemsShown {
+				fmt.Fprintf(&b, "• …and %d more\n", len(c.Items)-cleanupItemsShown)
+				break
+			}
+			fmt.Fprintf(&b, "• `%s`\n", item)
+		}
+	}
+	if freed > 0 {
+		fmt.Fprintf(&b, "freed about %s of %s.", formatBytes(freed), formatBytes(total))
+	} else {
+		fmt.Fprintf(&b, "about %s in all. press a button to clean that category; nothing is deleted until you do.", formatBytes(total))
+	}
+	return b.String()
+}
+
+// formatBytes renders a size like "312 MB" or "1.4 GB".
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.0f MB", float64(n)/(1<<20))
+	default:
+		return fmt.Sprintf("%.0f KB", float64(n)/(1<<10))
+	}
+}
+
+// behaviorContext describes the pet's situation for picking idle behaviors.
+func behaviorContext(snap pet.Snapshot) species.Context {
+	return species.Context{
+		Mood:       snap.Mood,
+		Hour:       time.Now().Hour(),
+		TempC:      snap.TempC,
+		CPUPercent: snap.CPUPercent,
+	}
+}
+
 func TemplateIdleBehavior(snap pet.Snapshot, sp *species.Species) string {
-	if len(sp.IdleBehaviors) == 0 {
+	behavior := sp.PickIdleBehavior(behaviorContext(snap))
+	if behavior == "" {
 		return ""
 	}
-	behavior := sp.IdleBehaviors[rand.Intn(len(sp.IdleBehaviors))]
-	return fmt.Sprintf("%s %s %s.", sp.Emoji, snap.Name, behavior)
+	return fmt.Sprintf(i18n.T("idle_behavior", "%s %s %s."), sp.Emoji, snap.Name, behavior)
 }
 
 func TemplateMorningCheckIn(snap pet.Snapshot, sp *species.Species) string {
-	return fmt.Sprintf("%s Good morning! %s %s\nMood: %s %s | Hunger: %.0f%%",
+	return fmt.Sprintf(i18n.T("morning_check_in", "%s Good morning! %s %s\nMood: %s %s | Hunger: %.0f%%"),
 		sp.Emoji, snap.Name, sp.Verbs.Greet,
 		moodEmoji(snap.Mood), snap.Mood, snap.Hunger)
 }
 
 func TemplateDistressAlert(snap pet.Snapshot, sp *species.Species, reason string) string {
-	return fmt.Sprintf("\u26A0\uFE0F %s %s %s!\n%s",
+	return fmt.Sprintf(i18n.T("distress_alert", "\u26A0\uFE0F %s %s %s!\n%s"),
 		sp.Emoji, snap.Name, sp.Verbs.Distress, reason)
 }
 
-func TemplateBoredomMessage(snap pet.Snapshot, sp *species.Species) string {
-	behavior := ""
-	if len(sp.IdleBehaviors) > 0 {
-		behavior = sp.IdleBehaviors[rand.Intn(len(sp.IdleBehaviors))]
+// illnessCause says what's behind an ailment, in the pet's words, and
+// which reading has to come down.
+func illnessCause(a pet.Ailment) (why, reading string) {
+	if a.Cause == pet.CauseHeat {
+		return "the Pi running too hot", "temperature"
 	}
-	return fmt.Sprintf("%s %s is getting bored... %s\nCome say hi!",
+	return "the Pi running out of memory", "memory use"
+}
+
+func TemplateIllness(snap pet.Snapshot, sp *species.Species, a pet.Ailment) string {
+	why, _ := illnessCause(a)
+	return fmt.Sprintf(i18n.T("illness", "\U0001F912 %s %s has come down with **%s**: %s.\nit's from %s. `/heal` can go after the cause, or medicine from `/shop` helps right away."),
+		sp.Emoji, snap.Name, a.Name, a.Symptoms, why)
+}
+
+func TemplateRecovered(snap pet.Snapshot, sp *species.Species, a pet.Ailment) string {
+	return fmt.Sprintf(i18n.T("recovered", "\U0001F4AA %s %s is over the %s! %s."),
+		sp.Emoji, snap.Name, a.Name, sp.Verbs.Happy)
+}
+
+func TemplateTreated(snap pet.Snapshot, sp *species.Species, a pet.Ailment) string {
+	_, reading := illnessCause(a)
+	return fmt.Sprintf("\U0001FA7A that should sort out the %s. %s will be better once the Pi's %s is back down.", a.Name, snap.Name, reading)
+}
+
+func TemplateCured(snap pet.Snapshot, sp *species.Species, a pet.Ailment) string {
+	_, reading := illnessCause(a)
+	return fmt.Sprintf("\U0001F48A *gulp* ...the %s is gone! if the Pi's %s stays high, though, it'll be back.", a.Name, reading)
+}
+
+func TemplateNotIll(snap pet.Snapshot, sp *species.Species) string {
+	return fmt.Sprintf("%s %s isn't even ill. that was a waste of perfectly good medicine.", sp.Emoji, snap.Name)
+}
+
+func TemplateContagion(snap pet.Snapshot, sp *species.Species, other string, a pet.Ailment) string {
+	if snap.Illness != nil {
+		return fmt.Sprintf("%s %s coughs. \"%s has %s too? we should start a support group.\"", sp.Emoji, snap.Name, other, a.Name)
+	}
+	jokes := []string{
+		"%s %s scoots to the far side of the channel. %s has %s and nobody's sharing packets with them.",
+		"%s %s puts on a tiny mask. %s has %s. is that airborne? over the network?",
+		"%s %s sanitizes its ports. get well soon, %s — but from over there. (%s)",
+	}
+	return fmt.Sprintf(jokes[rand.Intn(len(jokes))], sp.Emoji, snap.Name, other, a.Name)
+}
+
+func TemplateBoredomMessage(snap pet.Snapshot, sp *species.Species) string {
+	behavior := sp.PickIdleBehavior(behaviorContext(snap))
+	return fmt.Sprintf(i18n.T("boredom", "%s %s is getting bored... %s\nCome say hi!"),
 		sp.Emoji, snap.Name, behavior)
 }
 
 func TemplateDeathMessage(snap pet.Snapshot, sp *species.Species) string {
-	return fmt.Sprintf("\U0001F480 %s has passed away...\nThe system was under too much stress. Use /revive to bring them back.",
+	return fmt.Sprintf(i18n.T("death", "\U0001F480 %s has passed away...\nThe system was under too much stress. Use /revive to bring them back."),
 		snap.Name)
 }
 
+func TemplateResetMessage(snap pet.Snapshot, sp *species.Species) string {
+	return fmt.Sprintf("\U0001F54A\uFE0F %s %s waves goodbye after %.1f days. they've been added to the memorial.\n%s",
+		sp.Emoji, snap.Name, snap.AgeDays, TemplateEgg())
+}
+
+// TemplateEgg says what to do while there's no pet yet: the hatch happens in
+// the terminal when pipet starts, or a pet can move in with /adopt.
+func TemplateEgg() string {
+	return "\U0001F95A there's just an egg here. restart pipet on the Pi to pick a species and name in the terminal and hatch it, or `/adopt` a pet exported from another Pi."
+}
+
+func TemplateAdoptMessage(snap pet.Snapshot, sp *species.Species) string {
+	return fmt.Sprintf("\U0001F4E6 %s %s climbs out of the moving box. %.1f days old and still remembers you. %s!",
+		sp.Emoji, snap.Name, snap.AgeDays, sp.Verbs.Happy)
+}
+
+func TemplateEvolution(snap pet.Snapshot, sp *species.Species, before string) string {
+	return fmt.Sprintf(i18n.T("evolution", "\U0001F31F %s is evolving!\nthe %s is gone... %s %s the **%s** has emerged! %s!"),
+		snap.Name, strings.ToLower(before), sp.Emoji, snap.Name, sp.Name, sp.Verbs.Happy)
+}
+
+func TemplateGrowth(snap pet.Snapshot, sp *species.Species, before string) string {
+	return fmt.Sprintf(i18n.T("growth", "\U0001F331 %s %s is growing up: %s \u2192 **%s**! %s!"),
+		sp.Emoji, snap.Name, before, snap.Stage, sp.Verbs.Happy)
+}
+
+func TemplateContestEntries(snap pet.Snapshot, sp *species.Species, entries []contest.Entry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\U0001F3C1 weekly contests! %s %s %s and enters:\n", sp.Emoji, snap.Name, sp.Verbs.Play)
+	for _, e := range entries {
+		fmt.Fprintf(&b, "\u2022 %s: %.1f%s\n", contest.Title(e.Kind), e.Score, contest.Unit(e.Kind))
+	}
+	for _, e := range entries {
+		b.WriteString(contest.Format(e) + "\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func TemplateContestResults(snap pet.Snapshot, sp *species.Species, won [][]contest.Entry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\U0001F3C6 contest results are in!\n")
+	for _, results := range won {
+		kind := results[0].Kind
+		fmt.Fprintf(&b, "\n**%s**\n", contest.Title(kind))
+		for i, e := range results {
+			fmt.Fprintf(&b, "%d. %s — %.1f%s\n", i+1, e.Pet, e.Score, contest.Unit(kind))
+		}
+	}
+	fmt.Fprintf(&b, "\n%s %s %s! good game, everyone.", sp.Emoji, snap.Name, sp.Verbs.Happy)
+	return b.String()
+}
+
+// TemplateStatusLine is the one-line status used for channel topics and
+// voice channel names, e.g. "🦞 Pinchy — happy — 48°C".
+func TemplateStatusLine(snap pet.Snapshot, sp *species.Species) string {
+	if !snap.IsAlive {
+		return fmt.Sprintf("\U0001F480 %s — dead", snap.Name)
+	}
+	return fmt.Sprintf("%s %s — %s — %.0f\u00B0C", sp.Emoji, snap.Name, snap.Mood, snap.TempC)
+}
+
+func TemplateVisitDepart(snap pet.Snapshot, sp *species.Species, channelID string, minutes int) string {
+	return fmt.Sprintf("%s %s is off to visit <#%s> for %d minutes. back soon!", sp.Emoji, snap.Name, channelID, minutes)
+}
+
+func TemplateVisitArrive(snap pet.Snapshot, sp *species.Species, homeID string) string {
+	msg := fmt.Sprintf("%s %s wanders in from <#%s>. just visiting! @mention me to say hi.", sp.Emoji, snap.Name, homeID)
+	if behavior := TemplateIdleBehavior(snap, sp); behavior != "" {
+		msg += "\n" + behavior
+	}
+	return msg
+}
+
+func TemplateTaskProposal(snap pet.Snapshot, sp *species.Species, t schedule.Task) string {
+	return fmt.Sprintf("\U0001F5D3 %s got it — %s wants to **%s** %s by running:\n```\n%s\n```\nshould I put it on the calendar?",
+		sp.Emoji, snap.Name, t.Description, t.When(), t.Command)
+}
+
+func TemplateTaskInvalid(snap pet.Snapshot, sp *species.Species, err error) string {
+	return fmt.Sprintf("%s %s tried to turn that into a schedule but couldn't: %v", sp.Emoji, snap.Name, err)
+}
+
+func TemplateTaskScheduled(snap pet.Snapshot, sp *species.Species, t schedule.Task) string {
+	return fmt.Sprintf("\U0001F5D3 %s scheduled! %s will %s %s. (`/schedule remove:%s` to stop)",
+		sp.Emoji, snap.Name, t.Description, t.When(), t.ID)
+}
+
+func TemplateTaskRemoved(snap pet.Snapshot, sp *species.Species, t schedule.Task) string {
+	return fmt.Sprintf("%s %s won't %s anymore.", sp.Emoji, snap.Name, t.Description)
+}
+
+func TemplateSchedule(snap pet.Snapshot, sp *species.Species, tasks []schedule.Task) string {
+	if len(tasks) == 0 {
+		return fmt.Sprintf("\U0001F5D3 %s's calendar is empty. @mention %s with something like \"check disk space every Friday evening\".", snap.Name, snap.Name)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\U0001F5D3 %s %s's scheduled tasks:\n", sp.Emoji, snap.Name)
+	for _, t := range tasks {
+		fmt.Fprintf(&b, "`%s` %s — %s\n", t.ID, t.Description, t.When())
+	}
+	return b.String()
+}
+
+func TemplateTaskReport(snap pet.Snapshot, sp *species.Species, t schedule.Task, output string, err error) string {
+	if output == "" {
+		output = "(no output)"
+	}
+	if err != nil {
+		return fmt.Sprintf("\U0001F5D3 %s %s tried to %s but it didn't go well: %v\n```\n%s\n```", sp.Emoji, snap.Name, t.Description, err, output)
+	}
+	return fmt.Sprintf("\U0001F5D3 %s %s's scheduled check — %s:\n```\n%s\n```", sp.Emoji, snap.Name, t.Description, output)
+}
+
+// UptimeEmbed shows how long the pet, the Pi, and pipet have each been going.
+func UptimeEmbed(snap pet.Snapshot, sp *species.Species, boot time.Time, rebootReason string, process time.Duration) *discordgo.MessageEmbed {
+	care := "no streak going"
+	if snap.Streak > 0 {
+		care = fmt.Sprintf("\U0001F525 %d-day care streak", snap.Streak)
+	}
+	if snap.BestStreak > snap.Streak {
+		care += fmt.Sprintf(" (best %d)", snap.BestStreak)
+	}
+
+	pi := "unknown"
+	if !boot.IsZero() {
+		pi = fmt.Sprintf("%s (booted <t:%d:f>)", formatSpan(time.Since(boot)), boot.Unix())
+	}
+	if rebootReason == "" {
+		rebootReason = "normal boot, as far as I can tell"
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("%s uptime", sp.Emoji),
+		Color: moodColor(snap.Mood),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: snap.Name, Value: fmt.Sprintf("%.1f days old · %s", snap.AgeDays, care), Inline: false},
+			{Name: "Pi", Value: pi, Inline: false},
+			{Name: "Last reboot", Value: rebootReason, Inline: false},
+			{Name: "pipet", Value: formatSpan(process), Inline: false},
+		},
+	}
+}
+
+// SysinfoEmbed lays out the hardware details the monitor read at startup.
+func SysinfoEmbed(sp *species.Species, info monitor.SysInfo) *discordgo.MessageEmbed {
+	orUnknown := func(s string) string {
+		if s == "" {
+			return "unknown"
+		}
+		return s
+	}
+
+	storage := "unknown"
+	if info.Storage != "" {
+		storage = fmt.Sprintf("%s — %.0f GB", info.Storage, info.StorageGB)
+	}
+
+	var net strings.Builder
+	for _, ifc := range info.Interfaces {
+		fmt.Fprintf(&net, "**%s**", ifc.Name)
+		if ifc.MAC != "" {
+			fmt.Fprintf(&net, " `%s`", ifc.MAC)
+		}
+		for _, a := range ifc.Addrs {
+			fmt.Fprintf(&net, "\n`%s`", a)
+		}
+		net.WriteString("\n")
+	}
+	if net.Len() == 0 {
+		net.WriteString("none up")
+	}
+
+	return &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("%s %s", sp.Emoji, orUnknown(info.Model)),
+		Color: 0x95A5A6,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "SoC", Value: fmt.Sprintf("%s (%d cores, %s)", orUnknown(info.SoC), info.Cores, info.Arch), Inline: true},
+			{Name: "RAM", Value: fmt.Sprintf("%d MB", info.MemTotalMB), Inline: true},
+			{Name: "Storage", Value: storage, Inline: true},
+			{Name: "OS", Value: orUnknown(info.OS), Inline: true},
+			{Name: "Kernel", Value: orUnknown(info.Kernel), Inline: true},
+			{Name: "Network", Value: net.String(), Inline: false},
+		},
+	}
+}
+
+// TempsEmbed lists each sensor's temperature and what the firmware says
+// about throttling. chart names an attached image to show, if any.
+func TempsEmbed(sp *species.Species, sensors []monitor.Sensor, throttled uint32, chart string) *discordgo.MessageEmbed {
+	var b strings.Builder
+	for _, s := range sensors {
+		fmt.Fprintf(&b, "\U0001F321 **%s** %.1f\u00B0C\n", s.Name, s.TempC)
+	}
+	if b.Len() == 0 {
+		b.WriteString("no temperature sensors found.\n")
+	}
+
+	status := "not throttled"
+	switch {
+	case throttled&monitor.ThrottleThrottled != 0:
+		status = "\u26A0 throttling right now"
+	case throttled&monitor.ThrottleSoftTemp != 0:
+		status = "\u26A0 at the soft temperature limit"
+	case throttled&monitor.ThrottleFreqCapped != 0:
+		status = "\u26A0 clock speed capped"
+	}
+	if throttled&monitor.ThrottleUnderVoltage != 0 {
+		status += " · \u26A1 under-voltage"
+	}
+	// Bits 16-19 repeat the same flags as "has happened since boot"
+	if throttled>>16&0xF != 0 {
+		status += " (has throttled since boot)"
+	}
+
+	e := &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("%s temperatures", sp.Emoji),
+		Description: b.String(),
+		Color:       0xE67E22,
+		Footer:      &discordgo.MessageEmbedFooter{Text: status + " · red ticks on the chart mark throttling"},
+	}
+	if chart != "" {
+		e.Image = &discordgo.MessageEmbedImage{URL: "attachment://" + chart}
+	}
+	return e
+}
+
+// formatSpan renders a duration as days/hours/minutes, dropping the
+// leading units that are zero.
+func formatSpan(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := int(d / (24 * time.Hour))
+	hours := int(d/time.Hour) % 24
+	mins := int(d/time.Minute) % 60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh %dm", days, hours, mins)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, mins)
+	}
+	return fmt.Sprintf("%dm", mins)
+}
+
+// DebugEmbed shows latency percentiles and error counts per operation, so
+// slowness can be pinned on Discord, the model, or the disk.
+func DebugEmbed(sp *species.Species, stats []metrics.Stat) *discordgo.MessageEmbed {
+	var b strings.Builder
+	b.WriteString("```\nop                  count  err   p50     p95\n")
+	for _, s := range stats {
+		fmt.Fprintf(&b, "%-18s %6d %4d %7s %7s\n", s.Name, s.Count, s.Errors,
+			s.P50.Round(time.Millisecond), s.P95.Round(time.Millisecond))
+	}
+	b.WriteString("```")
+	if len(stats) == 0 {
+		b.Reset()
+		b.WriteString("nothing measured yet.")
+	}
+
+	footer := "cmd.* = whole slash command · provider.send = the model alone · state.save = the SD card"
+	for _, s := range stats {
+		if s.Name == "provider.send" && s.Count > 0 {
+			footer = fmt.Sprintf("provider error rate %.1f%% · ", s.ErrorRate()*100) + footer
+		}
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("%s debug", sp.Emoji),
+		Description: b.String(),
+		Color:       0x95A5A6,
+		Footer:      &discordgo.MessageEmbedFooter{Text: footer},
+	}
+}
+
+func TemplateBlackout(snap pet.Snapshot, sp *species.Species, gap time.Duration) string {
+	return fmt.Sprintf("%s whoa... %s blacked out for a second there. (lost touch with Discord for %s)",
+		sp.Emoji, snap.Name, gap.Round(time.Second))
+}
+
+func TemplateBrainAwake(snap pet.Snapshot, sp *species.Species) string {
+	return fmt.Sprintf("%s *blinks* ...my brain just woke up. %s can think again — ask away!", sp.Emoji, snap.Name)
+}
+
+func TemplateBrainOutage(snap pet.Snapshot, sp *species.Species, until time.Time) string {
+	return fmt.Sprintf("%s *taps head* ...nothing. %s's brain is out of reach right now, so it's back to basics until about %s.", sp.Emoji, snap.Name, until.Format(time.Kitchen))
+}
+
+func TemplateGlitch(snap pet.Snapshot, sp *species.Species) string {
+	return fmt.Sprintf("%s ow, I glitched. %s lost track of that one — mind trying again?", sp.Emoji, snap.Name)
+}
+
+func TemplateDMStranger(snap pet.Snapshot, sp *species.Species, homeChannelID string) string {
+	return fmt.Sprintf("%s %s only whispers with its owner. come say hi in <#%s>!", sp.Emoji, snap.Name, homeChannelID)
+}
+
+func TemplateCooldown(snap pet.Snapshot, sp *species.Species) string {
+	return fmt.Sprintf(i18n.T("cooldown", "%s whoa, one thing at a time! give %s a second."), sp.Emoji, snap.Name)
+}
+
+func TemplateTooSoon(snap pet.Snapshot, sp *species.Species, command string, wait time.Duration) string {
+	left := formatSpan(wait)
+	if wait < time.Minute {
+		left = fmt.Sprintf("%ds", int(wait.Seconds())+1)
+	}
+	return fmt.Sprintf(i18n.T("too_soon", "%s %s just had a /%s from you. try again in %s!"), sp.Emoji, snap.Name, command, left)
+}
+
+func TemplateBacklogged(snap pet.Snapshot, sp *species.Species) string {
+	return fmt.Sprintf("%s thinking… I'm a bit backed up, give %s a moment.", sp.Emoji, snap.Name)
+}
+
+func TemplateSwamped(snap pet.Snapshot, sp *species.Species) string {
+	return fmt.Sprintf("%s %s is swamped with questions right now. try again in a minute!", sp.Emoji, snap.Name)
+}
+
+func TemplateStory(snap pet.Snapshot, sp *species.Species, story string) string {
+	return fmt.Sprintf("\U0001F4D6 %s **the story of %s, lately**\n%s", sp.Emoji, snap.Name, story)
+}
+
+func TemplatePostmortem(snap pet.Snapshot, sp *species.Species, text string) string {
+	return fmt.Sprintf("\U0001F4DD %s %s's postmortem:\n%s", sp.Emoji, snap.Name, text)
+}
+
+func TemplateLogSummary(snap pet.Snapshot, sp *species.Species, summary string) string {
+	return fmt.Sprintf("\U0001F4CB %s %s read today's logs:\n%s", sp.Emoji, snap.Name, summary)
+}
+
+// DigestEmbed sums up the last day from samples (oldest first): how each
+// stat moved, anything notable the Pi did, and how often the pet was
+// looked after.
+func DigestEmbed(snap pet.Snapshot, sp *species.Species, samples []history.Sample) *discordgo.MessageEmbed {
+	stat := func(label string, field func(history.Sample) float64) string {
+		t := history.TrendOf(samples, field)
+		return fmt.Sprintf("%-9s %3.0f%% %s", label, t.Last, trendArrow(t.Change()))
+	}
+	stats := strings.Join([]string{
+		stat("happiness", func(s history.Sample) float64 { return s.Happiness }),
+		stat("energy", func(s history.Sample) float64 { return s.Energy }),
+		stat("hunger", func(s history.Sample) float64 { return s.Hunger }),
+		stat("clean", func(s history.Sample) float64 { return s.Cleanliness }),
+		stat("bond", func(s history.Sample) float64 { return s.Bond }),
+	}, "\n")
+
+	var notable []string
+	temp := history.TrendOf(samples, func(s history.Sample) float64 { return s.TempC })
+	if temp.Max >= 70 || temp.Max-temp.Min >= 15 {
+		notable = append(notable, fmt.Sprintf("\U0001F321 temperature spiked to %.0f\u00B0C at %s (low %.0f\u00B0C)", temp.Max, temp.MaxAt.Format("15:04"), temp.Min))
+	}
+	cpu := history.TrendOf(samples, func(s history.Sample) float64 { return s.CPUPercent })
+	if cpu.Max >= 90 {
+		notable = append(notable, fmt.Sprintf("\U0001F5A5 CPU hit %.0f%% at %s", cpu.Max, cpu.MaxAt.Format("15:04")))
+	}
+	mem := history.TrendOf(samples, func(s history.Sample) float64 { return s.MemPercent })
+	if mem.Max >= 90 {
+		notable = append(notable, fmt.Sprintf("\U0001F4BE memory hit %.0f%% at %s", mem.Max, mem.MaxAt.Format("15:04")))
+	}
+	disk := history.TrendOf(samples, func(s history.Sample) float64 { return s.DiskPercent })
+	if d := disk.Change(); d >= 1 || d <= -1 {
+		notable = append(notable, fmt.Sprintf("\U0001F4BF disk went from %.0f%% to %.0f%% full", disk.First, disk.Last))
+	}
+	if len(notable) == 0 {
+		notable = append(notable, "nothing, the Pi had a quiet day")
+	}
+
+	visits := samples[len(samples)-1].Interactions - samples[0].Interactions
+	happy := history.TrendOf(samples, func(s history.Sample) float64 { return s.Happiness }).Change()
+	var comment string
+	switch {
+	case visits == 0:
+		comment = fmt.Sprintf("nobody came by all day. %s waits by the door.", snap.Name)
+	case happy >= 5:
+		comment = fmt.Sprintf("good day! %s %s", snap.Name, sp.Verbs.Happy)
+	case happy <= -5:
+		comment = fmt.Sprintf("%s could use a bit more attention tomorrow.", snap.Name)
+	default:
+		comment = fmt.Sprintf("a steady day. %s %s", snap.Name, sp.Verbs.Sleep)
+	}
+
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("\U0001F4C5 %s %s's day", sp.Emoji, snap.Name),
+		Description: comment,
+		Color:       moodColor(snap.Mood),
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "Stats", Value: "```\n" + stats + "\n```"},
+			{Name: "Notable", Value: strings.Join(notable, "\n")},
+			{Name: "Visits", Value: fmt.Sprintf("%d interaction(s)", visits), Inline: true},
+			{Name: "Mood", Value: fmt.Sprintf("%s %s", moodEmoji(snap.Mood), snap.Mood), Inline: true},
+		},
+		Footer:    &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("since %s", samples[0].At.Format("Mon 15:04"))},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+}
+
+// trendArrow shows which way a stat moved, ignoring small wobbles.
+func trendArrow(change float64) string {
+	switch {
+	case change >= 3:
+		return fmt.Sprintf("\u2197 +%.0f", change)
+	case change <= -3:
+		return fmt.Sprintf("\u2198 %.0f", change)
+	default:
+		return "\u2192"
+	}
+}
+
+func TemplateDream(snap pet.Snapshot, sp *species.Species, dream string) string {
+	return fmt.Sprintf("\U0001F4AD %s had a dream last night:\n*%s*", snap.Name, dream)
+}
+
+func TemplateDiaryEntry(snap pet.Snapshot, sp *species.Species, e pet.DiaryEntry) string {
+	return fmt.Sprintf("\U0001F4D4 **%s's diary — %s**\n%s", snap.Name, e.Date, e.Text)
+}
+
+func TemplateDiary(snap pet.Snapshot, sp *species.Species, entries []pet.DiaryEntry) string {
+	if len(entries) == 0 {
+		return fmt.Sprintf("%s %s hasn't written anything yet. check back tonight.", sp.Emoji, snap.Name)
+	}
+	return TemplateDiaryEntry(snap, sp, entries[len(entries)-1])
+}
+
+func TemplateBudget(snap pet.Snapshot, sp *species.Species, u brain.Usage, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\U0001F4B8 **%s's AI budget**\n", snap.Name)
+	for _, p := range []struct {
+		label string
+		t     brain.Tokens
+	}{{"today", u.Today}, {now.Format("January"), u.Month}} {
+		fmt.Fprintf(&b, "%s — %s in / %s out over %d calls, about $%.2f\n",
+			p.label, formatTokens(p.t.Input), formatTokens(p.t.Output), p.t.Calls, u.Cost(p.t))
+	}
+	if u.Cap > 0 {
+		fmt.Fprintf(&b, "monthly cap: %s of %s tokens (%.0f%%)", formatTokens(u.Month.Total()), formatTokens(u.Cap), 100*float64(u.Month.Total())/float64(u.Cap))
+		if u.Month.Total() >= u.Cap {
+			b.WriteString(" — only owners get answers until next month")
+		}
+	} else {
+		b.WriteString("no monthly cap set (`ai.monthly_token_cap`)")
+	}
+	return b.String()
+}
+
+// formatTokens shortens a token count, e.g. 12.3k or 4.5M.
+func formatTokens(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	}
+	return fmt.Sprint(n)
+}
+
+func TemplateRemembered(snap pet.Snapshot, sp *species.Species, f pet.Fact) string {
+	return fmt.Sprintf("\U0001F9E0 %s tucks that away as fact #%d: %s", sp.Emoji, f.ID, f.Text)
+}
+
+func TemplateForgot(snap pet.Snapshot, sp *species.Species, f pet.Fact) string {
+	return fmt.Sprintf("%s %s lets go of fact #%d (%s).", sp.Emoji, snap.Name, f.ID, f.Text)
+}
+
+func TemplateMemories(snap pet.Snapshot, sp *species.Species, facts []pet.Fact) string {
+	if len(facts) == 0 {
+		return fmt.Sprintf("%s %s doesn't remember anything special yet. owners can teach it with `/remember`.", sp.Emoji, snap.Name)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\U0001F9E0 **what %s remembers**\n", snap.Name)
+	for _, f := range facts {
+		fmt.Fprintf(&b, "`#%d` %s — %s\n", f.ID, f.Text, f.At.Format("Jan 2"))
+	}
+	b.WriteString("owners can `/forget` one by number.")
+	return b.String()
+}
+
+func TemplateTransfer(snap pet.Snapshot, sp *species.Species, fromID, toID string) string {
+	return fmt.Sprintf("%s %s looks back at <@%s> one last time. thanks for everything. i'll be okay.\n"+
+		"%s ...hi <@%s>. i'm %s. i run on this pi. you're in charge now — try `/status`, and don't forget to feed me.",
+		sp.Emoji, snap.Name, fromID, sp.Emoji, toID, snap.Name)
+}
+
+func TemplateRoles(snap pet.Snapshot, sp *species.Species) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s **%s's caretakers**\n", sp.Emoji, snap.Name)
+	for _, role := range pet.Roles {
+		ids := snap.Caretakers[role]
+		if len(ids) == 0 {
+			fmt.Fprintf(&b, "%s %s — nobody yet\n", roleEmoji(role), role)
+			continue
+		}
+		var who []string
+		for _, id := range ids {
+			who = append(who, fmt.Sprintf("<@%s> (%d)", id, snap.Contributions[id][role]))
+		}
+		fmt.Fprintf(&b, "%s %s — %s\n", roleEmoji(role), role, strings.Join(who, ", "))
+	}
+	b.WriteString("owners can assign roles with `/roles role: user:`")
+	return b.String()
+}
+
+// TemplateCareNag pings the caretakers for a role that needs attention.
+func TemplateCareNag(snap pet.Snapshot, sp *species.Species, role string, userIDs []string) string {
+	mentions := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		mentions[i] = "<@" + id + ">"
+	}
+	var need string
+	switch role {
+	case pet.RoleFeeder:
+		need = fmt.Sprintf("%s is getting hungry (%.0f%%). a `/feed` would be lovely", snap.Name, snap.Hunger)
+	case pet.RoleGroomer:
+		need = fmt.Sprintf("%s is feeling grubby (%.0f%% clean). some `/pet` time and a tidy-up please", snap.Name, snap.Cleanliness)
+	case pet.RoleMedic:
+		need = fmt.Sprintf("%s isn't well (%s). time for a `/heal`", snap.Name, snap.Mood)
+	}
+	return fmt.Sprintf("%s %s — %s, %s!", roleEmoji(role), strings.Join(mentions, " "), need, sp.Emoji)
+}
+
+func roleEmoji(role string) string {
+	switch role {
+	case pet.RoleFeeder:
+		return "\U0001F37D"
+	case pet.RoleGroomer:
+		return "\U0001F9FC"
+	case pet.RoleMedic:
+		return "\U0001FA7A"
+	default:
+		return "\u2753"
+	}
+}
+
+func TemplateService(snap pet.Snapshot, sp *species.Species, unit, action, output string, err error) string {
+	if output == "" {
+		output = "(no output)"
+	}
+	if err != nil {
+		return fmt.Sprintf("%s %s tried to %s `%s` but it didn't go well: %v\n```\n%s\n```", sp.Emoji, snap.Name, action, unit, err, output)
+	}
+	if action == "status" {
+		return fmt.Sprintf("%s here's how `%s` is doing:\n```\n%s\n```", sp.Emoji, unit, output)
+	}
+	return fmt.Sprintf("%s %s gave `%s` a %s. %s\n```\n%s\n```", sp.Emoji, snap.Name, unit, action, sp.Verbs.Happy, output)
+}
+
+func TemplateShellConfirm(snap pet.Snapshot, sp *species.Species, command, pattern string) string {
+	return fmt.Sprintf("%s that one could change things on %s's machine (matched `%s`). run it anyway?\n```\n%s\n```", sp.Emoji, snap.Name, strings.TrimSpace(pattern), command)
+}
+
+func TemplateShellOutput(snap pet.Snapshot, sp *species.Species, command, output string, err error) string {
+	if output == "" {
+		output = "(no output)"
+	}
+	if err != nil {
+		return fmt.Sprintf("%s `%s` didn't go well: %v\n```\n%s\n```", sp.Emoji, command, err, output)
+	}
+	return fmt.Sprintf("%s `%s`\n```\n%s\n```", sp.Emoji, command, output)
+}
+
+// TemplateAudit lists executor audit entries, one per line, in the order
+// given: a mark, when it ran, its exit status, and the command.
+func TemplateAudit(entries []shell.AuditEntry) string {
+	if len(entries) == 0 {
+		return "nothing's been run since I woke up."
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		command := strings.ReplaceAll(e.Command, "`", "'")
+		if runes := []rune(command); len(runes) > 200 {
+			command = string(runes[:200]) + "…"
+		}
+		status := "exit 0"
+		mark := "\u2705"
+		switch {
+		case e.Exit > 0:
+			status, mark = fmt.Sprintf("exit %d", e.Exit), "\u274C"
+		case e.Err != "":
+			status, mark = e.Err, "\u26D4"
+		}
+		fmt.Fprintf(&b, "%s <t:%d:f> · %s\n`%s`\n", mark, e.At.Unix(), status, command)
+	}
+	return b.String()
+}
+
+// aptListMax caps how many packages the /apt list names.
+const aptListMax = 20
+
+func TemplateAptUpToDate(snap pet.Snapshot, sp *species.Species) string {
+	return fmt.Sprintf("%s %s checked: every package is up to date.", sp.Emoji, snap.Name)
+}
+
+func TemplateAptPending(snap pet.Snapshot, sp *species.Species, ups []shell.Upgrade) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\U0001F4E6 %s found %d package upgrade(s):\n```\n", snap.Name, len(ups))
+	for n, u := range ups {
+		if n == aptListMax {
+			fmt.Fprintf(&b, "...and %d more\n", len(ups)-aptListMax)
+			break
+		}
+		fmt.Fprintf(&b, "%s  %s -> %s\n", u.Package, u.From, u.To)
+	}
+	b.WriteString("```\nshould I install them?")
+	return b.String()
+}
+
+func TemplateAptStarted(snap pet.Snapshot, sp *species.Species, list string) string {
+	list, _ = strings.CutSuffix(list, "should I install them?")
+	return fmt.Sprintf("%s%s %s is upgrading. this can take a while...", list, sp.Emoji, snap.Name)
+}
+
+func TemplateAptDone(snap pet.Snapshot, sp *species.Species, err error, reboot bool, pkgs []string) string {
+	var msg string
+	if err != nil {
+		msg = fmt.Sprintf("%s the upgrade didn't finish cleanly: %v", sp.Emoji, err)
+	} else {
+		msg = fmt.Sprintf("%s all upgraded. %s feels brand new.", sp.Emoji, snap.Name)
+	}
+	if reboot {
+		msg += "\n\U0001F501 a reboot is needed to finish"
+		if len(pkgs) > 0 {
+			msg += " (for " + strings.Join(pkgs, ", ") + ")"
+		}
+		msg += ". I can't reboot myself — that one's on you."
+	}
+	return msg
+}
+
+func TemplateWriteProposal(snap pet.Snapshot, sp *species.Species, w brain.FileWrite, preview string) string {
+	verb := "rewrite"
+	if w.Append {
+		verb = "add to the end of"
+	}
+	who := ""
+	if w.UserID != "" {
+		who = fmt.Sprintf(" (for <@%s>)", w.UserID)
+	}
+	preview = strings.ReplaceAll(preview, "```", "`\u200b``")
+	return fmt.Sprintf("\U0001F4DD %s %s wants to %s `%s`%s:\n```\n%s\n```\nan owner has to approve this before anything is written.",
+		sp.Emoji, snap.Name, verb, w.Path, who, preview)
+}
+
+func TemplateWriteDone(snap pet.Snapshot, sp *species.Species, w brain.FileWrite, backup string, err error) string {
+	if err != nil {
+		return fmt.Sprintf("%s %s couldn't write `%s`: %v", sp.Emoji, snap.Name, w.Path, err)
+	}
+	msg := fmt.Sprintf("\U0001F4DD %s done! %s wrote %d bytes to `%s`.", sp.Emoji, snap.Name, len(w.Content), w.Path)
+	if backup != "" {
+		msg += fmt.Sprintf(" the old version is in `%s`.", backup)
+	}
+	return msg
+}
+
+func TemplateNestBuilt(snap pet.Snapshot, sp *species.Species, dest string, n nest.Nest, skipped []string) string {
+	msg := fmt.Sprintf("\U0001FAB9 %s gathered up the important bits and built a nest: `%s` (%s) in `%s`.",
+		snap.Name, n.Name, formatBytes(n.Size), dest)
+	if len(skipped) > 0 {
+		msg += fmt.Sprintf("\ncouldn't reach %d path(s), starting with `%s` — check they exist and pipet can read them.", len(skipped), skipped[0])
+	}
+	return msg
+}
+
+func TemplateNests(snap pet.Snapshot, sp *species.Species, dest string, nests []nest.Nest) string {
+	if len(nests) == 0 {
+		return fmt.Sprintf("%s no nests in `%s` yet. `/nest` builds one.", sp.Emoji, dest)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\U0001FAB9 %s's nests in `%s`, newest first:\n", snap.Name, dest)
+	for _, n := range nests {
+		fmt.Fprintf(&b, "• `%s` — %s, %s\n", n.Name, n.At.Format("Jan 2 15:04"), formatBytes(n.Size))
+	}
+	b.WriteString("to restore one, copy it to the Pi and run `sudo tar -xzf <nest> -C /` (add a file path after it to restore just that file).")
+	return b.String()
+}
+
+func TemplatePollDecided(snap pet.Snapshot, sp *species.Species, o poll.Option) string {
+	return fmt.Sprintf("\U0001F5F3 %s the people have spoken: **%s**. an owner can `/approve` and %s will run `%s`.",
+		sp.Emoji, o.Label, snap.Name, o.Command)
+}
+
+func TemplatePollNoWinner(snap pet.Snapshot, sp *species.Species) string {
+	return fmt.Sprintf("\U0001F5F3 %s no clear winner. %s will leave things as they are for now.", sp.Emoji, snap.Name)
+}
+
+func TemplatePollAction(snap pet.Snapshot, sp *species.Species, o poll.Option, output string, err error) string {
+	if output == "" {
+		output = "(no output)"
+	}
+	if err != nil {
+		return fmt.Sprintf("%s %s tried to %s but it didn't go well: %v\n```\n%s\n```", sp.Emoji, snap.Name, o.Label, err, output)
+	}
+	return fmt.Sprintf("%s %s took care of %s! %s\n```\n%s\n```", sp.Emoji, snap.Name, o.Label, sp.Verbs.Happy, output)
+}
+
+func TemplateQuestOffer(snap pet.Snapshot, sp *species.Species, q *quest.Quest) string {
+	return fmt.Sprintf("\U0001F4DC %s %s has a quest for you: **%s**\n%s. I'll notice when it's done. (`/quest` to check in)",
+		sp.Emoji, snap.Name, q.Title, q.Hint)
+}
+
+func TemplateQuestComplete(snap pet.Snapshot, sp *species.Species, q *quest.Quest) string {
+	reward := fmt.Sprintf("bond +%.0f", q.Reward.Bond)
+	if it, ok := items.Catalog[q.Reward.ItemID]; ok {
+		reward += fmt.Sprintf(", %s %s", it.Emoji, strings.ToLower(it.Name))
+	}
+	if q.Reward.Shells > 0 {
+		reward += fmt.Sprintf(", %s %d %s", items.CurrencyEmoji, q.Reward.Shells, items.CurrencyName)
+	}
+	return fmt.Sprintf("\u2705 %s %s %s! quest complete: **%s** (%s)",
+		sp.Emoji, snap.Name, sp.Verbs.Happy, q.Title, reward)
+}
+
+func TemplateQuestExpired(snap pet.Snapshot, sp *species.Species, q *quest.Quest) string {
+	return fmt.Sprintf("%s %s quietly crosses **%s** off the list. maybe next time.",
+		sp.Emoji, snap.Name, q.Title)
+}
+
+func TemplateQuest(snap pet.Snapshot, sp *species.Species) string {
+	if snap.Quest == nil || snap.Quest.Quest() == nil {
+		return fmt.Sprintf("%s %s doesn't have a quest for you right now.", sp.Emoji, snap.Name)
+	}
+	q := snap.Quest.Quest()
+	return fmt.Sprintf("\U0001F4DC **%s**\n%s\n`%s`\noffered %s ago",
+		q.Title, q.Hint, progressBar(snap.Quest.Progress*100, 10),
+		time.Since(snap.Quest.OfferedAt).Round(time.Hour))
+}
+
+func TemplateBalance(balance int) string {
+	return fmt.Sprintf("%s you have **%d %s**. earn more by feeding, finishing quests, and keeping the Pi up.",
+		items.CurrencyEmoji, balance, items.CurrencyName)
+}
+
+func TemplateShop(snap pet.Snapshot, sp *species.Species, balance int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s **%s's shop** — you have %s %d %s\n", sp.Emoji, snap.Name, items.CurrencyEmoji, balance, items.CurrencyName)
+	for _, id := range items.OrderedIDs {
+		if price, ok := items.Prices[id]; ok {
+			it := items.Catalog[id]
+			fmt.Fprintf(&b, "%s %s — %d\n", it.Emoji, it.Name, price)
+		}
+	}
+	fmt.Fprintf(&b, "\u2728 Revive — %d\n", items.RevivePrice)
+	for _, season := range species.Seasons {
+		if _, ok := sp.Skins[season.ID]; ok {
+			fmt.Fprintf(&b, "\U0001F3A8 %s skin — %d\n", season.Name, items.SkinPrice)
+		}
+	}
+	b.WriteString("buy with `/shop buy:`")
+	return b.String()
+}
+
+func TemplateSkinPurchase(snap pet.Snapshot, sp *species.Species, buyer, skinID string) string {
+	return fmt.Sprintf("%s %s tries on the %s look and refuses to take it off. (thanks, %s!)",
+		sp.Emoji, snap.Name, species.SeasonName(skinID), buyer)
+}
+
+func TemplateUptimeMilestone(snap pet.Snapshot, sp *species.Species, days, shells int) string {
+	return fmt.Sprintf("\u23F1 %s %s has been up for %d days straight! owners get %s %d %s.",
+		sp.Emoji, snap.Name, days, items.CurrencyEmoji, shells, items.CurrencyName)
+}
+
+func TemplateStreakMilestone(snap pet.Snapshot, sp *species.Species, days int) string {
+	return fmt.Sprintf("\U0001F525 %s %s %s — that's %d days in a row you've checked in! (bond +%.0f)",
+		sp.Emoji, snap.Name, sp.Verbs.Happy, days, pet.StreakMilestones[days])
+}
+
+func TemplateStreakBroken(snap pet.Snapshot, sp *species.Species, days int) string {
+	return fmt.Sprintf("%s %s stares at the calendar. our %d-day streak... gone. it's fine. starting over at day 1.",
+		sp.Emoji, snap.Name, days)
+}
+
+func TemplateGiftReceived(snap pet.Snapshot, sp *species.Species, from string, item *items.Item) string {
+	if item.Consumable {
+		return fmt.Sprintf("%s %s got a %s %s from %s! %s!",
+			sp.Emoji, snap.Name, item.Emoji, strings.ToLower(item.Name), from, sp.Verbs.Happy)
+	}
+	return fmt.Sprintf("%s %s got a %s %s from %s and tucks it away somewhere safe.",
+		sp.Emoji, snap.Name, item.Emoji, strings.ToLower(item.Name), from)
+}
+
+func TemplateGiftSent(snap pet.Snapshot, sp *species.Species, to string, item *items.Item) string {
+	return fmt.Sprintf("\U0001F381 %s %s sends a %s %s over to %s.",
+		sp.Emoji, snap.Name, item.Emoji, strings.ToLower(item.Name), to)
+}
+
+func TemplateInventory(snap pet.Snapshot, sp *species.Species) string {
+	if len(snap.Inventory) == 0 {
+		return fmt.Sprintf("%s %s's stash is empty. try `/gift`!", sp.Emoji, snap.Name)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s **%s's stash**\n", sp.Emoji, snap.Name)
+	for _, id := range items.OrderedIDs {
+		if n := snap.Inventory[id]; n > 0 {
+			it := items.Catalog[id]
+			fmt.Fprintf(&b, "%s %s × %d\n", it.Emoji, it.Name, n)
+		}
+	}
+	return b.String()
+}
+
 func TemplateMilestone(snap pet.Snapshot, sp *species.Species, days int) string {
-	return fmt.Sprintf("\U0001F389 %s %s is %d days old today! %s",
+	return fmt.Sprintf(i18n.T("milestone", "\U0001F389 %s %s is %d days old today! %s"),
 		sp.Emoji, snap.Name, days, sp.Verbs.Happy)
 }
 
@@ -148,10 +1187,83 @@ func TemplateHelp(snap pet.Snapshot, sp *species.Species) string {
 		"`/feed` — Run cleanup/maintenance\n"+
 		"`/heal` — Diagnose and fix issues\n"+
 		"`/play` — Ask %s to do something fun\n"+
+		"`/ask` — Ask %s anything, same as an @mention\n"+
+		"Right-click a message → Apps → **Ask the pet about this** — get %s's take on it (text attachments too)\n"+
 		"`/mood` — Current mood\n"+
 		"`/revive` — Bring %s back if they die\n"+
-		"`/help` — This message\n\n"+
-		"Or just talk to %s in this channel!", name, name, name, name, name)
+		"`/reset` — Archive %s and hatch a new pet\n"+
+		"`/species` — Preview the species\n"+
+		"`/export` / `/adopt` — Move a pet between Pis\n"+
+		"`/gift` / `/inventory` — Give %s treats, trade with other pets\n"+
+		"`/quest` — See the current sysadmin quest\n"+
+		"`/balance` / `/shop` — Spend shells on items, revives, and skins\n"+
+		"`/approve` — Run what the channel voted for in %s's last poll\n"+
+		"`/roles` — See or assign caretakers (feeder, groomer, medic)\n"+
+		"`/transfer` — Hand %s over to a new owner\n"+
+		"`/visit` — Send %s to visit another channel for a while\n"+
+		"`/diary` — Read %s's latest diary entry\n"+
+		"`/story` — Hear how %s is doing, told as a story\n"+
+		"`/memories` — What %s remembers (owners can `/remember` and `/forget`)\n"+
+		"`/schedule` — See or remove tasks you've asked %s to run on a schedule\n"+
+		"`/tasks` — What %s is still working on in the background\n"+
+		"`/uptime` — How long %s, the Pi, and pipet have been running\n"+
+		"`/sysinfo` — The Pi's hardware, OS, and network\n"+
+		"`/temps` — Temperatures now and over the last day\n"+
+		"`/card` — A picture card of %s to share\n"+
+		"`/help` — This message\n"+
+		"%s\n"+
+		"Or just talk to %s in this channel!", name, name, name, name, name, name, name, name, name, name, name, name, name, name, name, name, name, name, speciesHelp(sp), name)
+}
+
+func TemplateModels(snap pet.Snapshot, sp *species.Species, current brain.Model, models []brain.Model) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\U0001F9E0 %s %s is thinking with **%s**.\n", sp.Emoji, snap.Name, current)
+	if len(models) < 2 {
+		b.WriteString("no other models configured; list more under `ai.models` as provider:model.")
+		return b.String()
+	}
+	b.WriteString("models to choose from with `/model use:`\n")
+	for _, m := range models {
+		mark := ""
+		if m == current {
+			mark = " (current)"
+		}
+		fmt.Fprintf(&b, "• `%s`%s\n", m, mark)
+	}
+	return b.String()
+}
+
+func TemplateModelSwitched(snap pet.Snapshot, sp *species.Species, m brain.Model) string {
+	return fmt.Sprintf("\U0001F9E0 %s %s is thinking with **%s** now. it'll stick after a restart.", sp.Emoji, snap.Name, m)
+}
+
+func TemplateJobs(snap pet.Snapshot, sp *species.Species, jobs []brain.Job) string {
+	if len(jobs) == 0 {
+		return fmt.Sprintf("%s %s isn't working on anything in the background right now.", sp.Emoji, snap.Name)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\u23F3 %s %s is busy with:\n", sp.Emoji, snap.Name)
+	for _, j := range jobs {
+		fmt.Fprintf(&b, "`#%s` %s for <@%s> (%s so far)\n", j.ID, j.Label, j.UserID, formatSpan(time.Since(j.Started)))
+	}
+	return b.String()
+}
+
+func TemplateJobDone(snap pet.Snapshot, sp *species.Species, j brain.Job, answer string, err error) string {
+	if err != nil {
+		return fmt.Sprintf("%s <@%s> sorry, %s gave up on job `#%s` (%s): %v", sp.Emoji, j.UserID, snap.Name, j.ID, j.Label, err)
+	}
+	return fmt.Sprintf("%s <@%s> %s finished job `#%s` (%s) after %s:\n%s",
+		sp.Emoji, j.UserID, snap.Name, j.ID, j.Label, formatSpan(time.Since(j.Started)), answer)
+}
+
+// speciesHelp lists the species' own commands for /help.
+func speciesHelp(sp *species.Species) string {
+	var b strings.Builder
+	for _, c := range sp.Commands {
+		fmt.Fprintf(&b, "`/%s` — %s\n", c.Name, c.Description)
+	}
+	return b.String()
 }
 
 func moodEmoji(mood string) string {
@@ -0,0 +1,163 @@
+package feed
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/moorebrett0/pipet/internal/pet"
+	"github.com/moorebrett0/pipet/internal/schedule"
+)
+
+// ageMilestones are the ages (in days) that get a calendar entry.
+var ageMilestones = []int{7, 30, 50, 100, 200, 365, 500, 1000}
+
+// upcomingAges caps how many future age milestones are listed at once.
+const upcomingAges = 3
+
+// Calendar serves an iCalendar feed of the pet's upcoming milestones, its
+// hatch-day anniversary, the next care-streak milestone, and the owners'
+// scheduled tasks. book may be nil.
+func Calendar(state *pet.PetState, book *schedule.Book) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		var tasks []schedule.Task
+		if book != nil {
+			tasks = book.Tasks()
+		}
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Write([]byte(renderCalendar(state.Snapshot(), tasks, time.Now())))
+	})
+}
+
+func renderCalendar(snap pet.Snapshot, tasks []schedule.Task, now time.Time) string {
+	c := &ics{stamp: now.UTC().Format("20060102T150405Z")}
+	c.line("BEGIN:VCALENDAR")
+	c.line("VERSION:2.0")
+	c.line("PRODID:-//pipet//pet calendar//EN")
+	c.line("CALSCALE:GREGORIAN")
+	c.line("X-WR-CALNAME:" + escape(calendarName(snap)))
+
+	if snap.Name != "" && snap.IsAlive && !snap.BornAt.IsZero() {
+		born := snap.BornAt.In(now.Location())
+		id := born.Format("20060102150405") // keeps a new pet's events apart from the last one's
+
+		c.allDay("hatchday-"+id, born, fmt.Sprintf("%s's hatch day", snap.Name), "RRULE:FREQ=YEARLY")
+
+		listed := 0
+		for _, days := range ageMilestones {
+			day := born.AddDate(0, 0, days)
+			if day.Before(now) || listed == upcomingAges {
+				continue
+			}
+			c.allDay(fmt.Sprintf("age-%d-%s", days, id), day, fmt.Sprintf("%s turns %d days old", snap.Name, days), "")
+			listed++
+		}
+
+		if next, ok := nextStreakMilestone(snap.Streak); ok {
+			day := now.AddDate(0, 0, next-snap.Streak)
+			c.allDay(fmt.Sprintf("streak-%d-%s", next, id), day,
+				fmt.Sprintf("%s's %d-day care streak (if nobody misses a day)", snap.Name, next), "")
+		}
+	}
+
+	for _, t := range tasks {
+		c.task(t, now)
+	}
+
+	c.line("END:VCALENDAR")
+	return c.b.String()
+}
+
+func calendarName(snap pet.Snapshot) string {
+	if snap.Name == "" {
+		return "pipet"
+	}
+	return snap.Name + " (pipet)"
+}
+
+// nextStreakMilestone returns the next streak length with a bonus, if the
+// streak is going at all.
+func nextStreakMilestone(streak int) (int, bool) {
+	if streak == 0 {
+		return 0, false
+	}
+	var keys []int
+	for k := range pet.StreakMilestones {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	for _, k := range keys {
+		if k > streak {
+			return k, true
+		}
+	}
+	return 0, false
+}
+
+// ics builds an iCalendar document with CRLF line endings and folding.
+type ics struct {
+	b     strings.Builder
+	stamp string
+}
+
+// line writes one content line, folded at 75 octets as RFC 5545 requires.
+func (c *ics) line(s string) {
+	for len(s) > 75 {
+		cut := 75
+		for cut > 0 && !utf8Start(s[cut]) {
+			cut-- // don't split a multi-byte character
+		}
+		c.b.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+	}
+	c.b.WriteString(s + "\r\n")
+}
+
+func utf8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+func (c *ics) allDay(uid string, day time.Time, summary, rrule string) {
+	c.line("BEGIN:VEVENT")
+	c.line("UID:" + uid + "@pipet")
+	c.line("DTSTAMP:" + c.stamp)
+	c.line("DTSTART;VALUE=DATE:" + day.Format("20060102"))
+	c.line("DTEND;VALUE=DATE:" + day.AddDate(0, 0, 1).Format("20060102"))
+	c.line("SUMMARY:" + escape(summary))
+	if rrule != "" {
+		c.line(rrule)
+	}
+	c.line("TRANSP:TRANSPARENT")
+	c.line("END:VEVENT")
+}
+
+// task writes a scheduled task as a recurring 15-minute event in the Pi's
+// local time (floating, so it shows at the same clock time for the reader).
+func (c *ics) task(t schedule.Task, now time.Time) {
+	start := time.Date(now.Year(), now.Month(), now.Day(), t.Hour, t.Minute, 0, 0, now.Location())
+	rrule := "RRULE:FREQ=DAILY"
+	if len(t.Days) > 0 {
+		days := make([]string, len(t.Days))
+		for i, d := range t.Days {
+			days[i] = strings.ToUpper(d.String()[:2])
+		}
+		rrule = "RRULE:FREQ=WEEKLY;BYDAY=" + strings.Join(days, ",")
+	}
+
+	c.line("BEGIN:VEVENT")
+	c.line("UID:task-" + t.ID + "@pipet")
+	c.line("DTSTAMP:" + c.stamp)
+	c.line("DTSTART:" + start.Format("20060102T150405"))
+	c.line("DURATION:PT15M")
+	c.line(rrule)
+	c.line("SUMMARY:" + escape("pipet: "+t.Description))
+	c.line("DESCRIPTION:" + escape("Scheduled check, runs: "+t.Command))
+	c.line("END:VEVENT")
+}
+
+// escape quotes TEXT values per RFC 5545.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,23 @@
+// Package feed serves the pet's life as feeds other apps can subscribe to.
+package feed
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// Protect requires ?token=<token> on every request when token is set, so a
+// feed URL can be shared without opening it to the whole network.
+func Protect(token string, h http.Handler) http.Handler {
+	if token == "" {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := r.URL.Query().Get("token")
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
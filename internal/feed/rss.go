@@ -0,0 +1,96 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/moorebrett0/pipet/internal/pet"
+)
+
+// maxItems caps how many entries the RSS feed carries.
+const maxItems = 50
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Description string  `xml:"description"`
+	PubDate     string  `xml:"pubDate"`
+	GUID        rssGUID `xml:"guid"`
+	at          time.Time
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// Diary serves the pet's diary entries and event log as an RSS 2.0 feed,
+// newest first, for people who follow along without Discord.
+func Diary(state *pet.PetState) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		snap := state.Snapshot()
+		name := snap.Name
+		if name == "" {
+			name = "pipet"
+		}
+
+		var items []rssItem
+		for _, d := range state.DiaryEntries() {
+			// Entries are written in the evening; date them at the end of their day
+			day, err := time.ParseInLocation("2006-01-02", d.Date, time.Local)
+			if err != nil {
+				continue
+			}
+			at := day.Add(23*time.Hour + 59*time.Minute)
+			items = append(items, rssItem{
+				Title:       fmt.Sprintf("%s's diary, %s", name, day.Format("Mon Jan 2")),
+				Description: d.Text,
+				GUID:        rssGUID{Value: "diary-" + d.Date},
+				at:          at,
+			})
+		}
+		for _, e := range state.EventsSince(time.Time{}) {
+			items = append(items, rssItem{
+				Title:       fmt.Sprintf("%s: %s", name, e.Text),
+				Description: e.Text,
+				GUID:        rssGUID{Value: fmt.Sprintf("event-%d", e.At.UnixNano())},
+				at:          e.At,
+			})
+		}
+
+		sort.SliceStable(items, func(i, j int) bool { return items[i].at.After(items[j].at) })
+		if len(items) > maxItems {
+			items = items[:maxItems]
+		}
+		for i := range items {
+			items[i].PubDate = items[i].at.Format(time.RFC1123Z)
+		}
+
+		doc := rss{Version: "2.0", Channel: rssChannel{
+			Title:       name + "'s life on the Pi",
+			Link:        "http://" + r.Host + "/",
+			Description: fmt.Sprintf("Diary entries and goings-on from %s, a pipet.", name),
+			Items:       items,
+		}}
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		w.Write([]byte(xml.Header))
+		enc := xml.NewEncoder(w)
+		enc.Indent("", "  ")
+		enc.Encode(doc)
+	})
+}
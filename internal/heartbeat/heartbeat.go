@@ -0,0 +1,86 @@
+package heartbeat
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/moorebrett0/pipet/internal/metrics"
+)
+
+// Pinger reports in to an external uptime monitor, so it notices when the
+// Pi or the pet daemon goes quiet. It speaks two dialects, picked from the
+// URL: Uptime Kuma push monitors (".../api/push/<token>"), which take the
+// status and message as query parameters, and Healthchecks.io-style ping
+// URLs, which take a POST body and a "/fail" suffix.
+type Pinger struct {
+	url    string
+	kuma   bool
+	client *http.Client
+}
+
+// New creates a Pinger for a monitor's ping URL.
+func New(pingURL string) *Pinger {
+	return &Pinger{
+		url:    strings.TrimRight(pingURL, "/"),
+		kuma:   strings.Contains(pingURL, "/api/push/"),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// OK reports a healthy check cycle, with the pet's vitals as the payload.
+func (p *Pinger) OK(ctx context.Context, vitals string) error {
+	return p.ping(ctx, true, vitals)
+}
+
+// Fail reports that something critical is wrong.
+func (p *Pinger) Fail(ctx context.Context, reason string) error {
+	return p.ping(ctx, false, reason)
+}
+
+func (p *Pinger) ping(ctx context.Context, up bool, msg string) (err error) {
+	defer func(start time.Time) { metrics.Since("heartbeat.ping", start, err) }(time.Now())
+
+	var req *http.Request
+	if p.kuma {
+		u, err := url.Parse(p.url)
+		if err != nil {
+			return fmt.Errorf("parsing heartbeat URL: %w", err)
+		}
+		q := u.Query()
+		q.Set("status", "up")
+		if !up {
+			q.Set("status", "down")
+		}
+		q.Set("msg", msg)
+		q.Del("ping")
+		u.RawQuery = q.Encode()
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return fmt.Errorf("building heartbeat: %w", err)
+		}
+	} else {
+		target := p.url
+		if !up {
+			target += "/fail"
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, target, strings.NewReader(msg))
+		if err != nil {
+			return fmt.Errorf("building heartbeat: %w", err)
+		}
+		req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending heartbeat: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("heartbeat rejected: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/moorebrett0/pipet/internal/pet"
+)
+
+// Sample is the pet's stats and the machine's readings at one moment.
+type Sample struct {
+	At time.Time `json:"at"`
+
+	Hunger      float64 `json:"hunger"`
+	Happiness   float64 `json:"happiness"`
+	Energy      float64 `json:"energy"`
+	Cleanliness float64 `json:"cleanliness"`
+	Bond        float64 `json:"bond"`
+
+	CPUPercent  float64 `json:"cpu_percent"`
+	MemPercent  float64 `json:"mem_percent"`
+	DiskPercent float64 `json:"disk_percent"`
+	TempC       float64 `json:"temp_c"`
+
+	// Interactions is the pet's lifetime interaction count, so the
+	// difference between two samples is how often it was looked after.
+	Interactions int `json:"interactions"`
+}
+
+// FromSnapshot takes a sample of snap at t.
+func FromSnapshot(snap pet.Snapshot, t time.Time) Sample {
+	return Sample{
+		At:           t,
+		Hunger:       snap.Hunger,
+		Happiness:    snap.Happiness,
+		Energy:       snap.Energy,
+		Cleanliness:  snap.Cleanliness,
+		Bond:         snap.Bond,
+		CPUPercent:   snap.CPUPercent,
+		MemPercent:   snap.MemPercent,
+		DiskPercent:  snap.DiskPercent,
+		TempC:        snap.TempC,
+		Interactions: snap.Interactions,
+	}
+}
+
+// Buffer keeps samples taken at most every interval, dropping anything
+// older than span.
+type Buffer struct {
+	every time.Duration
+	span  time.Duration
+	path  string // "" keeps it in memory only
+
+	mu      sync.Mutex
+	samples []Sample // oldest first
+}
+
+// New creates a buffer that keeps one sample per every, for span.
+func New(every, span time.Duration) *Buffer {
+	return &Buffer{every: every, span: span}
+}
+
+// Open creates a buffer like New that's saved to path each time it keeps a
+// sample, loading what was saved there before so charts and digests pick
+// up where they left off after a restart. A missing file is an empty
+// history; samples older than span are dropped.
+func Open(path string, every, span time.Duration) (*Buffer, error) {
+	b := &Buffer{every: every, span: span, path: path}
+	if path == "" {
+		return b, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return b, nil
+		}
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+	if err := json.Unmarshal(data, &b.samples); err != nil {
+		return nil, fmt.Errorf("unmarshal history: %w", err)
+	}
+	b.pruneLocked(time.Now())
+	return b, nil
+}
+
+// Record adds s unless the last sample is less than every old. Returns
+// whether it was kept.
+func (b *Buffer) Record(s Sample) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if n := len(b.samples); n > 0 && s.At.Sub(b.samples[n-1].At) < b.every {
+		return false
+	}
+	b.pruneLocked(s.At)
+	b.samples = append(b.samples, s)
+	b.saveLocked()
+	return true
+}
+
+// pruneLocked drops samples more than span before now. Caller must hold
+// b.mu.
+func (b *Buffer) pruneLocked(now time.Time) {
+	cutoff := now.Add(-b.span)
+	drop := 0
+	for drop < len(b.samples) && b.samples[drop].At.Before(cutoff) {
+		drop++
+	}
+	b.samples = b.samples[drop:]
+}
+
+// saveLocked writes the samples atomically. Caller must hold b.mu.
+func (b *Buffer) saveLocked() {
+	if b.path == "" {
+		return
+	}
+	data, err := json.Marshal(b.samples)
+	if err != nil {
+		slog.Error("history: marshal samples", "err", err)
+		return
+	}
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		slog.Error("history: write samples", "err", err)
+		return
+	}
+	if err := os.Rename(tmp, b.path); err != nil {
+		slog.Error("history: rename samples", "err", err)
+	}
+}
+
+// Since returns the samples taken after t, oldest first.
+func (b *Buffer) Since(t time.Time) []Sample {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []Sample
+	for _, s := range b.samples {
+		if s.At.After(t) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Trend is how one reading moved over a run of samples.
+type Trend struct {
+	First, Last float64
+	Min, Max    float64
+	MaxAt       time.Time
+}
+
+// Change is how much the reading rose (or, if negative, fell).
+func (t Trend) Change() float64 {
+	return t.Last - t.First
+}
+
+// TrendOf follows the reading picked by field across samples. It's the
+// zero Trend if there are no samples.
+func TrendOf(samples []Sample, field func(Sample) float64) Trend {
+	if len(samples) == 0 {
+		return Trend{}
+	}
+	v := field(samples[0])
+	t := Trend{First: v, Last: field(samples[len(samples)-1]), Min: v, Max: v, MaxAt: samples[0].At}
+	for _, s := range samples[1:] {
+		v := field(s)
+		t.Min = min(t.Min, v)
+		if v > t.Max {
+			t.Max, t.MaxAt = v, s.At
+		}
+	}
+	return t
+}
@@ -0,0 +1,89 @@
+// Package i18n loads translations of slash command text and message
+// templates from per-locale YAML files. Anything a file leaves out stays in
+// English.
+package i18n
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Locale is one translation file, named after its Discord locale code
+// (de.yaml, es-ES.yaml, pt-BR.yaml, ...).
+type Locale struct {
+	Code string `yaml:"-"`
+
+	// Slash command text by command name
+	Commands map[string]Command `yaml:"commands"`
+
+	// Message templates by key, as fmt format strings taking the same
+	// arguments as the English ones. Use %[n]s to reorder them.
+	Templates map[string]string `yaml:"templates"`
+}
+
+// Command is the translated text for one slash command. Names must follow
+// Discord's rules (lowercase, no spaces); leave Name empty to keep the
+// English one.
+type Command struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Options     map[string]string `yaml:"options"` // option name → description
+}
+
+// LoadDir reads every *.yaml file in dir, keyed by locale code. A missing
+// directory is not an error.
+func LoadDir(dir string) (map[string]*Locale, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("list locales: %w", err)
+	}
+	locales := make(map[string]*Locale, len(files))
+	for _, path := range files {
+		l, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		locales[l.Code] = l
+		slog.Info("i18n: loaded locale", "code", l.Code, "commands", len(l.Commands), "templates", len(l.Templates))
+	}
+	return locales, nil
+}
+
+// LoadFile reads one locale file.
+func LoadFile(path string) (*Locale, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read locale %s: %w", path, err)
+	}
+	var l Locale
+	if err := yaml.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("parse locale %s: %w", path, err)
+	}
+	l.Code = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return &l, nil
+}
+
+// active is the locale templates are written in (nil = English).
+var active atomic.Pointer[Locale]
+
+// Use makes l the locale for templates. Nil goes back to English.
+func Use(l *Locale) {
+	active.Store(l)
+}
+
+// T returns the active locale's template for key, or fallback if it has
+// none.
+func T(key, fallback string) string {
+	if l := active.Load(); l != nil {
+		if s, ok := l.Templates[key]; ok && s != "" {
+			return s
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,97 @@
+package items
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moorebrett0/pipet/internal/species"
+)
+
+// Item is something a pet can own, eat, or be given.
+type Item struct {
+	ID         string
+	Name       string
+	Emoji      string
+	Consumable bool            // used up immediately when received
+	Effects    species.Effects // applied when consumed
+	Cures      bool            // ends an illness; only sold, never given free
+}
+
+// Catalog holds all known items keyed by ID.
+var Catalog = map[string]*Item{
+	"treat": {
+		ID:         "treat",
+		Name:       "Treat",
+		Emoji:      "\U0001F36A",
+		Consumable: true,
+		Effects:    species.Effects{Hunger: -15, Happiness: 5},
+	},
+	"toy": {
+		ID:         "toy",
+		Name:       "Toy",
+		Emoji:      "\U0001F9F8",
+		Consumable: true,
+		Effects:    species.Effects{Happiness: 12, Energy: -3},
+	},
+	"blanket": {
+		ID:         "blanket",
+		Name:       "Blanket",
+		Emoji:      "\U0001F9E3",
+		Consumable: true,
+		Effects:    species.Effects{Energy: 10},
+	},
+	"medicine": {
+		ID:         "medicine",
+		Name:       "Medicine",
+		Emoji:      "\U0001F48A",
+		Consumable: true,
+		Cures:      true,
+		Effects:    species.Effects{Happiness: -2}, // it tastes awful
+	},
+	"pebble": {
+		ID:      "pebble",
+		Name:    "Shiny Pebble",
+		Emoji:   "\U0001FAA8",
+		Effects: species.Effects{Happiness: 3},
+	},
+	"shell": {
+		ID:      "shell",
+		Name:    "Seashell",
+		Emoji:   "\U0001F41A",
+		Effects: species.Effects{Happiness: 3},
+	},
+}
+
+// OrderedIDs defines display order for item lists.
+var OrderedIDs = []string{"treat", "toy", "blanket", "medicine", "pebble", "shell"}
+
+// giftTag marks a pet-to-pet gift line, posted as Discord subtext.
+const giftTag = "-# pipet:gift "
+
+// Gift is an item sent from one pet to another across bots.
+type Gift struct {
+	ItemID string
+	ToID   string // recipient bot's user ID
+	From   string // sending pet's name
+}
+
+// FormatGift renders the machine-readable line for a gift.
+func FormatGift(g Gift) string {
+	return fmt.Sprintf("%s%s %s %s", giftTag, g.ItemID, g.ToID, g.From)
+}
+
+// ParseGift extracts a gift from a message, if it contains one.
+func ParseGift(text string) (Gift, bool) {
+	for _, line := range strings.Split(text, "\n") {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), giftTag)
+		if !ok {
+			continue
+		}
+		fields := strings.SplitN(rest, " ", 3)
+		if len(fields) != 3 || Catalog[fields[0]] == nil {
+			return Gift{}, false
+		}
+		return Gift{ItemID: fields[0], ToID: fields[1], From: fields[2]}, true
+	}
+	return Gift{}, false
+}
@@ -0,0 +1,36 @@
+package items
+
+// Shells are the pet's currency. Owners earn them by looking after the Pi
+// and anyone can spend them in the shop.
+const (
+	CurrencyName  = "shells"
+	CurrencyEmoji = "\U0001F41A"
+)
+
+// FeedEarning is paid to an owner for a /feed at least an hour after the
+// last one.
+const FeedEarning = 1
+
+// UptimeEarnings maps uptime milestones (days) to shells paid to each owner.
+var UptimeEarnings = map[int]int{
+	7:   5,
+	30:  15,
+	100: 50,
+}
+
+// Prices lists what each item costs in the shop. Items without a price
+// aren't for sale.
+var Prices = map[string]int{
+	"treat":    3,
+	"toy":      5,
+	"blanket":  5,
+	"medicine": 15,
+	"pebble":   12,
+	"shell":    12,
+}
+
+// Prices for things that aren't items.
+const (
+	RevivePrice = 25
+	SkinPrice   = 20
+)
@@ -0,0 +1,204 @@
+package knowledge
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxChunk is roughly how many characters of a doc go in one snippet.
+const maxChunk = 800
+
+// maxFileBytes skips anything too big to be a hand-written note.
+const maxFileBytes = 256 << 10
+
+// Snippet is a piece of one doc.
+type Snippet struct {
+	File    string // relative to the docs dir
+	Heading string // nearest markdown heading, if any
+	Text    string
+}
+
+// Base is an index of the owner's notes about their setup, searched by
+// keyword overlap (TF-IDF).
+type Base struct {
+	snippets []Snippet
+	terms    []map[string]int // term counts per snippet
+	df       map[string]int   // snippets containing each term
+	files    []string
+}
+
+// Load indexes the .md and .txt files under dir. A missing dir is an empty
+// base.
+func Load(dir string) (*Base, error) {
+	b := &Base{df: make(map[string]int)}
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == dir {
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".md", ".markdown", ".txt":
+		default:
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > maxFileBytes {
+			slog.Warn("knowledge: skipping large file", "path", path, "bytes", info.Size())
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", path, err)
+		}
+		rel, _ := filepath.Rel(dir, path)
+		b.files = append(b.files, rel)
+		for _, s := range chunk(rel, string(data)) {
+			b.add(s)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("load docs: %w", err)
+	}
+	return b, nil
+}
+
+// Files lists the indexed docs.
+func (b *Base) Files() []string {
+	return b.files
+}
+
+// Len returns the number of snippets indexed.
+func (b *Base) Len() int {
+	return len(b.snippets)
+}
+
+func (b *Base) add(s Snippet) {
+	counts := make(map[string]int)
+	for _, t := range tokenize(s.Heading + " " + s.Text) {
+		counts[t]++
+	}
+	for t := range counts {
+		b.df[t]++
+	}
+	b.snippets = append(b.snippets, s)
+	b.terms = append(b.terms, counts)
+}
+
+// Search returns up to n snippets most relevant to query, best first.
+func (b *Base) Search(query string, n int) []Snippet {
+	q := tokenize(query)
+	if len(q) == 0 || len(b.snippets) == 0 {
+		return nil
+	}
+
+	type hit struct {
+		i     int
+		score float64
+	}
+	var hits []hit
+	total := float64(len(b.snippets))
+	for i, counts := range b.terms {
+		score := 0.0
+		for _, t := range q {
+			if c := counts[t]; c > 0 {
+				idf := math.Log(1 + total/float64(b.df[t]))
+				score += (1 + math.Log(float64(c))) * idf
+			}
+		}
+		if score > 0 {
+			hits = append(hits, hit{i, score})
+		}
+	}
+	sort.Slice(hits, func(a, c int) bool { return hits[a].score > hits[c].score })
+
+	var out []Snippet
+	for _, h := range hits {
+		if len(out) == n {
+			break
+		}
+		out = append(out, b.snippets[h.i])
+	}
+	return out
+}
+
+// chunk splits a doc into snippets at headings and blank lines, merging
+// paragraphs up to maxChunk characters.
+func chunk(file, text string) []Snippet {
+	var out []Snippet
+	heading := ""
+	var cur strings.Builder
+	flush := func() {
+		if t := strings.TrimSpace(cur.String()); t != "" {
+			out = append(out, Snippet{File: file, Heading: heading, Text: t})
+		}
+		cur.Reset()
+	}
+
+	for _, para := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		if strings.HasPrefix(para, "#") {
+			flush()
+			line, rest, _ := strings.Cut(para, "\n")
+			heading = strings.TrimSpace(strings.TrimLeft(line, "#"))
+			para = strings.TrimSpace(rest)
+			if para == "" {
+				continue
+			}
+		}
+		if cur.Len() > 0 && cur.Len()+len(para) > maxChunk {
+			flush()
+		}
+		for len(para) > maxChunk {
+			cur.WriteString(para[:maxChunk])
+			flush()
+			para = para[maxChunk:]
+		}
+		if cur.Len() > 0 {
+			cur.WriteString("\n\n")
+		}
+		cur.WriteString(para)
+	}
+	flush()
+	return out
+}
+
+// stopwords are too common to help find anything.
+var stopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true,
+	"you": true, "your": true, "with": true, "this": true, "that": true, "from": true,
+	"have": true, "has": true, "was": true, "what": true, "why": true, "how": true,
+	"its": true, "it's": true, "can": true, "any": true, "all": true, "don't": true,
+}
+
+func tokenize(s string) []string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '.' && r != '\''
+	})
+	var out []string
+	for _, w := range words {
+		w = strings.Trim(w, ".-_'")
+		if len(w) < 2 || stopwords[w] {
+			continue
+		}
+		out = append(out, w)
+	}
+	return out
+}
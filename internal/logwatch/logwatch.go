@@ -0,0 +1,75 @@
+package logwatch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Runner runs a shell command, e.g. *shell.Executor.
+type Runner interface {
+	Run(ctx context.Context, command string) (string, error)
+}
+
+// maxLines caps how many distinct messages are returned, most frequent first.
+const maxLines = 50
+
+// maxLineLen truncates very long log messages.
+const maxLineLen = 200
+
+// Since returns the distinct journald warnings and errors logged after t,
+// most frequent first, with repeats collapsed into a count suffix.
+func Since(ctx context.Context, r Runner, t time.Time) ([]string, error) {
+	cmd := fmt.Sprintf("journalctl --since '%s' -p warning --no-pager -q -o cat", t.Format("2006-01-02 15:04:05"))
+	out, err := r.Run(ctx, cmd)
+	if err != nil {
+		return nil, fmt.Errorf("reading journal: %w", err)
+	}
+
+	counts := make(map[string]int)
+	var order []string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "...") {
+			continue // blank, or the executor's truncation marker
+		}
+		if len(line) > maxLineLen {
+			line = line[:maxLineLen] + "…"
+		}
+		if counts[line] == 0 {
+			order = append(order, line)
+		}
+		counts[line]++
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+	if len(order) > maxLines {
+		order = order[:maxLines]
+	}
+
+	lines := make([]string, len(order))
+	for i, msg := range order {
+		if n := counts[msg]; n > 1 {
+			msg = fmt.Sprintf("%s (x%d)", msg, n)
+		}
+		lines[i] = msg
+	}
+	return lines, nil
+}
+
+// Tail returns the last n journald lines for a systemd unit.
+func Tail(ctx context.Context, r Runner, unit string, n int) (string, error) {
+	if unit == "" || strings.ContainsAny(unit, "'\"\\ ;|&$`") {
+		return "", fmt.Errorf("invalid unit name %q", unit)
+	}
+	cmd := fmt.Sprintf("journalctl -u '%s' -n %d --no-pager -q -o short-iso", unit, n)
+	out, err := r.Run(ctx, cmd)
+	if err != nil {
+		return out, fmt.Errorf("reading journal for %s: %w", unit, err)
+	}
+	return out, nil
+}
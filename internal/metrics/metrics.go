@@ -0,0 +1,196 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxSamples is how many recent latencies each operation keeps for
+// percentiles.
+const maxSamples = 500
+
+// Stat summarizes one operation.
+type Stat struct {
+	Name     string
+	Count    int64
+	Errors   int64
+	P50, P95 time.Duration
+}
+
+// ErrorRate is the fraction of calls that failed.
+func (s Stat) ErrorRate() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Count)
+}
+
+type series struct {
+	count, errors int64
+	samples       []time.Duration // ring buffer
+	next          int
+}
+
+var (
+	mu  sync.Mutex
+	ops = make(map[string]*series)
+)
+
+// Observe records one call to the named operation, e.g. "cmd.heal",
+// "brain.ask", or "discord.send".
+func Observe(name string, d time.Duration, err error) {
+	mu.Lock()
+	defer mu.Unlock()
+	s := ops[name]
+	if s == nil {
+		s = &series{}
+		ops[name] = s
+	}
+	s.count++
+	if err != nil {
+		s.errors++
+	}
+	if len(s.samples) < maxSamples {
+		s.samples = append(s.samples, d)
+	} else {
+		s.samples[s.next] = d
+		s.next = (s.next + 1) % maxSamples
+	}
+}
+
+// Since records a call that started at start. Handy with defer:
+//
+//	defer func(t time.Time) { metrics.Since("brain.ask", t, err) }(time.Now())
+func Since(name string, start time.Time, err error) {
+	Observe(name, time.Since(start), err)
+}
+
+// Snapshot returns a summary of every operation, sorted by name.
+func Snapshot() []Stat {
+	mu.Lock()
+	defer mu.Unlock()
+	out := make([]Stat, 0, len(ops))
+	for name, s := range ops {
+		sorted := make([]time.Duration, len(s.samples))
+		copy(sorted, s.samples)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		out = append(out, Stat{
+			Name:   name,
+			Count:  s.count,
+			Errors: s.errors,
+			P50:    percentile(sorted, 0.50),
+			P95:    percentile(sorted, 0.95),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[int(p*float64(len(sorted)-1))]
+}
+
+// Check is the health of one component.
+type Check struct {
+	OK     bool      `json:"ok"`
+	Detail string    `json:"detail,omitempty"`
+	Since  time.Time `json:"since"`
+}
+
+var health = make(map[string]Check)
+
+// SetHealth records whether a component (e.g. "discord") is healthy. Since
+// only changes when OK does.
+func SetHealth(name string, ok bool, detail string) {
+	mu.Lock()
+	defer mu.Unlock()
+	c, seen := health[name]
+	if !seen || c.OK != ok {
+		c.Since = time.Now()
+	}
+	c.OK, c.Detail = ok, detail
+	health[name] = c
+}
+
+// HealthHandler serves each component's health as JSON, with status 503 if
+// any is unhealthy.
+func HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		mu.Lock()
+		checks := make(map[string]Check, len(health))
+		status := http.StatusOK
+		for name, c := range health {
+			checks[name] = c
+			if !c.OK {
+				status = http.StatusServiceUnavailable
+			}
+		}
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(checks)
+	})
+}
+
+// Handler serves the metrics in Prometheus text format.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		var b strings.Builder
+		b.WriteString("# TYPE pipet_calls_total counter\n# TYPE pipet_errors_total counter\n# TYPE pipet_latency_seconds summary\n")
+		for _, s := range Snapshot() {
+			fmt.Fprintf(&b, "pipet_calls_total{op=%q} %d\n", s.Name, s.Count)
+			fmt.Fprintf(&b, "pipet_errors_total{op=%q} %d\n", s.Name, s.Errors)
+			fmt.Fprintf(&b, "pipet_latency_seconds{op=%q,quantile=\"0.5\"} %g\n", s.Name, s.P50.Seconds())
+			fmt.Fprintf(&b, "pipet_latency_seconds{op=%q,quantile=\"0.95\"} %g\n", s.Name, s.P95.Seconds())
+		}
+		w.Write([]byte(b.String()))
+	})
+}
+
+// routes are extra pages (feeds and the like) served next to /metrics.
+var routes = make(map[string]http.Handler)
+
+// Handle adds a page to the server started by Serve. Call it before Serve.
+func Handle(pattern string, h http.Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	routes[pattern] = h
+}
+
+// Serve exposes /metrics, /healthz, and any pages added with Handle on addr
+// until ctx is cancelled.
+func Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	mux.Handle("/healthz", HealthHandler())
+	mu.Lock()
+	for pattern, h := range routes {
+		mux.Handle(pattern, h)
+	}
+	mu.Unlock()
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	slog.Info("metrics: serving", "addr", addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("metrics server: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+//go:build !windows
+
+package monitor
+
+import (
+	"log/slog"
+	"syscall"
+)
+
+// --- Disk (syscall.Statfs — works on Linux and macOS) ---
+
+func readDiskPercent() float64 {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs("/", &stat); err != nil {
+		slog.Debug("monitor: statfs failed", "err", err)
+		return 0
+	}
+
+	total := stat.Blocks * uint64(stat.Bsize)
+	free := stat.Bavail * uint64(stat.Bsize)
+	if total == 0 {
+		return 0
+	}
+	return float64(total-free) / float64(total) * 100
+}
+
+func readDiskFreeMB() float64 {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs("/", &stat); err != nil {
+		return 0
+	}
+	return float64(stat.Bavail*uint64(stat.Bsize)) / (1 << 20)
+}
@@ -0,0 +1,51 @@
+package monitor
+
+import (
+	"log/slog"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// --- Disk (Windows: GetDiskFreeSpaceExW on the system drive) ---
+
+var getDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// diskSpace returns the free-to-caller and total bytes on the system drive.
+func diskSpace() (free, total uint64, ok bool) {
+	root := os.Getenv("SystemDrive") + `\`
+	if root == `\` {
+		root = `C:\`
+	}
+	path, err := syscall.UTF16PtrFromString(root)
+	if err != nil {
+		return 0, 0, false
+	}
+	r, _, err := getDiskFreeSpaceEx.Call(
+		uintptr(unsafe.Pointer(path)),
+		uintptr(unsafe.Pointer(&free)),
+		uintptr(unsafe.Pointer(&total)),
+		0,
+	)
+	if r == 0 {
+		slog.Debug("monitor: GetDiskFreeSpaceEx failed", "err", err)
+		return 0, 0, false
+	}
+	return free, total, true
+}
+
+func readDiskPercent() float64 {
+	free, total, ok := diskSpace()
+	if !ok || total == 0 {
+		return 0
+	}
+	return float64(total-free) / float64(total) * 100
+}
+
+func readDiskFreeMB() float64 {
+	free, _, ok := diskSpace()
+	if !ok {
+		return 0
+	}
+	return float64(free) / (1 << 20)
+}
@@ -6,11 +6,12 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
-	"syscall"
 	"time"
 )
 
@@ -19,15 +20,39 @@ type SystemStats struct {
 	CPUPercent  float64
 	MemPercent  float64
 	DiskPercent float64
+	DiskFreeMB  float64
 	TempC       float64
 	UptimeDays  float64
+	Throttled   uint32 // Pi firmware throttle flags (0 = fine or unknown)
 }
 
+// Throttle flag bits reported by the Pi firmware (vcgencmd get_throttled).
+const (
+	ThrottleUnderVoltage = 1 << 0
+	ThrottleFreqCapped   = 1 << 1
+	ThrottleThrottled    = 1 << 2
+	ThrottleSoftTemp     = 1 << 3
+)
+
+// TempSample is one temperature reading kept for the history chart.
+type TempSample struct {
+	At        time.Time
+	TempC     float64
+	Throttled uint32
+}
+
+// historySpan is how much temperature history is kept.
+const historySpan = 24 * time.Hour
+
 // Monitor reads system metrics periodically and stores them atomically.
 type Monitor struct {
 	stats    atomic.Pointer[SystemStats]
 	interval time.Duration
 	onUpdate func(SystemStats) // callback when stats are updated
+	info     SysInfo           // hardware details, read once in New
+
+	histMu  sync.Mutex
+	history []TempSample // oldest first, trimmed to historySpan
 
 	// CPU delta tracking
 	prevIdle  uint64
@@ -39,6 +64,7 @@ func New(interval time.Duration, onUpdate func(SystemStats)) *Monitor {
 	m := &Monitor{
 		interval: interval,
 		onUpdate: onUpdate,
+		info:     readSysInfo(),
 	}
 	m.stats.Store(&SystemStats{})
 	return m
@@ -49,6 +75,11 @@ func (m *Monitor) Stats() SystemStats {
 	return *m.stats.Load()
 }
 
+// Info returns the hardware and OS details gathered at startup.
+func (m *Monitor) Info() SysInfo {
+	return m.info
+}
+
 // Run polls system metrics until the context is cancelled.
 func (m *Monitor) Run(ctx context.Context) {
 	// Immediate first read
@@ -72,15 +103,38 @@ func (m *Monitor) refresh() {
 		CPUPercent:  m.readCPU(),
 		MemPercent:  readMemPercent(),
 		DiskPercent: readDiskPercent(),
+		DiskFreeMB:  readDiskFreeMB(),
 		TempC:       readTemp(),
 		UptimeDays:  readUptime(),
+		Throttled:   readThrottled(),
 	}
 	m.stats.Store(s)
+	m.record(TempSample{At: time.Now(), TempC: s.TempC, Throttled: s.Throttled})
 	if m.onUpdate != nil {
 		m.onUpdate(*s)
 	}
 }
 
+// record appends a sample to the temperature history and drops anything
+// older than historySpan.
+func (m *Monitor) record(s TempSample) {
+	m.histMu.Lock()
+	defer m.histMu.Unlock()
+	cutoff := s.At.Add(-historySpan)
+	drop := 0
+	for drop < len(m.history) && m.history[drop].At.Before(cutoff) {
+		drop++
+	}
+	m.history = append(m.history[drop:], s)
+}
+
+// TempHistory returns the last 24h of temperature readings, oldest first.
+func (m *Monitor) TempHistory() []TempSample {
+	m.histMu.Lock()
+	defer m.histMu.Unlock()
+	return append([]TempSample(nil), m.history...)
+}
+
 // --- CPU (Linux: /proc/stat) ---
 
 func (m *Monitor) readCPU() float64 {
@@ -174,23 +228,6 @@ func parseMeminfoKB(line string) uint64 {
 	return val
 }
 
-// --- Disk (syscall.Statfs — works on Linux and macOS) ---
-
-func readDiskPercent() float64 {
-	var stat syscall.Statfs_t
-	if err := syscall.Statfs("/", &stat); err != nil {
-		slog.Debug("monitor: statfs failed", "err", err)
-		return 0
-	}
-
-	total := stat.Blocks * uint64(stat.Bsize)
-	free := stat.Bavail * uint64(stat.Bsize)
-	if total == 0 {
-		return 0
-	}
-	return float64(total-free) / float64(total) * 100
-}
-
 // --- Temperature (Linux: /sys/class/thermal) ---
 
 func readTemp() float64 {
@@ -212,6 +249,52 @@ func readTemp() float64 {
 	return float64(milliC) / 1000.0
 }
 
+// Sensor is one temperature sensor's current reading.
+type Sensor struct {
+	Name  string
+	TempC float64
+}
+
+// Sensors reads every thermal zone, e.g. the CPU and, on a Pi 5, the RP1.
+func Sensors() []Sensor {
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+
+	zones, _ := filepath.Glob("/sys/class/thermal/thermal_zone*")
+	var out []Sensor
+	for _, z := range zones {
+		data, err := os.ReadFile(filepath.Join(z, "temp"))
+		if err != nil {
+			continue
+		}
+		milliC, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil {
+			continue
+		}
+		name := readTrimmed(filepath.Join(z, "type"))
+		if name == "" {
+			name = filepath.Base(z)
+		}
+		out = append(out, Sensor{Name: name, TempC: float64(milliC) / 1000.0})
+	}
+	return out
+}
+
+// --- Throttling (Pi firmware via sysfs) ---
+
+func readThrottled() uint32 {
+	data, err := os.ReadFile("/sys/devices/platform/soc/soc:firmware/get_throttled")
+	if err != nil {
+		return 0
+	}
+	v, err := strconv.ParseUint(strings.TrimSpace(string(data)), 16, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(v)
+}
+
 // --- Uptime (Linux: /proc/uptime) ---
 
 func readUptime() float64 {
@@ -237,6 +320,76 @@ func readUptime() float64 {
 	return seconds / 86400.0
 }
 
+// --- Boot (Linux: /proc/stat, watchdog bootstatus) ---
+
+// started is when this process came up.
+var started = time.Now()
+
+// ProcessUptime returns how long pipet itself has been running.
+func ProcessUptime() time.Duration {
+	return time.Since(started)
+}
+
+// BootTime returns when the system last booted, or the zero time if unknown.
+func BootTime() time.Time {
+	if runtime.GOOS != "linux" {
+		return time.Time{}
+	}
+
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return time.Time{}
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if v, ok := strings.CutPrefix(scanner.Text(), "btime "); ok {
+			sec, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil {
+				return time.Time{}
+			}
+			return time.Unix(sec, 0)
+		}
+	}
+	return time.Time{}
+}
+
+// Watchdog bootstatus flags (linux/watchdog.h)
+const (
+	wdiofOverheat   = 0x0001
+	wdiofPowerUnder = 0x0010
+	wdiofCardReset  = 0x0020
+)
+
+// RebootReason reports why the system last reset, as far as the hardware
+// watchdog can tell. Returns "" when there's nothing to go on, which
+// usually means a normal reboot or power cycle.
+func RebootReason() string {
+	if runtime.GOOS != "linux" {
+		return ""
+	}
+
+	data, err := os.ReadFile("/sys/class/watchdog/watchdog0/bootstatus")
+	if err != nil {
+		return ""
+	}
+	status, err := strconv.ParseInt(strings.TrimSpace(string(data)), 0, 64)
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case status&wdiofOverheat != 0:
+		return "overheated"
+	case status&wdiofPowerUnder != 0:
+		return "power dipped"
+	case status&wdiofCardReset != 0:
+		return "watchdog reset (it locked up)"
+	}
+	return ""
+}
+
 // FormatStats returns a human-readable stats summary.
 func FormatStats(s SystemStats) string {
 	return fmt.Sprintf("CPU: %.1f%% | Mem: %.1f%% | Disk: %.1f%% | Temp: %.1f°C | Up: %.1fd",
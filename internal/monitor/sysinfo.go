@@ -0,0 +1,172 @@
+package monitor
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// SysInfo describes the hardware and OS. It doesn't change while pipet runs,
+// so it's read once at startup.
+type SysInfo struct {
+	Model      string // e.g. "Raspberry Pi 4 Model B Rev 1.4"
+	SoC        string // e.g. "bcm2711"
+	Cores      int
+	Arch       string
+	MemTotalMB uint64
+	Kernel     string
+	OS         string // PRETTY_NAME from /etc/os-release
+	Storage    string // boot device, e.g. "mmcblk0"
+	StorageGB  float64
+	Interfaces []Interface
+}
+
+// Interface is a network interface that's up, with its addresses.
+type Interface struct {
+	Name  string
+	MAC   string
+	Addrs []string
+}
+
+func readSysInfo() SysInfo {
+	info := SysInfo{
+		Model:  readModel(),
+		SoC:    readSoC(),
+		Cores:  runtime.NumCPU(),
+		Arch:   runtime.GOARCH,
+		Kernel: readTrimmed("/proc/sys/kernel/osrelease"),
+		OS:     readOSRelease(),
+	}
+	info.MemTotalMB = readMemTotalKB() / 1024
+	info.Storage, info.StorageGB = readStorage()
+	info.Interfaces = readInterfaces()
+	return info
+}
+
+// --- Board (Linux: device tree, falling back to DMI and /proc/cpuinfo) ---
+
+func readModel() string {
+	if m := readTrimmed("/proc/device-tree/model"); m != "" {
+		return m
+	}
+	return readTrimmed("/sys/class/dmi/id/product_name")
+}
+
+func readSoC() string {
+	// The last "vendor,chip" entry names the SoC: "raspberrypi,4-model-b\0brcm,bcm2711\0"
+	if data, err := os.ReadFile("/proc/device-tree/compatible"); err == nil {
+		parts := strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+		last := parts[len(parts)-1]
+		if _, chip, ok := strings.Cut(last, ","); ok {
+			return chip
+		}
+	}
+
+	f, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, val, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Hardware", "model name":
+			return strings.TrimSpace(val)
+		}
+	}
+	return ""
+}
+
+// --- OS (Linux: /etc/os-release) ---
+
+func readOSRelease() string {
+	f, err := os.Open("/etc/os-release")
+	if err != nil {
+		return runtime.GOOS
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if v, ok := strings.CutPrefix(scanner.Text(), "PRETTY_NAME="); ok {
+			return strings.Trim(v, `"`)
+		}
+	}
+	return runtime.GOOS
+}
+
+func readMemTotalKB() uint64 {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "MemTotal:") {
+			return parseMeminfoKB(line)
+		}
+	}
+	return 0
+}
+
+// --- Storage (Linux: /sys/block) ---
+
+// readStorage returns the first boot-style block device found (SD card,
+// then NVMe, then USB/SATA) and its size in GB.
+func readStorage() (string, float64) {
+	for _, dev := range []string{"mmcblk0", "nvme0n1", "sda"} {
+		data, err := os.ReadFile("/sys/block/" + dev + "/size")
+		if err != nil {
+			continue
+		}
+		sectors, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil {
+			continue
+		}
+		return dev, float64(sectors*512) / 1e9
+	}
+	return "", 0
+}
+
+// --- Network ---
+
+func readInterfaces() []Interface {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+
+	var out []Interface
+	for _, ifc := range ifaces {
+		if ifc.Flags&net.FlagUp == 0 || ifc.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		i := Interface{Name: ifc.Name, MAC: ifc.HardwareAddr.String()}
+		addrs, _ := ifc.Addrs()
+		for _, a := range addrs {
+			i.Addrs = append(i.Addrs, a.String())
+		}
+		out = append(out, i)
+	}
+	return out
+}
+
+// readTrimmed reads a small text file, dropping whitespace and the NUL that
+// device tree strings end with.
+func readTrimmed(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(string(data), "\x00"))
+}
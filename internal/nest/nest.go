@@ -0,0 +1,267 @@
+package nest
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Runner runs a shell command, e.g. *shell.Executor. It's used for rclone
+// remotes; local destinations are written directly.
+type Runner interface {
+	Run(ctx context.Context, command string) (string, error)
+}
+
+// Nest is one backup archive at the destination.
+type Nest struct {
+	Name string
+	At   time.Time
+	Size int64
+}
+
+// nameLayout timestamps archive names, so they sort oldest first.
+const nameLayout = "20060102-150405"
+
+// Nester archives a fixed set of paths into timestamped tarballs at a
+// destination, keeping the newest few.
+type Nester struct {
+	paths  []string
+	dest   string // a directory, e.g. a USB drive mount, or an rclone "remote:path"
+	keep   int
+	runner Runner
+}
+
+// New creates a Nester. dest is a local directory or an rclone remote such
+// as "gdrive:pipet"; r is only needed for remotes.
+func New(paths []string, dest string, keep int, r Runner) *Nester {
+	return &Nester{paths: paths, dest: dest, keep: keep, runner: r}
+}
+
+// Dest returns where nests are kept.
+func (n *Nester) Dest() string {
+	return n.dest
+}
+
+// remote reports whether dest is an rclone remote rather than a directory.
+func (n *Nester) remote() bool {
+	return !filepath.IsAbs(n.dest) && strings.Contains(n.dest, ":")
+}
+
+// Build archives the configured paths into a new nest, then prunes old
+// ones. Paths that couldn't be read are skipped and returned.
+func (n *Nester) Build(ctx context.Context, now time.Time) (Nest, []string, error) {
+	tmp, err := os.CreateTemp("", "pipet-nest-*.tar.gz")
+	if err != nil {
+		return Nest{}, nil, fmt.Errorf("creating archive: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	skipped, err := n.write(tmp)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return Nest{}, skipped, fmt.Errorf("writing archive: %w", err)
+	}
+	info, err := os.Stat(tmp.Name())
+	if err != nil {
+		return Nest{}, skipped, fmt.Errorf("writing archive: %w", err)
+	}
+
+	nest := Nest{Name: "nest-" + now.Format(nameLayout) + ".tar.gz", At: now, Size: info.Size()}
+	if err := n.store(ctx, tmp.Name(), nest.Name); err != nil {
+		return Nest{}, skipped, err
+	}
+	if err := n.prune(ctx); err != nil {
+		return nest, skipped, fmt.Errorf("pruning old nests: %w", err)
+	}
+	return nest, skipped, nil
+}
+
+// write tars and gzips every configured path into w, with names relative to
+// / so an archive restores with `tar -xzf <nest> -C /`.
+func (n *Nester) write(w io.Writer) ([]string, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	var skipped []string
+	for _, root := range n.paths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				skipped = append(skipped, path)
+				if d != nil && d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if err := addFile(tw, path, d); err != nil {
+				if errors.Is(err, fs.ErrPermission) || errors.Is(err, fs.ErrNotExist) {
+					skipped = append(skipped, path)
+					return nil
+				}
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return skipped, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return skipped, err
+	}
+	return skipped, gz.Close()
+}
+
+// addFile writes one directory, symlink, or regular file to tw. Anything
+// else (sockets, devices) is left out.
+func addFile(tw *tar.Writer, path string, d fs.DirEntry) error {
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+	var link string
+	switch {
+	case info.Mode()&fs.ModeSymlink != 0:
+		if link, err = os.Readlink(path); err != nil {
+			return err
+		}
+	case !info.Mode().IsRegular() && !info.IsDir():
+		return nil
+	}
+
+	hdr, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	hdr.Name = strings.TrimPrefix(filepath.ToSlash(path), "/")
+	if info.IsDir() {
+		hdr.Name += "/"
+	}
+	if !info.Mode().IsRegular() {
+		return tw.WriteHeader(hdr)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// store moves the finished archive to the destination.
+func (n *Nester) store(ctx context.Context, src, name string) error {
+	if n.remote() {
+		dst := strings.TrimRight(n.dest, "/") + "/" + name
+		if _, err := n.runner.Run(ctx, fmt.Sprintf("rclone copyto %s %s", quote(src), quote(dst))); err != nil {
+			return fmt.Errorf("uploading nest: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(n.dest, 0o700); err != nil {
+		return fmt.Errorf("creating nest directory: %w", err)
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("copying nest: %w", err)
+	}
+	defer in.Close()
+	out, err := os.OpenFile(filepath.Join(n.dest, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("copying nest: %w", err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("copying nest: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("copying nest: %w", err)
+	}
+	return nil
+}
+
+// List returns the nests at the destination, newest first.
+func (n *Nester) List(ctx context.Context) ([]Nest, error) {
+	var nests []Nest
+	add := func(name string, size int64) {
+		ts, ok := strings.CutPrefix(strings.TrimSuffix(name, ".tar.gz"), "nest-")
+		if !ok {
+			return
+		}
+		at, err := time.ParseInLocation(nameLayout, ts, time.Local)
+		if err != nil {
+			return
+		}
+		nests = append(nests, Nest{Name: name, At: at, Size: size})
+	}
+
+	if n.remote() {
+		out, err := n.runner.Run(ctx, fmt.Sprintf("rclone lsf --files-only --format sp %s", quote(n.dest)))
+		if err != nil {
+			return nil, fmt.Errorf("listing nests: %w", err)
+		}
+		for _, line := range strings.Split(out, "\n") {
+			size, name, ok := strings.Cut(strings.TrimSpace(line), ";")
+			if !ok {
+				continue
+			}
+			s, _ := strconv.ParseInt(size, 10, 64)
+			add(name, s)
+		}
+	} else {
+		entries, err := os.ReadDir(n.dest)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("listing nests: %w", err)
+		}
+		for _, e := range entries {
+			if info, err := e.Info(); err == nil && info.Mode().IsRegular() {
+				add(e.Name(), info.Size())
+			}
+		}
+	}
+
+	sort.Slice(nests, func(i, j int) bool { return nests[i].At.After(nests[j].At) })
+	return nests, nil
+}
+
+// prune deletes all but the newest keep nests.
+func (n *Nester) prune(ctx context.Context) error {
+	nests, err := n.List(ctx)
+	if err != nil || len(nests) <= n.keep {
+		return err
+	}
+	for _, old := range nests[n.keep:] {
+		if n.remote() {
+			dst := strings.TrimRight(n.dest, "/") + "/" + old.Name
+			_, err = n.runner.Run(ctx, "rclone deletefile "+quote(dst))
+		} else {
+			err = os.Remove(filepath.Join(n.dest, old.Name))
+		}
+		if err != nil {
+			return fmt.Errorf("deleting %s: %w", old.Name, err)
+		}
+	}
+	return nil
+}
+
+// quote single-quotes s for sh.
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
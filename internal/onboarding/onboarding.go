@@ -2,6 +2,7 @@ package onboarding
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"strconv"
@@ -12,8 +13,14 @@ import (
 	"github.com/moorebrett0/pipet/internal/species"
 )
 
+// NameSuggester proposes names for a freshly hatched pet (implemented by the brain).
+type NameSuggester interface {
+	SuggestNames(ctx context.Context, speciesID string) ([]string, error)
+}
+
 // Run performs interactive terminal onboarding. Returns true if onboarding completed.
-func Run(petState *pet.PetState) bool {
+// suggester may be nil if no AI provider is configured.
+func Run(petState *pet.PetState, suggester NameSuggester) bool {
 	if petState.IsOnboarded() {
 		return false
 	}
@@ -44,33 +51,27 @@ func Run(petState *pet.PetState) bool {
 		}
 	}
 
-	// Species selection
+	// Species selection with a preview/confirm step
 	fmt.Println()
 	var selectedID string
 	for {
 		fmt.Print("  > ")
 		input, _ := reader.ReadString('\n')
-		input = strings.TrimSpace(input)
-
-		// Try as number first
-		if num, err := strconv.Atoi(input); err == nil && num >= 1 && num <= len(species.OrderedIDs) {
-			selectedID = species.OrderedIDs[num-1]
-			break
+		id := parseSpecies(strings.TrimSpace(input))
+		if id == "" {
+			fmt.Printf("  hmm, pick a number 1-%d or type the species name\n", len(species.OrderedIDs))
+			continue
 		}
 
-		// Try as name
-		lower := strings.ToLower(input)
-		for _, id := range species.OrderedIDs {
-			if id == lower {
-				selectedID = id
-				break
-			}
-		}
-		if selectedID != "" {
+		printPreview(species.Registry[id])
+		fmt.Print("  hatch this one? (y/n) ")
+		answer, _ := reader.ReadString('\n')
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(answer)), "y") {
+			selectedID = id
 			break
 		}
-
-		fmt.Println("  hmm, pick a number 1-8 or type the species name")
+		fmt.Println()
+		fmt.Println("  ok, pick another:")
 	}
 
 	sp := species.Registry[selectedID]
@@ -81,14 +82,26 @@ func Run(petState *pet.PetState) bool {
 
 	// Name selection
 	fmt.Println("  what's my name?")
+	if suggester != nil {
+		fmt.Println("  (type ? for suggestions)")
+	}
 	fmt.Println()
 
 	var name string
+	var suggestions []string
 	for {
 		fmt.Print("  > ")
 		input, _ := reader.ReadString('\n')
 		name = strings.TrimSpace(input)
 
+		if name == "?" && suggester != nil {
+			suggestions = suggestNames(suggester, selectedID)
+			continue
+		}
+		if num, err := strconv.Atoi(name); err == nil && num >= 1 && num <= len(suggestions) {
+			name = suggestions[num-1]
+		}
+
 		if name != "" && len(name) <= 32 {
 			break
 		}
@@ -100,6 +113,11 @@ func Run(petState *pet.PetState) bool {
 
 	// Hatching reveal
 	fmt.Println()
+	if petState.Snapshot().Shiny {
+		sp = sp.AsShiny()
+		printSlow("  \u2728 ... wait. the shell is sparkling.", 80)
+		fmt.Println()
+	}
 	fmt.Printf("  %s %s\n", sp.Emoji, sp.Verbs.Greet)
 	fmt.Println()
 	printSlow(fmt.Sprintf("  hi. i'm %s.", name), 50)
@@ -109,6 +127,128 @@ func Run(petState *pet.PetState) bool {
 	return true
 }
 
+// suggestNames asks the suggester for names and prints them as a numbered list.
+func suggestNames(suggester NameSuggester, speciesID string) []string {
+	fmt.Println("  thinking...")
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	names, err := suggester.SuggestNames(ctx, speciesID)
+	if err != nil {
+		fmt.Println("  couldn't think of any. you pick.")
+		return nil
+	}
+
+	fmt.Println()
+	for i, n := range names {
+		fmt.Printf("  %d) %s\n", i+1, n)
+	}
+	fmt.Println()
+	fmt.Println("  pick a number, type your own, or ? to reroll")
+	return names
+}
+
+// parseSpecies resolves a menu number or species name to a species ID.
+func parseSpecies(input string) string {
+	if num, err := strconv.Atoi(input); err == nil && num >= 1 && num <= len(species.OrderedIDs) {
+		return species.OrderedIDs[num-1]
+	}
+	lower := strings.ToLower(input)
+	for _, id := range species.OrderedIDs {
+		if id == lower {
+			return id
+		}
+	}
+	return ""
+}
+
+// printPreview shows a species' description, personality, and a sample behavior.
+func printPreview(sp *species.Species) {
+	fmt.Println()
+	fmt.Printf("  %s %s — %s\n", sp.Emoji, sp.Name, strings.ToLower(sp.Description))
+	fmt.Printf("  %s\n", sp.PersonalitySummary())
+	if sample := sp.SampleIdleBehavior(); sample != "" {
+		fmt.Printf("  sometimes it %s.\n", sample)
+	}
+	fmt.Println()
+}
+
+// Reset runs the terminal `pipet reset` flow: asks the user to type the pet's
+// name, archives it to the memorial record, and clears the state so Run can
+// hatch a fresh egg. Returns true if the pet was reset.
+func Reset(petState *pet.PetState, memorialPath string) (bool, error) {
+	if !petState.IsOnboarded() {
+		fmt.Println("  there's no pet to reset. just an egg.")
+		return false, nil
+	}
+
+	snap := petState.Snapshot()
+	sp := species.Registry[snap.SpeciesID]
+	emoji := "\U0001F95A"
+	if sp != nil {
+		emoji = sp.Emoji
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Println()
+	fmt.Printf("  %s %s is %.1f days old.\n", emoji, snap.Name, snap.AgeDays)
+	fmt.Println("  resetting archives them to the memorial and starts over.")
+	fmt.Printf("  type %q to confirm: ", snap.Name)
+
+	input, _ := reader.ReadString('\n')
+	if !strings.EqualFold(strings.TrimSpace(input), snap.Name) {
+		fmt.Println("  never mind then.")
+		return false, nil
+	}
+
+	m := petState.Reset("reset")
+	if err := pet.AppendMemorial(memorialPath, m); err != nil {
+		return true, err
+	}
+
+	fmt.Println()
+	printSlow(fmt.Sprintf("  goodbye, %s.", m.Name), 60)
+	fmt.Println()
+	return true, nil
+}
+
+// Adopt imports a pet exported from another Pi (the `pipet adopt <file>` flow).
+// It refuses to overwrite an existing pet; reset first.
+func Adopt(petState *pet.PetState, exportPath, memorialPath string) error {
+	if petState.IsOnboarded() {
+		return fmt.Errorf("a pet already lives here — run `pipet reset` first")
+	}
+
+	f, err := os.Open(exportPath)
+	if err != nil {
+		return fmt.Errorf("open export: %w", err)
+	}
+	defer f.Close()
+
+	export, err := pet.ReadExport(f)
+	if err != nil {
+		return err
+	}
+	sp := species.Registry[export.Pet.SpeciesID]
+	if sp == nil {
+		return fmt.Errorf("unknown species %q", export.Pet.SpeciesID)
+	}
+
+	petState.Adopt(export)
+	if len(export.Memorials) > 0 {
+		if err := pet.AppendMemorial(memorialPath, export.Memorials...); err != nil {
+			return err
+		}
+	}
+
+	snap := petState.Snapshot()
+	fmt.Println()
+	fmt.Printf("  \U0001F4E6 %s %s\n", sp.Emoji, sp.Verbs.Greet)
+	printSlow(fmt.Sprintf("  %s here. %.1f days old. new house, same me.", snap.Name, snap.AgeDays), 50)
+	fmt.Println()
+	return nil
+}
+
 // PrintStartup prints the startup checklist after onboarding.
 func PrintStartup(name string, aiEnabled, discordConnected bool) {
 	fmt.Println("  starting up...")
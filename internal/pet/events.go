@@ -0,0 +1,89 @@
+package pet
+
+import "time"
+
+// Event is a notable thing that happened to the pet, kept so the brain can
+// reflect on the day.
+type Event struct {
+	At   time.Time `json:"at"`
+	Text string    `json:"text"`
+}
+
+// maxEvents caps the event log; older events fall off the front.
+const maxEvents = 100
+
+// DiaryEntry is one day's journal entry.
+type DiaryEntry struct {
+	Date string `json:"date"` // YYYY-MM-DD, local time
+	Text string `json:"text"`
+}
+
+// maxDiary caps how many diary entries are kept.
+const maxDiary = 30
+
+// LogEvent records something that happened to the pet.
+func (s *PetState) LogEvent(text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	s.logLocked(text)
+}
+
+// logLocked appends to the event log. Caller must hold s.mu.
+func (s *PetState) logLocked(text string) {
+	s.Events = append(s.Events, Event{At: time.Now(), Text: text})
+	if len(s.Events) > maxEvents {
+		s.Events = s.Events[len(s.Events)-maxEvents:]
+	}
+}
+
+// EventsSince returns the events logged after t, oldest first.
+func (s *PetState) EventsSince(t time.Time) []Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var out []Event
+	for _, e := range s.Events {
+		if e.At.After(t) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// AddDiaryEntry saves a diary entry, replacing any existing one for the
+// same day.
+func (s *PetState) AddDiaryEntry(e DiaryEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	if n := len(s.Diary); n > 0 && s.Diary[n-1].Date == e.Date {
+		s.Diary[n-1] = e
+		return
+	}
+	s.Diary = append(s.Diary, e)
+	if len(s.Diary) > maxDiary {
+		s.Diary = s.Diary[len(s.Diary)-maxDiary:]
+	}
+}
+
+// DiaryEntries returns the saved diary, oldest first.
+func (s *PetState) DiaryEntries() []DiaryEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]DiaryEntry(nil), s.Diary...)
+}
+
+// SetDiaryThread remembers the thread diary entries are posted to.
+func (s *PetState) SetDiaryThread(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	s.DiaryThreadID = id
+}
+
+// DiaryThread returns the diary thread ID, or "" if there isn't one yet.
+func (s *PetState) DiaryThread() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.DiaryThreadID
+}
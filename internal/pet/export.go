@@ -0,0 +1,189 @@
+package pet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// ExportVersion is the current export format version.
+const ExportVersion = 1
+
+// Export is a portable bundle of a pet and its memorials, used to move a pet
+// between Pis.
+type Export struct {
+	Version    int        `json:"version"`
+	ExportedAt time.Time  `json:"exported_at"`
+	Pet        *PetState  `json:"pet"`
+	Memorials  []Memorial `json:"memorials,omitempty"`
+}
+
+// NewExport bundles the current state and memorials for export.
+func NewExport(s *PetState, memorials []Memorial) *Export {
+	s.mu.RLock()
+	cp := s.copyLocked()
+	s.mu.RUnlock()
+	return &Export{
+		Version:    ExportVersion,
+		ExportedAt: time.Now(),
+		Pet:        cp,
+		Memorials:  memorials,
+	}
+}
+
+// Marshal encodes the export as indented JSON.
+func (e *Export) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal export: %w", err)
+	}
+	return data, nil
+}
+
+// ReadExport decodes, migrates, and validates an export. A bare state.json
+// (no envelope) is accepted as version 0.
+func ReadExport(r io.Reader) (*Export, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read export: %w", err)
+	}
+
+	var probe struct {
+		Version int             `json:"version"`
+		Pet     json.RawMessage `json:"pet"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("unmarshal export: %w", err)
+	}
+
+	var e Export
+	switch {
+	case probe.Pet == nil:
+		// Version 0: raw state.json copied from another Pi
+		var state PetState
+		if err := json.Unmarshal(data, &state); err != nil {
+			return nil, fmt.Errorf("unmarshal state: %w", err)
+		}
+		e = Export{Pet: &state}
+	case probe.Version <= ExportVersion:
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, fmt.Errorf("unmarshal export: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("export version %d is newer than this pipet supports (%d)", probe.Version, ExportVersion)
+	}
+
+	e.Version = ExportVersion
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (e *Export) validate() error {
+	p := e.Pet
+	if p == nil || p.Name == "" || p.SpeciesID == "" {
+		return fmt.Errorf("export has no pet in it")
+	}
+	if len(p.Name) > 32 {
+		return fmt.Errorf("pet name is too long (%d characters)", len(p.Name))
+	}
+	if p.BornAt.IsZero() || p.BornAt.After(time.Now()) {
+		return fmt.Errorf("pet has an invalid birth date: %s", p.BornAt)
+	}
+
+	p.Hunger = clamp(p.Hunger)
+	p.Happiness = clamp(p.Happiness)
+	p.Energy = clamp(p.Energy)
+	p.Cleanliness = clamp(p.Cleanliness)
+	p.Bond = clamp(p.Bond)
+	if p.LastInteraction.IsZero() {
+		p.LastInteraction = time.Now()
+	}
+	if p.LastFed.IsZero() {
+		p.LastFed = p.LastInteraction
+	}
+	return nil
+}
+
+// Adopt replaces this pet with the one from an export, preserving its age,
+// bond, and stats. System stats are left alone; the monitor refreshes them.
+func (s *PetState) Adopt(e *Export) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+
+	p := e.Pet
+	s.Name = p.Name
+	s.SpeciesID = p.SpeciesID
+	s.Shiny = p.Shiny
+	s.Form = p.Form
+	s.Stage = p.Stage
+	s.Hunger = p.Hunger
+	s.Happiness = p.Happiness
+	s.Energy = p.Energy
+	s.Cleanliness = p.Cleanliness
+	s.Bond = p.Bond
+	s.BornAt = p.BornAt
+	s.LastInteraction = time.Now()
+	s.LastFed = p.LastFed
+	s.IsAlive = p.IsAlive
+	s.Streak = p.Streak
+	s.BestStreak = p.BestStreak
+	s.LastCareDay = p.LastCareDay
+	s.Inventory = copyCounts(p.Inventory)
+	s.Quest = nil   // quests are about the old Pi's hardware
+	s.Illness = nil // so are illnesses
+	s.Skin = p.Skin
+	s.Wallets = copyCounts(p.Wallets)
+	s.Caretakers = copyCaretakers(p.Caretakers)
+	s.Contributions = copyContributions(p.Contributions)
+	s.Prefs = copyPrefs(p.Prefs)
+	s.Facts = slices.Clone(p.Facts)
+	s.Events = slices.Clone(p.Events)
+	s.Diary = slices.Clone(p.Diary)
+	s.logLocked("moved to a new Pi")
+}
+
+// copyLocked returns a copy of the persisted fields. Caller must hold s.mu.
+func (s *PetState) copyLocked() *PetState {
+	return &PetState{
+		Name:            s.Name,
+		SpeciesID:       s.SpeciesID,
+		Shiny:           s.Shiny,
+		Form:            s.Form,
+		Stage:           s.Stage,
+		Hunger:          s.Hunger,
+		Happiness:       s.Happiness,
+		Energy:          s.Energy,
+		Cleanliness:     s.Cleanliness,
+		Bond:            s.Bond,
+		BornAt:          s.BornAt,
+		LastInteraction: s.LastInteraction,
+		LastFed:         s.LastFed,
+		IsAlive:         s.IsAlive,
+		Interactions:    s.Interactions,
+		Streak:          s.Streak,
+		BestStreak:      s.BestStreak,
+		LastCareDay:     s.LastCareDay,
+		Inventory:       copyCounts(s.Inventory),
+		Quest:           copyQuest(s.Quest),
+		Illness:         copyIllness(s.Illness),
+		Skin:            s.Skin,
+		Wallets:         copyCounts(s.Wallets),
+		Owners:          slices.Clone(s.Owners),
+		Caretakers:      copyCaretakers(s.Caretakers),
+		Contributions:   copyContributions(s.Contributions),
+		Prefs:           copyPrefs(s.Prefs),
+		Facts:           slices.Clone(s.Facts),
+		Events:          slices.Clone(s.Events),
+		Diary:           slices.Clone(s.Diary),
+		CPUPercent:      s.CPUPercent,
+		MemPercent:      s.MemPercent,
+		DiskPercent:     s.DiskPercent,
+		TempC:           s.TempC,
+		UptimeDays:      s.UptimeDays,
+	}
+}
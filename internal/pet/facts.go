@@ -0,0 +1,73 @@
+package pet
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Fact is something the pet has learned and keeps for good, like the
+// owner's name or a chore it helped with.
+type Fact struct {
+	ID   int       `json:"id"`
+	Text string    `json:"text"`
+	By   string    `json:"by,omitempty"` // Discord user ID that taught it
+	At   time.Time `json:"at"`
+}
+
+// Long-term memory limits. Past maxFacts, the oldest fact is forgotten.
+const (
+	maxFacts   = 50
+	maxFactLen = 200
+)
+
+// Remember records a fact. Remembering something already known (ignoring
+// case) returns the existing fact instead of adding a copy.
+func (s *PetState) Remember(text, by string) (Fact, error) {
+	text = strings.TrimSpace(text)
+	switch {
+	case text == "":
+		return Fact{}, fmt.Errorf("nothing to remember")
+	case len(text) > maxFactLen:
+		return Fact{}, fmt.Errorf("too long to remember (max %d characters)", maxFactLen)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i := slices.IndexFunc(s.Facts, func(f Fact) bool { return strings.EqualFold(f.Text, text) }); i >= 0 {
+		return s.Facts[i], nil
+	}
+
+	s.dirty = true
+	f := Fact{ID: 1, Text: text, By: by, At: time.Now()}
+	if n := len(s.Facts); n > 0 {
+		f.ID = s.Facts[n-1].ID + 1
+	}
+	s.Facts = append(s.Facts, f)
+	if len(s.Facts) > maxFacts {
+		s.Facts = s.Facts[len(s.Facts)-maxFacts:]
+	}
+	return f, nil
+}
+
+// Forget removes a fact by ID. Returns false if there's no such fact.
+func (s *PetState) Forget(id int) (Fact, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := slices.IndexFunc(s.Facts, func(f Fact) bool { return f.ID == id })
+	if i < 0 {
+		return Fact{}, false
+	}
+	s.dirty = true
+	f := s.Facts[i]
+	s.Facts = slices.Delete(s.Facts, i, i+1)
+	return f, true
+}
+
+// KnownFacts returns everything the pet remembers, oldest first.
+func (s *PetState) KnownFacts() []Fact {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.Facts)
+}
@@ -0,0 +1,195 @@
+package pet
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/moorebrett0/pipet/internal/species"
+)
+
+// What strains the Pi enough to make the pet ill.
+const (
+	CauseMemory = "memory"
+	CauseHeat   = "heat"
+)
+
+// Ailment is an illness the pet can catch, and what it does to it.
+type Ailment struct {
+	Name     string
+	Symptoms string
+	Cause    string
+	// Energy lost per hour ill, up to maxEnergySap, and happiness lost per hour
+	EnergySap    float64
+	HappinessSap float64
+}
+
+// Ailments lists the illnesses by ID.
+var Ailments = map[string]Ailment{
+	"swap_flu": {
+		Name:         "Swap Flu",
+		Symptoms:     "sluggish, forgetful, keeps losing its train of thought",
+		Cause:        CauseMemory,
+		EnergySap:    6,
+		HappinessSap: 1.5,
+	},
+	"heap_bloat": {
+		Name:         "Heap Bloat",
+		Symptoms:     "puffy, bloated, groans whenever anything allocates",
+		Cause:        CauseMemory,
+		EnergySap:    4,
+		HappinessSap: 2,
+	},
+	"thermal_fever": {
+		Name:         "Thermal Fever",
+		Symptoms:     "flushed, fans wheezing, refuses to move",
+		Cause:        CauseHeat,
+		EnergySap:    8,
+		HappinessSap: 1,
+	},
+	"throttle_pox": {
+		Name:         "Throttle Pox",
+		Symptoms:     "spotty, slowed to a crawl, everything takes twice as long",
+		Cause:        CauseHeat,
+		EnergySap:    5,
+		HappinessSap: 1.5,
+	},
+}
+
+// Illness is what the pet currently has.
+type Illness struct {
+	ID      string    `json:"id"` // key into Ailments
+	Since   time.Time `json:"since"`
+	Treated bool      `json:"treated,omitempty"` // /heal went after the cause; recovers once it's gone
+}
+
+// Ailment returns what the illness is.
+func (i Illness) Ailment() Ailment {
+	return Ailments[i.ID]
+}
+
+const (
+	// illnessOnset is how long the Pi has to stay strained before the pet
+	// falls ill.
+	illnessOnset = 30 * time.Minute
+
+	// maxEnergySap caps how much energy an illness can take.
+	maxEnergySap = 50.0
+)
+
+// strainCauseLocked returns what's straining the Pi enough to make the pet
+// ill, or "" if nothing is. Caller must hold s.mu.
+func (s *PetState) strainCauseLocked() string {
+	switch {
+	case s.MemPercent > 90:
+		return CauseMemory
+	case s.TempC > 75+species.ModifiersFor(s.SpeciesID).HeatTolerance:
+		return CauseHeat
+	}
+	return ""
+}
+
+// updateIllnessLocked moves the illness along after new system stats:
+// a long strain makes the pet ill, an illness saps its stats, and a treated
+// one clears up once its cause is gone. Caller must hold s.mu.
+func (s *PetState) updateIllnessLocked(now time.Time) {
+	cause := s.strainCauseLocked()
+	if cause != s.strainCause {
+		s.strainCause = cause
+		s.strainSince = now
+	}
+
+	if s.Illness == nil {
+		if cause != "" && s.IsAlive && now.Sub(s.strainSince) >= illnessOnset {
+			s.catchLocked(cause, now)
+		}
+		s.symptomsAt = now
+		return
+	}
+
+	ailment := s.Illness.Ailment()
+	if s.Illness.Treated && cause != ailment.Cause {
+		s.logLocked("recovered from " + ailment.Name)
+		s.Illness = nil
+		s.dirty = true
+		return
+	}
+
+	// Symptoms: energy is recomputed from the Pi each time, so it takes a
+	// growing cut; happiness wears down for as long as it lasts
+	hoursIll := now.Sub(s.Illness.Since).Hours()
+	s.Energy = clamp(s.Energy - min(hoursIll*ailment.EnergySap, maxEnergySap))
+	if !s.symptomsAt.IsZero() {
+		s.Happiness = clamp(s.Happiness - now.Sub(s.symptomsAt).Hours()*ailment.HappinessSap)
+	}
+	s.symptomsAt = now
+}
+
+// catchLocked makes the pet ill with an ailment of the given cause. Caller
+// must hold s.mu.
+func (s *PetState) catchLocked(cause string, now time.Time) {
+	var ids []string
+	for id, a := range Ailments {
+		if a.Cause == cause {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	id := ids[rand.Intn(len(ids))]
+	s.Illness = &Illness{ID: id, Since: now}
+	s.dirty = true
+	s.logLocked("came down with " + Ailments[id].Name)
+}
+
+// Treat records that the cause of the pet's illness has been dealt with, so
+// it recovers once the Pi is no longer strained. Returns false if it isn't
+// ill.
+func (s *PetState) Treat() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Illness == nil {
+		return false
+	}
+	s.dirty = true
+	s.Illness.Treated = true
+	return true
+}
+
+// Cure ends the pet's illness at once, whatever the Pi is doing, and
+// returns what it had. If the cause is still there it can fall ill again.
+func (s *PetState) Cure() (Ailment, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.Illness == nil {
+		return Ailment{}, false
+	}
+	ailment := s.Illness.Ailment()
+	s.Illness = nil
+	s.strainSince = time.Now()
+	s.dirty = true
+	s.logLocked("was cured of " + ailment.Name)
+	return ailment, true
+}
+
+// illnessTag marks a line telling other pets in the channel this one is
+// ill, posted as Discord subtext.
+const illnessTag = "-# pipet:ill "
+
+// FormatIllness renders the machine-readable line announcing an illness.
+func FormatIllness(id string) string {
+	return fmt.Sprintf("%s%s", illnessTag, id)
+}
+
+// ParseIllness extracts the illness another pet announced, if any.
+func ParseIllness(text string) (Ailment, bool) {
+	for _, line := range strings.Split(text, "\n") {
+		if id, ok := strings.CutPrefix(strings.TrimSpace(line), illnessTag); ok {
+			a, known := Ailments[strings.TrimSpace(id)]
+			return a, known
+		}
+	}
+	return Ailment{}, false
+}
@@ -0,0 +1,60 @@
+package pet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Memorial records a pet that has passed on or been reset.
+type Memorial struct {
+	Name      string    `json:"name"`
+	SpeciesID string    `json:"species_id"`
+	Shiny     bool      `json:"shiny,omitempty"`
+	BornAt    time.Time `json:"born_at"`
+	EndedAt   time.Time `json:"ended_at"`
+	AgeDays   float64   `json:"age_days"`
+	Bond      float64   `json:"bond"`
+	Cause     string    `json:"cause"` // "reset", "died", ...
+}
+
+// LoadMemorials reads the memorial record. Returns nil if the file doesn't exist.
+func LoadMemorials(path string) ([]Memorial, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read memorials: %w", err)
+	}
+
+	var memorials []Memorial
+	if err := json.Unmarshal(data, &memorials); err != nil {
+		return nil, fmt.Errorf("unmarshal memorials: %w", err)
+	}
+	return memorials, nil
+}
+
+// AppendMemorial adds entries to the memorial record atomically.
+func AppendMemorial(path string, ms ...Memorial) error {
+	memorials, err := LoadMemorials(path)
+	if err != nil {
+		return err
+	}
+	memorials = append(memorials, ms...)
+
+	data, err := json.MarshalIndent(memorials, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal memorials: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write tmp memorials: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("rename memorials: %w", err)
+	}
+	return nil
+}
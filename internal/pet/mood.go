@@ -1,5 +1,7 @@
 package pet
 
+import "github.com/moorebrett0/pipet/internal/species"
+
 // DetermineMood returns a mood string based on priority-ordered rules.
 // Priority: Dead > Sick > Anxious > Sleepy > Hungry > Bored > Happy > Content
 func DetermineMood(s Snapshot) string {
@@ -7,13 +9,13 @@ func DetermineMood(s Snapshot) string {
 		return "dead"
 	}
 
-	// Sick: memory critical (>90%)
-	if s.MemPercent > 90 {
+	// Sick: memory critical (>90%), or ill
+	if s.MemPercent > 90 || s.Illness != nil {
 		return "sick"
 	}
 
-	// Anxious: temperature high (>70°C)
-	if s.TempC > 70 {
+	// Anxious: temperature high (>70°C, shifted by species heat tolerance)
+	if s.TempC > 70+species.ModifiersFor(s.SpeciesID).HeatTolerance {
 		return "anxious"
 	}
 
@@ -0,0 +1,54 @@
+package pet
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Persist saves the state to store shortly after it changes, and again
+// every interval to keep the ambient stats fresh. Care actions mark the
+// state dirty; it's written at most debounce later, so a crash loses
+// seconds rather than a whole save interval. Blocks until ctx is
+// cancelled, then saves one last time before returning.
+func (s *PetState) Persist(ctx context.Context, store Store, debounce, interval time.Duration) {
+	check := time.NewTicker(debounce)
+	defer check.Stop()
+	periodic := time.NewTicker(interval)
+	defer periodic.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := store.Save(s); err != nil {
+				slog.Error("pet: final save failed", "err", err)
+			}
+			return
+		case <-check.C:
+			if s.takeDirty() {
+				s.persist(store)
+			}
+		case <-periodic.C:
+			s.takeDirty()
+			s.persist(store)
+		}
+	}
+}
+
+// takeDirty clears the dirty flag and reports whether it was set.
+func (s *PetState) takeDirty() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d := s.dirty
+	s.dirty = false
+	return d
+}
+
+func (s *PetState) persist(store Store) {
+	if err := store.Save(s); err != nil {
+		slog.Error("pet: save failed", "err", err)
+		s.mu.Lock()
+		s.dirty = true // try again on the next check
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,137 @@
+package pet
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Preference keys the brain can set with its set_preference tool.
+const (
+	PrefNickname  = "nickname"
+	PrefVerbosity = "verbosity"
+	PrefTimezone  = "timezone"
+	PrefAvoid     = "avoid_topic"
+	PrefFavorite  = "favorite_command"
+)
+
+// PreferenceKeys lists every preference key.
+var PreferenceKeys = []string{PrefNickname, PrefVerbosity, PrefTimezone, PrefAvoid, PrefFavorite}
+
+// Verbosities are the allowed verbosity preferences.
+var Verbosities = []string{"brief", "normal", "detailed"}
+
+// maxPrefList caps list preferences (topics to avoid, favorite commands).
+const maxPrefList = 10
+
+// Preferences is what the pet has learned about how one user likes to be
+// talked to.
+type Preferences struct {
+	Nickname  string   `json:"nickname,omitempty"`
+	Verbosity string   `json:"verbosity,omitempty"` // one of Verbosities
+	Timezone  string   `json:"timezone,omitempty"`  // IANA name, e.g. "Europe/Berlin"
+	Avoid     []string `json:"avoid,omitempty"`     // topics to stay away from
+	Favorites []string `json:"favorites,omitempty"` // commands they use a lot
+}
+
+// IsZero reports whether nothing has been learned.
+func (p Preferences) IsZero() bool {
+	return p.Nickname == "" && p.Verbosity == "" && p.Timezone == "" && len(p.Avoid) == 0 && len(p.Favorites) == 0
+}
+
+// Preferences returns what the pet knows about a user.
+func (s *PetState) Preferences(userID string) Preferences {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return copyPreferences(s.Prefs[userID])
+}
+
+// SetPreference records one preference for a user. An empty value clears a
+// single-valued key; for list keys, a value that's already present is
+// removed instead of added.
+func (s *PetState) SetPreference(userID, key, value string) error {
+	value = strings.TrimSpace(value)
+	if userID == "" {
+		return fmt.Errorf("no user to set a preference for")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	p := s.Prefs[userID]
+
+	switch key {
+	case PrefNickname:
+		if len(value) > 32 {
+			return fmt.Errorf("nickname too long")
+		}
+		p.Nickname = value
+	case PrefVerbosity:
+		if value != "" && !slices.Contains(Verbosities, value) {
+			return fmt.Errorf("verbosity must be one of %s", strings.Join(Verbosities, ", "))
+		}
+		p.Verbosity = value
+	case PrefTimezone:
+		if value != "" {
+			if _, err := time.LoadLocation(value); err != nil {
+				return fmt.Errorf("unknown timezone %q", value)
+			}
+		}
+		p.Timezone = value
+	case PrefAvoid:
+		list, err := toggleListPref(p.Avoid, value)
+		if err != nil {
+			return err
+		}
+		p.Avoid = list
+	case PrefFavorite:
+		list, err := toggleListPref(p.Favorites, value)
+		if err != nil {
+			return err
+		}
+		p.Favorites = list
+	default:
+		return fmt.Errorf("unknown preference %q", key)
+	}
+
+	if s.Prefs == nil {
+		s.Prefs = make(map[string]Preferences)
+	}
+	if p.IsZero() {
+		delete(s.Prefs, userID)
+	} else {
+		s.Prefs[userID] = p
+	}
+	return nil
+}
+
+func toggleListPref(list []string, value string) ([]string, error) {
+	if value == "" {
+		return nil, fmt.Errorf("value is required")
+	}
+	if i := slices.IndexFunc(list, func(v string) bool { return strings.EqualFold(v, value) }); i >= 0 {
+		return slices.Delete(slices.Clone(list), i, i+1), nil
+	}
+	if len(list) >= maxPrefList {
+		return nil, fmt.Errorf("already remembering %d of those", maxPrefList)
+	}
+	return append(slices.Clone(list), value), nil
+}
+
+func copyPreferences(p Preferences) Preferences {
+	p.Avoid = slices.Clone(p.Avoid)
+	p.Favorites = slices.Clone(p.Favorites)
+	return p
+}
+
+func copyPrefs(m map[string]Preferences) map[string]Preferences {
+	if len(m) == 0 {
+		return nil
+	}
+	cp := make(map[string]Preferences, len(m))
+	for id, p := range m {
+		cp[id] = copyPreferences(p)
+	}
+	return cp
+}
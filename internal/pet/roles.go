@@ -0,0 +1,119 @@
+package pet
+
+import "slices"
+
+// Caretaker roles. Each one looks after one part of the pet's wellbeing.
+const (
+	RoleFeeder  = "feeder"  // hunger
+	RoleGroomer = "groomer" // cleanliness
+	RoleMedic   = "medic"   // sickness and overheating
+)
+
+// Roles lists all caretaker roles in display order.
+var Roles = []string{RoleFeeder, RoleGroomer, RoleMedic}
+
+// IsRole reports whether name is a caretaker role.
+func IsRole(name string) bool {
+	return slices.Contains(Roles, name)
+}
+
+// NeedsCare returns the roles whose part of the pet is suffering.
+func (s Snapshot) NeedsCare() []string {
+	if !s.IsAlive {
+		return []string{RoleMedic}
+	}
+	var roles []string
+	if s.Hunger > 70 {
+		roles = append(roles, RoleFeeder)
+	}
+	if s.Cleanliness < 30 {
+		roles = append(roles, RoleGroomer)
+	}
+	if s.Mood == "sick" || s.Mood == "anxious" {
+		roles = append(roles, RoleMedic)
+	}
+	return roles
+}
+
+// HasRole reports whether a user is a caretaker for role.
+func (s *PetState) HasRole(userID, role string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Contains(s.Caretakers[role], userID)
+}
+
+// ToggleRole assigns a user to a role, or removes them if they already have
+// it. Returns true if the user now has the role.
+func (s *PetState) ToggleRole(userID, role string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	if i := slices.Index(s.Caretakers[role], userID); i >= 0 {
+		s.Caretakers[role] = slices.Delete(s.Caretakers[role], i, i+1)
+		if len(s.Caretakers[role]) == 0 {
+			delete(s.Caretakers, role)
+		}
+		return false
+	}
+	if s.Caretakers == nil {
+		s.Caretakers = make(map[string][]string)
+	}
+	s.Caretakers[role] = append(s.Caretakers[role], userID)
+	return true
+}
+
+// SeedCaretakers fills in roles from config that haven't been assigned at
+// runtime yet. Assignments made with /roles take precedence.
+func (s *PetState) SeedCaretakers(roles map[string][]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	for role, ids := range roles {
+		if !IsRole(role) || len(ids) == 0 || len(s.Caretakers[role]) > 0 {
+			continue
+		}
+		if s.Caretakers == nil {
+			s.Caretakers = make(map[string][]string)
+		}
+		s.Caretakers[role] = slices.Clone(ids)
+	}
+}
+
+// Contribute credits a user with one bit of care in a role.
+func (s *PetState) Contribute(userID, role string) {
+	if userID == "" || role == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	if s.Contributions == nil {
+		s.Contributions = make(map[string]map[string]int)
+	}
+	if s.Contributions[userID] == nil {
+		s.Contributions[userID] = make(map[string]int)
+	}
+	s.Contributions[userID][role]++
+}
+
+func copyCaretakers(m map[string][]string) map[string][]string {
+	if len(m) == 0 {
+		return nil
+	}
+	cp := make(map[string][]string, len(m))
+	for role, ids := range m {
+		cp[role] = slices.Clone(ids)
+	}
+	return cp
+}
+
+func copyContributions(m map[string]map[string]int) map[string]map[string]int {
+	if len(m) == 0 {
+		return nil
+	}
+	cp := make(map[string]map[string]int, len(m))
+	for id, counts := range m {
+		cp[id] = copyCounts(counts)
+	}
+	return cp
+}
@@ -0,0 +1,66 @@
+package pet
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/moorebrett0/pipet/internal/species"
+)
+
+// Growth is when a pet may grow into a stage: once it's old enough and its
+// care quality (see Snapshot.Care) is high enough at the time.
+type Growth struct {
+	Stage      string
+	MinAgeDays float64
+	MinCare    float64
+}
+
+// growth lists every stage after the first, in the order of species.Stages. Old age comes to
+// every pet, however it's been looked after.
+var growth = []Growth{
+	{Stage: species.StageJuvenile, MinAgeDays: 3, MinCare: 40},
+	{Stage: species.StageAdult, MinAgeDays: 14, MinCare: 55},
+	{Stage: species.StageElder, MinAgeDays: 120},
+}
+
+// NextStage returns the stage the pet grows into next and what it takes,
+// or false if it's an elder already.
+func (s Snapshot) NextStage() (Growth, bool) {
+	i := slices.Index(species.Stages, s.Stage)
+	if i < 0 || i >= len(growth) {
+		return Growth{}, false
+	}
+	return growth[i], true
+}
+
+// TryGrow moves the pet on to its next growth stage if it's old enough and
+// well enough cared for, one stage at a time. Returns the stage it left.
+func (s *PetState) TryGrow() (string, bool) {
+	snap := s.Snapshot()
+	if !snap.IsAlive {
+		return "", false
+	}
+	next, ok := snap.NextStage()
+	if !ok || snap.AgeDays < next.MinAgeDays || snap.Care() < next.MinCare {
+		return "", false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current := cmp.Or(s.Stage, species.StageHatchling); current != snap.Stage {
+		return "", false // someone else got here first
+	}
+	s.dirty = true
+	s.Stage = next.Stage
+	s.logLocked("grew into " + article(next.Stage) + " " + next.Stage)
+	return snap.Stage, true
+}
+
+// article returns "an" before a vowel and "a" otherwise.
+func article(word string) string {
+	if word != "" && strings.ContainsRune("aeiou", rune(word[0])) {
+		return "an"
+	}
+	return "a"
+}
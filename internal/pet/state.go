@@ -3,18 +3,28 @@ package pet
 import (
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"os"
+	"slices"
 	"sync"
 	"time"
+
+	"github.com/moorebrett0/pipet/internal/metrics"
+	"github.com/moorebrett0/pipet/internal/quest"
+	"github.com/moorebrett0/pipet/internal/species"
 )
 
 // PetState holds the mutable state of the pet, protected by a mutex.
 type PetState struct {
-	mu sync.RWMutex
+	mu    sync.RWMutex
+	dirty bool // changed since the last save; see Persist
 
 	// Identity (set during onboarding, never change)
 	Name      string `json:"name"`
 	SpeciesID string `json:"species_id"`
+	Shiny     bool   `json:"shiny,omitempty"` // rare variant rolled at hatch
+	Form      int    `json:"form,omitempty"`  // index into the species evolution chain
+	Stage     string `json:"stage,omitempty"` // growth stage ("" = hatchling)
 
 	// Stats (0–100)
 	Hunger      float64 `json:"hunger"`      // 0=full, 100=starving
@@ -28,6 +38,49 @@ type PetState struct {
 	LastInteraction time.Time `json:"last_interaction"`
 	LastFed         time.Time `json:"last_fed"`
 	IsAlive         bool      `json:"is_alive"`
+	Interactions    int       `json:"interactions,omitempty"` // running count, only differences matter
+
+	// Daily care streak (days with at least one owner interaction)
+	Streak      int    `json:"streak,omitempty"`
+	BestStreak  int    `json:"best_streak,omitempty"`
+	LastCareDay string `json:"last_care_day,omitempty"` // YYYY-MM-DD, local time
+
+	// Items the pet owns, by item ID
+	Inventory map[string]int `json:"inventory,omitempty"`
+
+	// Sysadmin quest currently on offer
+	Quest *quest.Active `json:"quest,omitempty"`
+
+	// Illness caught from a strained Pi (nil = healthy), and the strain
+	// building toward one
+	Illness     *Illness `json:"illness,omitempty"`
+	strainCause string
+	strainSince time.Time
+	symptomsAt  time.Time // when symptoms were last applied
+
+	// Purchased cosmetic skin, by season ID ("" = none)
+	Skin string `json:"skin,omitempty"`
+
+	// Shell balances by Discord user ID
+	Wallets map[string]int `json:"wallets,omitempty"`
+
+	// Owner user IDs set with /transfer; overrides the configured owners
+	Owners []string `json:"owners,omitempty"`
+
+	// Event log and the diary written from it
+	Events        []Event      `json:"events,omitempty"`
+	Diary         []DiaryEntry `json:"diary,omitempty"`
+	DiaryThreadID string       `json:"diary_thread_id,omitempty"`
+
+	// Co-op care: user IDs per role, and each user's care count per role
+	Caretakers    map[string][]string       `json:"caretakers,omitempty"`
+	Contributions map[string]map[string]int `json:"contributions,omitempty"`
+
+	// What the pet has learned about each user, by Discord user ID
+	Prefs map[string]Preferences `json:"prefs,omitempty"`
+
+	// Long-term memory: facts learned with /remember or the remember tool
+	Facts []Fact `json:"facts,omitempty"`
 
 	// System stats (written by monitor, read by mood/templates)
 	CPUPercent  float64 `json:"cpu_percent"`
@@ -41,6 +94,9 @@ type PetState struct {
 type Snapshot struct {
 	Name      string
 	SpeciesID string
+	Shiny     bool
+	Form      int
+	Stage     string
 
 	Hunger      float64
 	Happiness   float64
@@ -52,6 +108,18 @@ type Snapshot struct {
 	LastInteraction time.Time
 	LastFed         time.Time
 	IsAlive         bool
+	Interactions    int
+
+	Streak     int
+	BestStreak int
+
+	Inventory map[string]int
+	Quest     *quest.Active
+	Skin      string
+	Illness   *Illness
+
+	Caretakers    map[string][]string
+	Contributions map[string]map[string]int
 
 	CPUPercent  float64
 	MemPercent  float64
@@ -87,6 +155,9 @@ func (s *PetState) Snapshot() Snapshot {
 	snap := Snapshot{
 		Name:            s.Name,
 		SpeciesID:       s.SpeciesID,
+		Shiny:           s.Shiny,
+		Form:            s.Form,
+		Stage:           s.Stage,
 		Hunger:          s.Hunger,
 		Happiness:       s.Happiness,
 		Energy:          s.Energy,
@@ -96,6 +167,15 @@ func (s *PetState) Snapshot() Snapshot {
 		LastInteraction: s.LastInteraction,
 		LastFed:         s.LastFed,
 		IsAlive:         s.IsAlive,
+		Interactions:    s.Interactions,
+		Streak:          s.currentStreakLocked(time.Now()),
+		BestStreak:      s.BestStreak,
+		Inventory:       copyCounts(s.Inventory),
+		Quest:           copyQuest(s.Quest),
+		Skin:            s.Skin,
+		Illness:         copyIllness(s.Illness),
+		Caretakers:      copyCaretakers(s.Caretakers),
+		Contributions:   copyContributions(s.Contributions),
 		CPUPercent:      s.CPUPercent,
 		MemPercent:      s.MemPercent,
 		DiskPercent:     s.DiskPercent,
@@ -106,9 +186,17 @@ func (s *PetState) Snapshot() Snapshot {
 
 	snap.Mood = DetermineMood(snap)
 	snap.AgeDays = time.Since(snap.BornAt).Hours() / 24
+	if snap.Stage == "" {
+		snap.Stage = species.StageHatchling
+	}
 	return snap
 }
 
+// Care returns a 0–100 care quality score from bond, happiness, and cleanliness.
+func (s Snapshot) Care() float64 {
+	return (s.Bond + s.Happiness + s.Cleanliness) / 3
+}
+
 // IsOnboarded returns true if the pet has been set up.
 func (s *PetState) IsOnboarded() bool {
 	s.mu.RLock()
@@ -116,12 +204,20 @@ func (s *PetState) IsOnboarded() bool {
 	return s.Name != "" && s.SpeciesID != ""
 }
 
-// SetIdentity sets name and species during onboarding.
+// ShinyChance is the probability of hatching a shiny variant.
+const ShinyChance = 1.0 / 64
+
+// SetIdentity sets name and species during onboarding, rolling for a shiny.
 func (s *PetState) SetIdentity(name, speciesID string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.dirty = true
 	s.Name = name
 	s.SpeciesID = speciesID
+	s.Shiny = rand.Float64() < ShinyChance
+	s.Form = 0
+	s.Stage = ""
+	s.Illness = nil
 	now := time.Now()
 	s.BornAt = now
 	s.LastInteraction = now
@@ -132,10 +228,13 @@ func (s *PetState) SetIdentity(name, speciesID string) {
 	s.Energy = 80
 	s.Cleanliness = 80
 	s.Bond = 10
+	s.logLocked("hatched")
 }
 
-// bumpBond increases bond on interaction (diminishing returns at high levels).
+// bumpBond increases bond on interaction (diminishing returns at high levels)
+// and counts the interaction.
 func (s *PetState) bumpBond() {
+	s.Interactions++
 	gain := 2.0
 	if s.Bond > 50 {
 		gain = 1.0
@@ -150,37 +249,64 @@ func (s *PetState) bumpBond() {
 func (s *PetState) Feed() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.dirty = true
 	s.Hunger = clamp(s.Hunger - 30)
 	s.Happiness = clamp(s.Happiness + 5)
 	s.LastFed = time.Now()
 	s.LastInteraction = time.Now()
 	s.bumpBond()
+	s.logLocked("got fed")
 }
 
 // Play increases happiness and decreases energy.
 func (s *PetState) Play() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.dirty = true
 	s.Happiness = clamp(s.Happiness + 20)
 	s.Energy = clamp(s.Energy - 10)
 	s.Hunger = clamp(s.Hunger + 5)
 	s.LastInteraction = time.Now()
 	s.bumpBond()
+	s.logLocked("played with the owner")
 }
 
 // Pet increases happiness slightly (affection).
 func (s *PetState) Pet() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.dirty = true
 	s.Happiness = clamp(s.Happiness + 10)
 	s.LastInteraction = time.Now()
 	s.bumpBond()
 }
 
+// ApplyEffects applies a species command's stat changes as an interaction.
+func (s *PetState) ApplyEffects(e species.Effects) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	s.Happiness = clamp(s.Happiness + e.Happiness)
+	s.Energy = clamp(s.Energy + e.Energy)
+	s.Hunger = clamp(s.Hunger + e.Hunger)
+	s.LastInteraction = time.Now()
+	s.bumpBond()
+}
+
+// Cheer gives the pet a happiness boost (or, with a negative amount, a
+// knock) that isn't an owner interaction.
+func (s *PetState) Cheer(amount float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	s.Happiness = clamp(s.Happiness + amount)
+}
+
 // TouchInteraction records that the user interacted without stat changes.
 func (s *PetState) TouchInteraction() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.dirty = true
 	s.LastInteraction = time.Now()
 	s.bumpBond()
 }
@@ -196,35 +322,70 @@ func (s *PetState) ApplySystemStats(cpu, mem, disk, tempC, uptimeDays float64) {
 	s.TempC = tempC
 	s.UptimeDays = uptimeDays
 
+	mods := species.ModifiersFor(s.SpeciesID)
+
 	// Map system → pet stats
-	s.Hunger = clamp(cpu)                          // CPU % → hunger
-	s.Cleanliness = clamp(100 - disk)              // disk usage → cleanliness
-	s.Energy = clamp(100 - (uptimeDays * 14))      // uptime → energy drain
+	s.Hunger = clamp(cpu * mods.Hunger())                     // CPU % → hunger
+	s.Cleanliness = clamp(100 - disk)                         // disk usage → cleanliness
+	s.Energy = clamp(100 - (uptimeDays * 14 * mods.Energy())) // uptime → energy drain
 
 	// Happiness decays per hour since last interaction
 	hoursSince := time.Since(s.LastInteraction).Hours()
-	s.Happiness = clamp(s.Happiness - hoursSince*0.1) // gentle decay per update cycle
+	s.Happiness = clamp(s.Happiness - hoursSince*0.1*mods.Boredom()) // gentle decay per update cycle
 
 	// Bond decays slowly without interaction (0.5/hour)
 	s.Bond = clamp(s.Bond - hoursSince*0.05)
 
+	// A long strain makes the pet ill, and being ill wears it down
+	s.updateIllnessLocked(time.Now())
+
 	// Death: sustained critical state
 	if s.Hunger >= 95 && s.MemPercent >= 95 && s.Energy <= 5 {
 		s.IsAlive = false
 	}
 }
 
+// TryEvolve advances the pet to the next form in its species' evolution
+// chain if it's old enough and well cared for. Returns the new form.
+func (s *PetState) TryEvolve() (species.Form, bool) {
+	snap := s.Snapshot()
+	if !snap.IsAlive {
+		return species.Form{}, false
+	}
+	sp, ok := species.Registry[snap.SpeciesID]
+	if !ok {
+		return species.Form{}, false
+	}
+	next, ok := sp.NextForm(snap.Form)
+	if !ok || snap.AgeDays < next.MinAgeDays || snap.Care() < next.MinCare {
+		return species.Form{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	if s.Form != snap.Form {
+		return species.Form{}, false // someone else got here first
+	}
+	s.Form++
+	s.logLocked("evolved into a " + next.Name)
+	return next, true
+}
+
 // Kill marks the pet as dead.
 func (s *PetState) Kill() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.dirty = true
 	s.IsAlive = false
+	s.logLocked("died")
 }
 
 // Revive resets the pet to alive with decent stats.
 func (s *PetState) Revive() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.dirty = true
 	s.IsAlive = true
 	s.Hunger = 20
 	s.Happiness = 50
@@ -232,10 +393,113 @@ func (s *PetState) Revive() {
 	s.Cleanliness = 50
 	s.Bond = clamp(s.Bond * 0.5) // bond persists partially through death
 	s.LastInteraction = time.Now()
+	s.logLocked("was revived")
+}
+
+// Reset clears the pet back to an unhatched egg and returns a memorial
+// entry describing the pet that was there before.
+func (s *PetState) Reset(cause string) Memorial {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+
+	now := time.Now()
+	m := Memorial{
+		Name:      s.Name,
+		SpeciesID: s.SpeciesID,
+		Shiny:     s.Shiny,
+		BornAt:    s.BornAt,
+		EndedAt:   now,
+		AgeDays:   now.Sub(s.BornAt).Hours() / 24,
+		Bond:      s.Bond,
+		Cause:     cause,
+	}
+
+	s.Name = ""
+	s.SpeciesID = ""
+	s.Shiny = false
+	s.Form = 0
+	s.Stage = ""
+	s.Illness = nil
+	s.Hunger = 0
+	s.Happiness = 0
+	s.Energy = 0
+	s.Cleanliness = 0
+	s.Bond = 0
+	s.BornAt = time.Time{}
+	s.LastInteraction = time.Time{}
+	s.LastFed = time.Time{}
+	s.IsAlive = false
+	s.Streak = 0
+	s.BestStreak = 0
+	s.LastCareDay = ""
+	s.Inventory = nil
+	s.Quest = nil
+	s.Skin = ""
+	s.Contributions = nil
+	s.Events = nil
+	s.Diary = nil
+	s.DiaryThreadID = ""
+	// Owners, wallets, caretaker roles, preferences, and facts belong to the
+	// people and the Pi, not the pet, so they carry over
+	return m
+}
+
+// OwnerIDs returns the owners set at runtime, or nil if the configured
+// owners still apply.
+func (s *PetState) OwnerIDs() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.Owners)
+}
+
+// SetOwners records a new owner set so it survives restarts.
+func (s *PetState) SetOwners(ids []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	s.Owners = slices.Clone(ids)
+}
+
+// SetSkin puts on a purchased skin ("" takes it off).
+func (s *PetState) SetSkin(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	s.Skin = id
+}
+
+// AddItem puts n of an item into the pet's inventory.
+func (s *PetState) AddItem(id string, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	if s.Inventory == nil {
+		s.Inventory = make(map[string]int)
+	}
+	s.Inventory[id] += n
+}
+
+// TakeItem removes one of an item from the inventory. Returns false if the
+// pet doesn't have any.
+func (s *PetState) TakeItem(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	if s.Inventory[id] <= 0 {
+		return false
+	}
+	s.Inventory[id]--
+	if s.Inventory[id] == 0 {
+		delete(s.Inventory, id)
+	}
+	return true
 }
 
 // Save writes the state to disk atomically (write tmp, then rename).
-func (s *PetState) Save(path string) error {
+func (s *PetState) Save(path string) (err error) {
+	defer func(start time.Time) { metrics.Since("state.save", start, err) }(time.Now())
+
 	s.mu.RLock()
 	data, err := json.MarshalIndent(s, "", "  ")
 	s.mu.RUnlock()
@@ -270,6 +534,58 @@ func Load(path string) (*PetState, error) {
 	return &state, nil
 }
 
+// SetQuest replaces the active quest (nil clears it).
+func (s *PetState) SetQuest(q *quest.Active) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	s.Quest = copyQuest(q)
+}
+
+// CompleteQuest clears the active quest and grants its reward.
+func (s *PetState) CompleteQuest(r quest.Reward) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	s.logLocked("saw a quest completed")
+	s.Quest = nil
+	s.Bond = clamp(s.Bond + r.Bond)
+	s.Happiness = clamp(s.Happiness + 10)
+	if r.ItemID != "" {
+		if s.Inventory == nil {
+			s.Inventory = make(map[string]int)
+		}
+		s.Inventory[r.ItemID]++
+	}
+}
+
+func copyQuest(q *quest.Active) *quest.Active {
+	if q == nil {
+		return nil
+	}
+	cp := *q
+	return &cp
+}
+
+func copyIllness(i *Illness) *Illness {
+	if i == nil {
+		return nil
+	}
+	cp := *i
+	return &cp
+}
+
+func copyCounts(inv map[string]int) map[string]int {
+	if len(inv) == 0 {
+		return nil
+	}
+	cp := make(map[string]int, len(inv))
+	for k, v := range inv {
+		cp[k] = v
+	}
+	return cp
+}
+
 func clamp(v float64) float64 {
 	if v < 0 {
 		return 0
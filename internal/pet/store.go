@@ -0,0 +1,314 @@
+package pet
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/moorebrett0/pipet/internal/metrics"
+
+	_ "modernc.org/sqlite" // registers the "sqlite" driver
+)
+
+// Store is where the pet's state is kept between runs.
+type Store interface {
+	Load() (*PetState, error)
+	Save(s *PetState) error
+	Close() error
+}
+
+// Backends for pet.state_backend.
+const (
+	BackendJSON   = "json"
+	BackendSQLite = "sqlite"
+)
+
+// OpenStore opens the state backend. jsonPath is the state.json file; the
+// SQLite backend keeps its database at dbPath and, the first time it's
+// opened with nothing in it, moves the state over from jsonPath.
+func OpenStore(backend, jsonPath, dbPath string) (Store, error) {
+	switch backend {
+	case BackendJSON, "":
+		return FileStore(jsonPath), nil
+	case BackendSQLite:
+		return openSQLite(dbPath, jsonPath)
+	}
+	return nil, fmt.Errorf("unknown state backend %q", backend)
+}
+
+// FileStore keeps the state in a JSON file at the given path.
+type FileStore string
+
+func (f FileStore) Load() (*PetState, error) { return Load(string(f)) }
+func (f FileStore) Save(s *PetState) error   { return s.Save(string(f)) }
+func (f FileStore) Close() error             { return nil }
+
+// sqliteSchema keeps the parts of the state that grow — inventory, facts,
+// the event log, and the diary — in their own tables, and everything else
+// as one JSON row.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS pet_state (
+	id       INTEGER PRIMARY KEY CHECK (id = 1),
+	data     TEXT NOT NULL,
+	saved_at TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS inventory (
+	item_id TEXT PRIMARY KEY,
+	count   INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS facts (
+	id      INTEGER PRIMARY KEY,
+	text    TEXT NOT NULL,
+	by_user TEXT NOT NULL,
+	at      TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS events (
+	seq  INTEGER PRIMARY KEY,
+	at   TEXT NOT NULL,
+	text TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS diary (
+	date TEXT PRIMARY KEY,
+	text TEXT NOT NULL
+);`
+
+// sqliteStore keeps the state in a SQLite database. Each save is one
+// transaction, and WAL mode lets readers (a backup, sqlite3 on the Pi)
+// look at it while the pet writes.
+type sqliteStore struct {
+	db *sql.DB
+}
+
+func openSQLite(path, jsonPath string) (*sqliteStore, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, fmt.Errorf("open state db: %w", err)
+	}
+	// One writer at a time; SQLite would only make the rest wait
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create state tables: %w", err)
+	}
+	st := &sqliteStore{db: db}
+	if err := st.migrate(jsonPath); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return st, nil
+}
+
+// migrate copies state.json into an empty database and renames the file
+// out of the way, so it only happens once.
+func (st *sqliteStore) migrate(jsonPath string) error {
+	var n int
+	if err := st.db.QueryRow(`SELECT COUNT(*) FROM pet_state`).Scan(&n); err != nil {
+		return fmt.Errorf("count state rows: %w", err)
+	}
+	if n > 0 || jsonPath == "" {
+		return nil
+	}
+	if _, err := os.Stat(jsonPath); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("stat state: %w", err)
+	}
+	state, err := Load(jsonPath)
+	if err != nil {
+		return err
+	}
+	if err := st.Save(state); err != nil {
+		return err
+	}
+	if err := os.Rename(jsonPath, jsonPath+".migrated"); err != nil {
+		return fmt.Errorf("rename migrated state: %w", err)
+	}
+	slog.Info("pet: moved state into sqlite", "from", jsonPath)
+	return nil
+}
+
+// Load reads the state. Returns a new empty state if none was saved yet.
+func (st *sqliteStore) Load() (*PetState, error) {
+	var data string
+	err := st.db.QueryRow(`SELECT data FROM pet_state WHERE id = 1`).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &PetState{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read state: %w", err)
+	}
+	var state PetState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, fmt.Errorf("unmarshal state: %w", err)
+	}
+
+	rows, err := st.db.Query(`SELECT item_id, count FROM inventory`)
+	if err != nil {
+		return nil, fmt.Errorf("read inventory: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var id string
+		var n int
+		if err := rows.Scan(&id, &n); err != nil {
+			return err
+		}
+		if state.Inventory == nil {
+			state.Inventory = make(map[string]int)
+		}
+		state.Inventory[id] = n
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read inventory: %w", err)
+	}
+
+	rows, err = st.db.Query(`SELECT id, text, by_user, at FROM facts ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("read facts: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var f Fact
+		var at string
+		if err := rows.Scan(&f.ID, &f.Text, &f.By, &at); err != nil {
+			return err
+		}
+		if f.At, err = time.Parse(time.RFC3339Nano, at); err != nil {
+			return err
+		}
+		state.Facts = append(state.Facts, f)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read facts: %w", err)
+	}
+
+	rows, err = st.db.Query(`SELECT at, text FROM events ORDER BY seq`)
+	if err != nil {
+		return nil, fmt.Errorf("read events: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var e Event
+		var at string
+		if err := rows.Scan(&at, &e.Text); err != nil {
+			return err
+		}
+		if e.At, err = time.Parse(time.RFC3339Nano, at); err != nil {
+			return err
+		}
+		state.Events = append(state.Events, e)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read events: %w", err)
+	}
+
+	rows, err = st.db.Query(`SELECT date, text FROM diary ORDER BY date`)
+	if err != nil {
+		return nil, fmt.Errorf("read diary: %w", err)
+	}
+	err = scanRows(rows, func() error {
+		var d DiaryEntry
+		if err := rows.Scan(&d.Date, &d.Text); err != nil {
+			return err
+		}
+		state.Diary = append(state.Diary, d)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("read diary: %w", err)
+	}
+	return &state, nil
+}
+
+// scanRows calls scan for each row, then closes rows.
+func scanRows(rows *sql.Rows, scan func() error) error {
+	defer rows.Close()
+	for rows.Next() {
+		if err := scan(); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Save replaces the saved state in one transaction.
+func (st *sqliteStore) Save(s *PetState) (err error) {
+	defer func(start time.Time) { metrics.Since("state.save", start, err) }(time.Now())
+
+	s.mu.RLock()
+	c := s.copyLocked()
+	s.mu.RUnlock()
+
+	inventory, facts, events, diary := c.Inventory, c.Facts, c.Events, c.Diary
+	c.Inventory, c.Facts, c.Events, c.Diary = nil, nil, nil, nil
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("marshal state: %w", err)
+	}
+
+	tx, err := st.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin state save: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT INTO pet_state (id, data, saved_at) VALUES (1, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET data = excluded.data, saved_at = excluded.saved_at`,
+		string(data), time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return fmt.Errorf("write state: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM inventory`); err != nil {
+		return fmt.Errorf("write inventory: %w", err)
+	}
+	for id, n := range inventory {
+		if _, err := tx.Exec(`INSERT INTO inventory (item_id, count) VALUES (?, ?)`, id, n); err != nil {
+			return fmt.Errorf("write inventory: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM facts`); err != nil {
+		return fmt.Errorf("write facts: %w", err)
+	}
+	for _, f := range facts {
+		_, err := tx.Exec(`INSERT INTO facts (id, text, by_user, at) VALUES (?, ?, ?, ?)`,
+			f.ID, f.Text, f.By, f.At.Format(time.RFC3339Nano))
+		if err != nil {
+			return fmt.Errorf("write facts: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM events`); err != nil {
+		return fmt.Errorf("write events: %w", err)
+	}
+	for _, e := range events {
+		_, err := tx.Exec(`INSERT INTO events (at, text) VALUES (?, ?)`, e.At.Format(time.RFC3339Nano), e.Text)
+		if err != nil {
+			return fmt.Errorf("write events: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM diary`); err != nil {
+		return fmt.Errorf("write diary: %w", err)
+	}
+	for _, d := range diary {
+		if _, err := tx.Exec(`INSERT INTO diary (date, text) VALUES (?, ?)`, d.Date, d.Text); err != nil {
+			return fmt.Errorf("write diary: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit state save: %w", err)
+	}
+	return nil
+}
+
+func (st *sqliteStore) Close() error {
+	return st.db.Close()
+}
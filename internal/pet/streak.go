@@ -0,0 +1,79 @@
+package pet
+
+import (
+	"fmt"
+	"time"
+)
+
+// StreakMilestones maps streak lengths (in days) to the bond bonus earned
+// on reaching them.
+var StreakMilestones = map[int]float64{
+	3:   2,
+	7:   5,
+	14:  8,
+	30:  12,
+	100: 20,
+}
+
+// StreakResult describes what recording a day of care did to the streak.
+type StreakResult struct {
+	Streak    int     // streak length after recording
+	NewDay    bool    // first owner interaction of the day
+	Milestone bool    // Streak just hit a StreakMilestones entry
+	Bonus     float64 // bond granted for the milestone
+	Broken    int     // length of the streak that lapsed before today, if any
+}
+
+const dayLayout = "2006-01-02"
+
+// RecordCare marks now's local day as a care day. Only the first call each
+// day changes anything.
+func (s *PetState) RecordCare(now time.Time) StreakResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+
+	today := now.Format(dayLayout)
+	if s.LastCareDay == today {
+		return StreakResult{Streak: s.Streak}
+	}
+
+	res := StreakResult{NewDay: true}
+	if s.LastCareDay == now.AddDate(0, 0, -1).Format(dayLayout) {
+		s.Streak++
+	} else {
+		if s.Streak > 1 {
+			res.Broken = s.Streak
+		}
+		s.Streak = 1
+	}
+	s.LastCareDay = today
+	if s.Streak > s.BestStreak {
+		s.BestStreak = s.Streak
+	}
+
+	if bonus, ok := StreakMilestones[s.Streak]; ok {
+		s.Bond = clamp(s.Bond + bonus)
+		res.Milestone = true
+		res.Bonus = bonus
+		s.logLocked(fmt.Sprintf("reached a %d-day care streak", s.Streak))
+	}
+	// Losing a real streak stings a little
+	if res.Broken >= 3 {
+		s.Happiness = clamp(s.Happiness - 5)
+		s.logLocked(fmt.Sprintf("lost a %d-day care streak", res.Broken))
+	}
+
+	res.Streak = s.Streak
+	return res
+}
+
+// currentStreakLocked returns the streak as it stands at now: once a whole
+// day passes without care it reads as zero. Caller must hold s.mu.
+func (s *PetState) currentStreakLocked(now time.Time) int {
+	switch s.LastCareDay {
+	case now.Format(dayLayout), now.AddDate(0, 0, -1).Format(dayLayout):
+		return s.Streak
+	}
+	return 0
+}
@@ -0,0 +1,35 @@
+package pet
+
+// Balance returns a user's shell balance.
+func (s *PetState) Balance(userID string) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Wallets[userID]
+}
+
+// Earn adds shells to a user's wallet.
+func (s *PetState) Earn(userID string, n int) {
+	if userID == "" || n <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	if s.Wallets == nil {
+		s.Wallets = make(map[string]int)
+	}
+	s.Wallets[userID] += n
+}
+
+// Spend takes shells from a user's wallet. Returns false (and takes nothing)
+// if they can't afford it.
+func (s *PetState) Spend(userID string, n int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty = true
+	if s.Wallets[userID] < n {
+		return false
+	}
+	s.Wallets[userID] -= n
+	return true
+}
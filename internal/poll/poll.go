@@ -0,0 +1,135 @@
+package poll
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Option is one answer in a decision poll and the command it leads to.
+type Option struct {
+	Label   string
+	Command string
+}
+
+// Decision is a maintenance question the pet can put to the channel.
+type Decision struct {
+	Question string
+	Options  []Option
+}
+
+// Decisions lists the polls the pet can run. Commands must pass the shell
+// executor's safety checks.
+var Decisions = []Decision{
+	{
+		Question: "should I clear the apt cache or vacuum journald first?",
+		Options: []Option{
+			{Label: "clear the apt cache", Command: "sudo apt-get clean"},
+			{Label: "vacuum journald", Command: "sudo journalctl --vacuum-time=7d"},
+		},
+	},
+	{
+		Question: "what should I tidy up today?",
+		Options: []Option{
+			{Label: "week-old files in /tmp", Command: "find /tmp -type f -atime +7 -delete"},
+			{Label: "the thumbnail cache", Command: "rm -rf ~/.cache/thumbnails"},
+			{Label: "old apt packages", Command: "sudo apt-get autoremove -y"},
+		},
+	},
+}
+
+// Random returns a decision to poll the channel about.
+func Random() Decision {
+	return Decisions[rand.Intn(len(Decisions))]
+}
+
+// ApprovalWindow is how long a decided option waits for an owner's approval.
+const ApprovalWindow = 24 * time.Hour
+
+// Tracker holds the open poll and any decided option waiting for an owner
+// to approve it. It's shared by the scheduler, which runs polls, and the
+// router, which handles approval.
+type Tracker struct {
+	mu sync.Mutex
+
+	decision  Decision
+	messageID string
+	closesAt  time.Time
+
+	decided   *Option
+	decidedAt time.Time
+}
+
+// NewTracker creates an empty tracker.
+func NewTracker() *Tracker {
+	return &Tracker{}
+}
+
+// Open records a newly posted poll.
+func (t *Tracker) Open(d Decision, messageID string, closesAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.decision = d
+	t.messageID = messageID
+	t.closesAt = closesAt
+}
+
+// Closed returns the open poll once it has closed at now, and forgets it.
+func (t *Tracker) Closed(now time.Time) (d Decision, messageID string, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.messageID == "" || now.Before(t.closesAt) {
+		return Decision{}, "", false
+	}
+	d, messageID = t.decision, t.messageID
+	t.messageID = ""
+	return d, messageID, true
+}
+
+// IsOpen reports whether a poll is running.
+func (t *Tracker) IsOpen() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.messageID != ""
+}
+
+// Decide records the winning option for an owner to approve.
+func (t *Tracker) Decide(o Option, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.decided = &o
+	t.decidedAt = now
+}
+
+// Take returns the decided option, if one is still waiting, and clears it.
+func (t *Tracker) Take(now time.Time) (Option, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	o := t.decided
+	t.decided = nil
+	if o == nil || now.Sub(t.decidedAt) > ApprovalWindow {
+		return Option{}, false
+	}
+	return *o, true
+}
+
+// Winner picks the option with the most votes. counts is indexed like
+// d.Options. Ties and empty polls have no winner.
+func Winner(d Decision, counts []int) (Option, bool) {
+	best, bestCount, tied := -1, 0, false
+	for i, c := range counts {
+		if i >= len(d.Options) {
+			break
+		}
+		switch {
+		case c > bestCount:
+			best, bestCount, tied = i, c, false
+		case c == bestCount && c > 0:
+			tied = true
+		}
+	}
+	if best < 0 || tied {
+		return Option{}, false
+	}
+	return d.Options[best], true
+}
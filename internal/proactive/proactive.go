@@ -2,22 +2,94 @@ package proactive
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	"math"
+	"math/rand"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/bwmarrin/discordgo"
+
+	"github.com/moorebrett0/pipet/internal/contest"
 	"github.com/moorebrett0/pipet/internal/discord"
+	"github.com/moorebrett0/pipet/internal/history"
+	"github.com/moorebrett0/pipet/internal/items"
+	"github.com/moorebrett0/pipet/internal/logwatch"
+	"github.com/moorebrett0/pipet/internal/monitor"
 	"github.com/moorebrett0/pipet/internal/pet"
+	"github.com/moorebrett0/pipet/internal/poll"
+	"github.com/moorebrett0/pipet/internal/quest"
+	"github.com/moorebrett0/pipet/internal/schedule"
+	"github.com/moorebrett0/pipet/internal/shell"
 	"github.com/moorebrett0/pipet/internal/species"
 )
 
 // MessageSender can send messages and update presence.
 type MessageSender interface {
 	SendMessage(channelID, text string)
+	Post(channelID, text string) (string, error)
+	SendEmbed(channelID string, embed *discordgo.MessageEmbed)
+	CreateThread(channelID, messageID, name string) (string, error)
 	UpdatePresence(mood string)
+	UpdateStatusLine(text string)
+	SendPoll(channelID, question string, answers []string, duration time.Duration) (string, error)
+	StartThread(channelID, name string) (string, error)
+	PollResults(channelID, messageID string) ([]int, error)
 	ChannelID() string
 }
 
+// Diarist writes the pet's nightly diary entry (implemented by *brain.Brain).
+type Diarist interface {
+	WriteDiary(ctx context.Context, events []pet.Event, maxTokens int64) (string, error)
+}
+
+// LogSummarizer turns the day's log lines into a short summary
+// (implemented by *brain.Brain).
+type LogSummarizer interface {
+	SummarizeLogs(ctx context.Context, lines []string, maxTokens int64) (string, error)
+}
+
+// PostmortemWriter writes a postmortem once a distress condition clears
+// (implemented by *brain.Brain).
+type PostmortemWriter interface {
+	WritePostmortem(ctx context.Context, report string, maxTokens int64) (string, error)
+}
+
+// Dreamer spins a dream out of the previous day (implemented by
+// *brain.Brain).
+type Dreamer interface {
+	Dream(ctx context.Context, events []pet.Event, readings string, maxTokens int64) (string, error)
+}
+
+// Heartbeat tells an external uptime monitor the pet is alive (implemented
+// by *heartbeat.Pinger).
+type Heartbeat interface {
+	OK(ctx context.Context, vitals string) error
+	Fail(ctx context.Context, reason string) error
+}
+
+// AuditLog lists commands recently run on the Pi (implemented by
+// *shell.Executor).
+type AuditLog interface {
+	Recent(since time.Time) []shell.AuditEntry
+}
+
+// postmortemTokens caps the length of an incident postmortem.
+const postmortemTokens = 300
+
+// logSummaryTokens caps the length of the daily log summary.
+const logSummaryTokens = 150
+
+// quietNight is how long nobody has to have talked to the pet before the
+// morning check-in for it to count as having slept.
+const quietNight = 6 * time.Hour
+
+// moodSettle is how long a new mood has to hold before the presence follows
+// it, so stats hovering around a threshold don't flap the status.
+const moodSettle = 3 * time.Minute
+
 // Scheduler sends proactive messages based on pet state and time.
 type Scheduler struct {
 	sender   MessageSender
@@ -34,15 +106,148 @@ type Scheduler struct {
 	lastBoredom   time.Time
 	lastDeath     time.Time
 	lastMilestone int
-	lastMood      string
+	lastMood      string    // mood shown in the presence
+	moodCandidate string    // mood waiting to settle before it's shown
+	moodSince     time.Time // when moodCandidate was first seen
+	illness       string    // ailment ID last announced ("" = healthy)
+	illnessSeen   bool      // illness has been read at least once
+
+	// Weekly contests with other pets (nil if disabled)
+	contests       *contest.Board
+	contestWeekday time.Weekday
+	contestHour    int
+	lastContest    string // round last entered
+	resultsRound   string
+	resultsAt      time.Time
+
+	// Sysadmin quests (disabled if monitor is nil)
+	monitor       *monitor.Monitor
+	runner        quest.Runner
+	questInterval time.Duration
+	lastQuest     time.Time
+
+	// Decision polls (nil if disabled)
+	polls        *poll.Tracker
+	pollInterval time.Duration
+	pollDuration time.Duration
+	lastPoll     time.Time
+
+	// Nightly diary (nil if disabled)
+	diarist        Diarist
+	diaryHour      int
+	diaryMaxTokens int64
+	diaryThread    bool
+	lastDiary      string // date of the last entry written
+
+	// Daily log summary (nil if disabled)
+	summarizer     LogSummarizer
+	logSummaryHour int
+	lastLogSummary string // date of the last summary posted
+
+	// Stats sampled every check, and the daily digest drawn from them
+	history    *history.Buffer
+	digest     bool
+	digestHour int
+	lastDigest string // date of the last digest posted
+
+	// Owner-scheduled tasks, run through runner (nil disables them)
+	tasks *schedule.Book
+
+	// Dreams after a quiet night (nil disables them)
+	dreamer        Dreamer
+	dreamChance    float64
+	dreamMaxTokens int64
+
+	// Distress incident in progress, for postmortems (nil writer disables)
+	postmortems PostmortemWriter
+	audit       AuditLog
+	incident    *incident
+
+	// External uptime monitor pinged every check (nil disables it)
+	heartbeat Heartbeat
+
+	// Last time each caretaker role was nagged
+	lastNag map[string]time.Time
+
+	// Owners are paid shells for quests and uptime milestones
+	owners       []string
+	uptimePrimed bool
+	lastUptime   int // highest uptime milestone already paid
 }
 
+// contestWindow is how long to wait for other pets' entries before scoring.
+const contestWindow = 15 * time.Minute
+
 // Config for the proactive scheduler.
 type Config struct {
 	CheckInterval    time.Duration
 	MorningHour      int
 	BoredomMinutes   int
 	DistressCooldown time.Duration
+
+	// Contests is shared with the router, which feeds it other pets' entries.
+	// Nil disables contests.
+	Contests       *contest.Board
+	ContestWeekday time.Weekday
+	ContestHour    int
+
+	// Quests are verified against Monitor readings and, for failed units,
+	// commands run through Runner. A nil Monitor disables quests.
+	Monitor       *monitor.Monitor
+	Runner        quest.Runner
+	QuestInterval time.Duration // minimum time between offers
+
+	// Owners are the Discord user IDs paid shells for quests and uptime.
+	Owners []string
+
+	// Polls is shared with the router, which runs the winning option once
+	// an owner approves it. Nil disables polls.
+	Polls        *poll.Tracker
+	PollInterval time.Duration // minimum time between polls
+	PollDuration time.Duration // how long each poll stays open
+
+	// Diarist writes a short diary entry each night at DiaryHour from the
+	// day's event log, kept for /diary and optionally posted to a diary
+	// thread. Nil disables the diary.
+	Diarist        Diarist
+	DiaryHour      int
+	DiaryMaxTokens int64
+	DiaryThread    bool
+
+	// Summarizer posts a two-line summary of the day's journald warnings
+	// and errors at LogSummaryHour, read through Runner. Nil disables it.
+	Summarizer     LogSummarizer
+	LogSummaryHour int
+
+	// History is sampled every check. With Digest set, the last day of it
+	// is posted at DigestHour as an embed of stat trends, temperature
+	// spikes, disk growth, and interactions. Nil disables both.
+	History    *history.Buffer
+	Digest     bool
+	DigestHour int
+
+	// Tasks are commands owners asked the pet to run on a schedule, run
+	// through Runner. Nil disables scheduled tasks.
+	Tasks *schedule.Book
+
+	// Postmortems writes up each distress incident once it clears, in a
+	// thread on the original alert, using the metrics seen during the
+	// incident and the commands in Audit. Nil disables postmortems.
+	Postmortems PostmortemWriter
+	Audit       AuditLog
+
+	// Dreamer adds a short dream to the morning check-in, with probability
+	// DreamChance, when nobody talked to the pet overnight. It draws on the
+	// last day's events and, if Monitor is set, its temperature history.
+	// Nil disables dreams.
+	Dreamer        Dreamer
+	DreamChance    float64
+	DreamMaxTokens int64
+
+	// Heartbeat is pinged at the end of every check with the pet's vitals,
+	// or told it failed while the pet is dead or in distress. Nil disables
+	// it.
+	Heartbeat Heartbeat
 }
 
 // New creates a proactive scheduler.
@@ -54,6 +259,34 @@ func New(sender MessageSender, petState *pet.PetState, cfg Config) *Scheduler {
 		morningHour:      cfg.MorningHour,
 		boredomMinutes:   cfg.BoredomMinutes,
 		distressCooldown: cfg.DistressCooldown,
+		contests:         cfg.Contests,
+		contestWeekday:   cfg.ContestWeekday,
+		contestHour:      cfg.ContestHour,
+		monitor:          cfg.Monitor,
+		runner:           cfg.Runner,
+		questInterval:    cfg.QuestInterval,
+		owners:           cfg.Owners,
+		polls:            cfg.Polls,
+		pollInterval:     cfg.PollInterval,
+		pollDuration:     cfg.PollDuration,
+		lastPoll:         time.Now(), // don't poll the moment we start
+		lastNag:          make(map[string]time.Time),
+		diarist:          cfg.Diarist,
+		diaryHour:        cfg.DiaryHour,
+		diaryMaxTokens:   cfg.DiaryMaxTokens,
+		diaryThread:      cfg.DiaryThread,
+		summarizer:       cfg.Summarizer,
+		logSummaryHour:   cfg.LogSummaryHour,
+		history:          cfg.History,
+		digest:           cfg.Digest,
+		digestHour:       cfg.DigestHour,
+		tasks:            cfg.Tasks,
+		postmortems:      cfg.Postmortems,
+		audit:            cfg.Audit,
+		dreamer:          cfg.Dreamer,
+		dreamChance:      cfg.DreamChance,
+		dreamMaxTokens:   cfg.DreamMaxTokens,
+		heartbeat:        cfg.Heartbeat,
 	}
 }
 
@@ -72,20 +305,49 @@ func (s *Scheduler) Run(ctx context.Context) {
 	}
 }
 
+// settleMood reports whether the presence should switch to mood. A new mood
+// has to be seen for moodSettle first; the first mood and death go through
+// straight away.
+func (s *Scheduler) settleMood(mood string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if mood == s.lastMood {
+		s.moodCandidate = ""
+		return false
+	}
+	if mood != s.moodCandidate {
+		s.moodCandidate = mood
+		s.moodSince = time.Now()
+	}
+	if s.lastMood != "" && mood != "dead" && time.Since(s.moodSince) < moodSettle {
+		return false
+	}
+	s.lastMood = mood
+	s.moodCandidate = ""
+	return true
+}
+
 func (s *Scheduler) check() {
 	if !s.petState.IsOnboarded() {
 		return
 	}
 
 	snap := s.petState.Snapshot()
-	sp := getSpecies(snap.SpeciesID)
+	sp := getSpecies(snap)
 	channelID := s.sender.ChannelID()
+	if s.heartbeat != nil {
+		defer s.beat(snap)
+	}
 
-	// Always update presence when mood changes
-	if snap.Mood != s.lastMood {
-		s.lastMood = snap.Mood
+	// Update presence once a mood change has settled
+	if s.settleMood(snap.Mood) {
 		s.sender.UpdatePresence(snap.Mood)
 	}
+	s.sender.UpdateStatusLine(discord.TemplateStatusLine(snap, sp))
+	if s.history != nil {
+		s.history.Record(history.FromSnapshot(snap, time.Now()))
+	}
 
 	if channelID == "" {
 		return
@@ -96,6 +358,11 @@ func (s *Scheduler) check() {
 
 	now := time.Now()
 
+	// Scheduled tasks run whatever state the pet is in
+	if s.tasks != nil && s.runner != nil {
+		s.runTasks(now, channelID, snap, sp)
+	}
+
 	// Death notice
 	if !snap.IsAlive && (s.lastDeath.IsZero() || now.Sub(s.lastDeath) > 24*time.Hour) {
 		s.lastDeath = now
@@ -110,14 +377,97 @@ func (s *Scheduler) check() {
 	// Morning check-in
 	if now.Hour() == s.morningHour && now.Sub(s.lastMorning) > 20*time.Hour {
 		s.lastMorning = now
-		s.sender.SendMessage(channelID, discord.TemplateMorningCheckIn(snap, sp))
+		msg := discord.TemplateMorningCheckIn(snap, sp)
+		if s.dreamer != nil && now.Sub(snap.LastInteraction) > quietNight && rand.Float64() < s.dreamChance {
+			if dream := s.dream(now, snap); dream != "" {
+				msg += "\n\n" + discord.TemplateDream(snap, sp, dream)
+			}
+		}
+		s.sender.SendMessage(channelID, msg)
+		return
+	}
+
+	// Diary
+	if s.diarist != nil && now.Hour() == s.diaryHour && s.lastDiary != now.Format("2006-01-02") {
+		s.lastDiary = now.Format("2006-01-02")
+		s.writeDiary(now, channelID, snap, sp)
+	}
+
+	// Falling ill and getting better
+	if msg := s.checkIllness(snap, sp); msg != "" {
+		s.sender.SendMessage(channelID, msg)
 		return
 	}
 
 	// Distress alerts
-	if reason := checkDistress(snap); reason != "" && now.Sub(s.lastDistress) > s.distressCooldown {
+	reason := checkDistress(snap)
+	if s.postmortems != nil {
+		s.trackIncident(now, channelID, reason, snap, sp)
+	}
+	if reason != "" && now.Sub(s.lastDistress) > s.distressCooldown {
 		s.lastDistress = now
-		s.sender.SendMessage(channelID, discord.TemplateDistressAlert(snap, sp, reason))
+		s.petState.LogEvent(reason)
+		alertID, err := s.sender.Post(channelID, discord.TemplateDistressAlert(snap, sp, reason))
+		if err != nil {
+			slog.Error("proactive: distress alert failed", "err", err)
+		} else if s.incident != nil && s.incident.alertID == "" {
+			s.incident.alertID = alertID
+		}
+		return
+	}
+
+	// Nag caretakers whose part of the pet is suffering
+	if msg := s.checkCaretakers(now, snap, sp); msg != "" {
+		s.sender.SendMessage(channelID, msg)
+		return
+	}
+
+	// Daily log summary
+	if s.summarizer != nil && s.runner != nil && now.Hour() == s.logSummaryHour && s.lastLogSummary != now.Format("2006-01-02") {
+		s.lastLogSummary = now.Format("2006-01-02")
+		if msg := s.summarizeLogs(now, snap, sp); msg != "" {
+			s.sender.SendMessage(channelID, msg)
+			return
+		}
+	}
+
+	// Daily digest
+	if s.digest && s.history != nil && now.Hour() == s.digestHour && s.lastDigest != now.Format("2006-01-02") {
+		s.lastDigest = now.Format("2006-01-02")
+		if samples := s.history.Since(now.Add(-24 * time.Hour)); len(samples) > 1 {
+			s.sender.SendEmbed(channelID, discord.DigestEmbed(snap, sp, samples))
+			return
+		}
+	}
+
+	// Weekly contests
+	if s.contests != nil {
+		s.contests.Record(now, snap.TempC, snap.Cleanliness)
+		if msg := s.checkContests(now, snap, sp); msg != "" {
+			s.sender.SendMessage(channelID, msg)
+			return
+		}
+	}
+
+	// Quests
+	if s.monitor != nil {
+		if msg := s.checkQuest(now, snap, sp); msg != "" {
+			s.sender.SendMessage(channelID, msg)
+			return
+		}
+	}
+
+	// Decision polls
+	if s.polls != nil {
+		if msg := s.checkPolls(now, channelID, snap, sp); msg != "" {
+			s.sender.SendMessage(channelID, msg)
+			return
+		}
+	}
+
+	// Uptime milestones
+	if msg := s.checkUptime(snap, sp); msg != "" {
+		s.sender.SendMessage(channelID, msg)
 		return
 	}
 
@@ -129,6 +479,21 @@ func (s *Scheduler) check() {
 		return
 	}
 
+	// Growing up
+	if before, grew := s.petState.TryGrow(); grew {
+		snap = s.petState.Snapshot()
+		s.sender.SendMessage(channelID, discord.TemplateGrowth(snap, getSpecies(snap), before))
+		return
+	}
+
+	// Evolution
+	if _, evolved := s.petState.TryEvolve(); evolved {
+		before := sp.Name
+		snap = s.petState.Snapshot()
+		s.sender.SendMessage(channelID, discord.TemplateEvolution(snap, getSpecies(snap), before))
+		return
+	}
+
 	// Age milestones
 	milestones := []int{1, 7, 30, 100, 365}
 	ageDays := int(math.Floor(snap.AgeDays))
@@ -141,11 +506,452 @@ func (s *Scheduler) check() {
 	}
 }
 
+// beat pings the heartbeat in the background: a failure while the pet is
+// dead or in distress, otherwise its vitals.
+func (s *Scheduler) beat(snap pet.Snapshot) {
+	reason := checkDistress(snap)
+	if !snap.IsAlive {
+		reason = "the pet is dead: the system is critically overloaded"
+	}
+	vitals := fmt.Sprintf("%s is %s | hunger %.0f%% | CPU %.0f%% | mem %.0f%% | disk %.0f%% | %.0f°C | up %.1fd",
+		snap.Name, snap.Mood, snap.Hunger, snap.CPUPercent, snap.MemPercent, snap.DiskPercent, snap.TempC, snap.UptimeDays)
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		var err error
+		if reason != "" {
+			err = s.heartbeat.Fail(ctx, reason+" | "+vitals)
+		} else {
+			err = s.heartbeat.OK(ctx, vitals)
+		}
+		if err != nil {
+			slog.Warn("proactive: heartbeat failed", "err", err)
+		}
+	}()
+}
+
+// nagCooldown is the minimum time between nags for the same role.
+const nagCooldown = 2 * time.Hour
+
+// writeDiary has the brain reflect on the last day's events, saves the entry,
+// and posts it to the diary thread if enabled. Caller must hold s.mu.
+func (s *Scheduler) writeDiary(now time.Time, channelID string, snap pet.Snapshot, sp *species.Species) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	text, err := s.diarist.WriteDiary(ctx, s.petState.EventsSince(now.Add(-24*time.Hour)), s.diaryMaxTokens)
+	if err != nil {
+		slog.Warn("proactive: diary failed", "err", err)
+		return
+	}
+	entry := pet.DiaryEntry{Date: now.Format("2006-01-02"), Text: text}
+	s.petState.AddDiaryEntry(entry)
+	if !s.diaryThread {
+		return
+	}
+
+	threadID := s.petState.DiaryThread()
+	if threadID == "" {
+		threadID, err = s.sender.StartThread(channelID, fmt.Sprintf("%s %s's diary", sp.Emoji, snap.Name))
+		if err != nil {
+			slog.Warn("proactive: diary thread failed", "err", err)
+			return
+		}
+		s.petState.SetDiaryThread(threadID)
+	}
+	s.sender.SendMessage(threadID, discord.TemplateDiaryEntry(snap, sp, entry))
+}
+
+// dream has the brain dream about the last day's events and readings.
+// Returns "" if it couldn't. Caller must hold s.mu.
+func (s *Scheduler) dream(now time.Time, snap pet.Snapshot) string {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	text, err := s.dreamer.Dream(ctx, s.petState.EventsSince(now.Add(-24*time.Hour)), s.readings(snap), s.dreamMaxTokens)
+	if err != nil {
+		slog.Warn("proactive: dream failed", "err", err)
+		return ""
+	}
+	return text
+}
+
+// readings summarizes the Pi's state over the last day for the brain: the
+// current stats and, when the monitor is available, the temperature range
+// and any throttling.
+func (s *Scheduler) readings(snap pet.Snapshot) string {
+	out := fmt.Sprintf("now: CPU %.0f%%, memory %.0f%%, disk %.0f%%, %.0f°C\n",
+		snap.CPUPercent, snap.MemPercent, snap.DiskPercent, snap.TempC)
+	if s.monitor == nil {
+		return out
+	}
+	hist := s.monitor.TempHistory()
+	if len(hist) == 0 {
+		return out
+	}
+	lo, hi := hist[0], hist[0]
+	var throttled bool
+	for _, h := range hist {
+		if h.TempC < lo.TempC {
+			lo = h
+		}
+		if h.TempC > hi.TempC {
+			hi = h
+		}
+		throttled = throttled || h.Throttled&monitor.ThrottleThrottled != 0
+	}
+	out += fmt.Sprintf("temperature: low %.0f°C at %s, high %.0f°C at %s\n",
+		lo.TempC, lo.At.Format("15:04"), hi.TempC, hi.At.Format("15:04"))
+	if throttled {
+		out += "the CPU was throttled at some point\n"
+	}
+	return out
+}
+
+// summarizeLogs reads the last day of journal warnings and errors and has
+// the brain sum them up. Caller must hold s.mu.
+func (s *Scheduler) summarizeLogs(now time.Time, snap pet.Snapshot, sp *species.Species) string {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	lines, err := logwatch.Since(ctx, s.runner, now.Add(-24*time.Hour))
+	if err != nil {
+		slog.Warn("proactive: log summary failed", "err", err)
+		return ""
+	}
+	if len(lines) == 0 {
+		return discord.TemplateLogSummary(snap, sp, "not a single warning in the logs today. spotless.")
+	}
+	summary, err := s.summarizer.SummarizeLogs(ctx, lines, logSummaryTokens)
+	if err != nil {
+		slog.Warn("proactive: log summary failed", "err", err)
+		return ""
+	}
+	return discord.TemplateLogSummary(snap, sp, summary)
+}
+
+// runTasks runs any scheduled tasks that are due and reports their output.
+// Caller must hold s.mu.
+func (s *Scheduler) runTasks(now time.Time, channelID string, snap pet.Snapshot, sp *species.Species) {
+	for _, t := range s.tasks.Due(now) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		out, err := s.runner.Run(ctx, t.Command)
+		cancel()
+		if err != nil {
+			slog.Warn("proactive: scheduled task failed", "task", t.ID, "cmd", t.Command, "err", err)
+		}
+		s.sender.SendMessage(channelID, discord.TemplateTaskReport(snap, sp, t, out, err))
+	}
+}
+
+// incident tracks a distress condition from first alert to recovery.
+type incident struct {
+	reason  string
+	start   time.Time
+	alertID string
+	samples []sample
+}
+
+type sample struct {
+	at                    time.Time
+	cpu, mem, disk, tempC float64
+}
+
+// maxSamples caps how many readings an incident keeps; later readings
+// replace every other early one so the whole span stays covered.
+const maxSamples = 120
+
+// trackIncident records readings while the pet is in distress and writes a
+// postmortem when it clears. Caller must hold s.mu.
+func (s *Scheduler) trackIncident(now time.Time, channelID, reason string, snap pet.Snapshot, sp *species.Species) {
+	if reason != "" {
+		if s.incident == nil {
+			s.incident = &incident{reason: reason, start: now}
+		}
+		inc := s.incident
+		inc.samples = append(inc.samples, sample{at: now, cpu: snap.CPUPercent, mem: snap.MemPercent, disk: snap.DiskPercent, tempC: snap.TempC})
+		if len(inc.samples) > maxSamples {
+			thinned := inc.samples[:0]
+			for i, smp := range inc.samples {
+				if i%2 == 0 {
+					thinned = append(thinned, smp)
+				}
+			}
+			inc.samples = thinned
+		}
+		return
+	}
+
+	inc := s.incident
+	if inc == nil {
+		return
+	}
+	s.incident = nil
+	if inc.alertID == "" {
+		return // never alerted, nothing to attach to
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	text, err := s.postmortems.WritePostmortem(ctx, s.incidentReport(inc, now), postmortemTokens)
+	if err != nil {
+		slog.Warn("proactive: postmortem failed", "err", err)
+		return
+	}
+	threadID, err := s.sender.CreateThread(channelID, inc.alertID, fmt.Sprintf("%s postmortem", sp.Emoji))
+	if err != nil {
+		slog.Warn("proactive: postmortem thread failed", "err", err)
+		s.sender.SendMessage(channelID, discord.TemplatePostmortem(snap, sp, text))
+		return
+	}
+	s.sender.SendMessage(threadID, discord.TemplatePostmortem(snap, sp, text))
+}
+
+// incidentReport lays out an incident as plain text for the brain.
+func (s *Scheduler) incidentReport(inc *incident, end time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Alert: %s\nStarted: %s\nCleared: %s (%s)\n\nReadings (time, CPU%%, mem%%, disk%%, temp°C):\n",
+		inc.reason, inc.start.Format("15:04"), end.Format("15:04"), end.Sub(inc.start).Round(time.Minute))
+	step := max(1, len(inc.samples)/12)
+	for i := 0; i < len(inc.samples); i += step {
+		smp := inc.samples[i]
+		fmt.Fprintf(&b, "%s  %.0f  %.0f  %.0f  %.1f\n", smp.at.Format("15:04"), smp.cpu, smp.mem, smp.disk, smp.tempC)
+	}
+
+	b.WriteString("\nCommands run during the incident:\n")
+	var entries []shell.AuditEntry
+	if s.audit != nil {
+		entries = s.audit.Recent(inc.start)
+	}
+	if len(entries) == 0 {
+		b.WriteString("(none)\n")
+	}
+	for _, e := range entries {
+		status := "ok"
+		if e.Err != "" {
+			status = e.Err
+		}
+		fmt.Fprintf(&b, "%s  %s  [%s]\n", e.At.Format("15:04"), e.Command, status)
+	}
+	return b.String()
+}
+
+// checkIllness announces the pet falling ill, with a line other pets in the
+// channel can pick up, and getting over it. An illness already there at
+// startup isn't announced again. Caller must hold s.mu.
+func (s *Scheduler) checkIllness(snap pet.Snapshot, sp *species.Species) string {
+	current := ""
+	if snap.Illness != nil {
+		current = snap.Illness.ID
+	}
+	before := s.illness
+	s.illness = current
+	if !s.illnessSeen {
+		s.illnessSeen = true
+		return ""
+	}
+	switch {
+	case current == before:
+		return ""
+	case current == "":
+		return discord.TemplateRecovered(snap, sp, pet.Ailments[before])
+	}
+	return discord.TemplateIllness(snap, sp, snap.Illness.Ailment()) + "\n" + pet.FormatIllness(current)
+}
+
+// checkCaretakers pings the caretakers for the first role that needs
+// attention. Roles nobody has taken are left to distress alerts.
+// Caller must hold s.mu.
+func (s *Scheduler) checkCaretakers(now time.Time, snap pet.Snapshot, sp *species.Species) string {
+	for _, role := range snap.NeedsCare() {
+		ids := snap.Caretakers[role]
+		if len(ids) == 0 || now.Sub(s.lastNag[role]) < nagCooldown {
+			continue
+		}
+		s.lastNag[role] = now
+		return discord.TemplateCareNag(snap, sp, role, ids)
+	}
+	return ""
+}
+
+// checkContests enters this week's contests at the scheduled time and,
+// once other pets have had time to enter, announces any wins.
+// Caller must hold s.mu.
+func (s *Scheduler) checkContests(now time.Time, snap pet.Snapshot, sp *species.Species) string {
+	round := contest.RoundOf(now)
+
+	if now.Weekday() == s.contestWeekday && now.Hour() == s.contestHour && s.lastContest != round {
+		s.lastContest = round
+		var entries []contest.Entry
+		for _, kind := range contest.Kinds {
+			score, ok := s.contests.Score(kind)
+			if !ok {
+				continue
+			}
+			e := contest.Entry{Kind: kind, Round: round, Score: score, Pet: snap.Name}
+			s.contests.Submit(e)
+			entries = append(entries, e)
+		}
+		if len(entries) == 0 {
+			return ""
+		}
+		s.resultsRound = round
+		s.resultsAt = now.Add(contestWindow)
+		return discord.TemplateContestEntries(snap, sp, entries)
+	}
+
+	if s.resultsAt.IsZero() || now.Before(s.resultsAt) {
+		return ""
+	}
+	s.resultsAt = time.Time{}
+
+	var won [][]contest.Entry
+	for _, kind := range contest.Kinds {
+		results := s.contests.Results(kind, s.resultsRound)
+		if len(results) < 2 {
+			continue // no competition, no contest
+		}
+		if results[0].Pet == snap.Name {
+			won = append(won, results)
+			s.petState.LogEvent("won the " + contest.Title(kind) + " contest")
+			s.petState.Cheer(10)
+		} else {
+			s.petState.Cheer(3) // a good sport
+		}
+	}
+	if len(won) == 0 {
+		return ""
+	}
+	return discord.TemplateContestResults(snap, sp, won)
+}
+
+// checkQuest offers a new quest when it's time, and checks progress on the
+// active one. Caller must hold s.mu.
+func (s *Scheduler) checkQuest(now time.Time, snap pet.Snapshot, sp *species.Species) string {
+	if snap.Quest == nil {
+		if now.Sub(s.lastQuest) < s.questInterval {
+			return ""
+		}
+		s.lastQuest = now
+		a := quest.Offer(s.questFacts(), now)
+		if a == nil {
+			return ""
+		}
+		s.petState.SetQuest(a)
+		return discord.TemplateQuestOffer(snap, sp, a.Quest())
+	}
+
+	a := snap.Quest
+	q := a.Quest()
+	switch quest.Update(a, s.questFacts(), now) {
+	case quest.Done:
+		s.lastQuest = now
+		s.petState.CompleteQuest(q.Reward)
+		s.payOwners(q.Reward.Shells)
+		return discord.TemplateQuestComplete(s.petState.Snapshot(), sp, q)
+	case quest.Expired:
+		s.lastQuest = now
+		s.petState.SetQuest(nil)
+		if q == nil {
+			return ""
+		}
+		return discord.TemplateQuestExpired(snap, sp, q)
+	default:
+		s.petState.SetQuest(a)
+		return ""
+	}
+}
+
+// checkPolls posts a new decision poll when it's time, and tallies the open
+// one after it closes. Caller must hold s.mu.
+func (s *Scheduler) checkPolls(now time.Time, channelID string, snap pet.Snapshot, sp *species.Species) string {
+	if d, messageID, ok := s.polls.Closed(now); ok {
+		counts, err := s.sender.PollResults(channelID, messageID)
+		if err != nil {
+			slog.Warn("proactive: poll results failed", "err", err)
+			return ""
+		}
+		winner, ok := poll.Winner(d, counts)
+		if !ok {
+			return discord.TemplatePollNoWinner(snap, sp)
+		}
+		s.polls.Decide(winner, now)
+		return discord.TemplatePollDecided(snap, sp, winner)
+	}
+
+	if s.polls.IsOpen() || now.Sub(s.lastPoll) < s.pollInterval {
+		return ""
+	}
+	s.lastPoll = now
+	d := poll.Random()
+	answers := make([]string, len(d.Options))
+	for i, o := range d.Options {
+		answers[i] = o.Label
+	}
+	messageID, err := s.sender.SendPoll(channelID, d.Question, answers, s.pollDuration)
+	if err != nil {
+		slog.Warn("proactive: send poll failed", "err", err)
+		return ""
+	}
+	// Give Discord a minute to finalize the results
+	s.polls.Open(d, messageID, now.Add(s.pollDuration+time.Minute))
+	return ""
+}
+
+// checkUptime pays owners when the Pi crosses an uptime milestone. Milestones
+// already passed at startup aren't paid again. Caller must hold s.mu.
+func (s *Scheduler) checkUptime(snap pet.Snapshot, sp *species.Species) string {
+	reached := 0
+	for days := range items.UptimeEarnings {
+		if snap.UptimeDays >= float64(days) && days > reached {
+			reached = days
+		}
+	}
+	if !s.uptimePrimed {
+		s.uptimePrimed = true
+		s.lastUptime = reached
+		return ""
+	}
+	if reached < s.lastUptime {
+		s.lastUptime = reached // rebooted
+	}
+	if reached <= s.lastUptime {
+		return ""
+	}
+	s.lastUptime = reached
+	shells := items.UptimeEarnings[reached]
+	s.payOwners(shells)
+	return discord.TemplateUptimeMilestone(snap, sp, reached, shells)
+}
+
+func (s *Scheduler) payOwners(shells int) {
+	owners := s.petState.OwnerIDs()
+	if len(owners) == 0 {
+		owners = s.owners
+	}
+	for _, id := range owners {
+		s.petState.Earn(id, shells)
+	}
+}
+
+func (s *Scheduler) questFacts() quest.Facts {
+	stats := s.monitor.Stats()
+	f := quest.Facts{
+		DiskFreeMB:  stats.DiskFreeMB,
+		TempC:       stats.TempC,
+		FailedUnits: -1,
+	}
+	if s.runner != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		f.FailedUnits = quest.FailedUnits(ctx, s.runner)
+	}
+	return f
+}
+
 func checkDistress(snap pet.Snapshot) string {
 	if snap.MemPercent > 90 {
 		return "Memory usage is critical! I'm not feeling well..."
 	}
-	if snap.TempC > 75 {
+	if snap.TempC > 75+species.ModifiersFor(snap.SpeciesID).HeatTolerance {
 		return "It's getting really hot in here! The Pi is overheating!"
 	}
 	if snap.CPUPercent > 90 {
@@ -157,9 +963,14 @@ func checkDistress(snap pet.Snapshot) string {
 	return ""
 }
 
-func getSpecies(id string) *species.Species {
-	if sp, ok := species.Registry[id]; ok {
-		return sp
+func getSpecies(snap pet.Snapshot) *species.Species {
+	sp, ok := species.Registry[snap.SpeciesID]
+	if !ok {
+		sp = species.Registry["octopus"]
+	}
+	sp = sp.Evolved(snap.Form).AtStage(snap.Stage).Wearing(snap.Skin, time.Now())
+	if snap.Shiny {
+		sp = sp.AsShiny()
 	}
-	return species.Registry["octopus"]
+	return sp
 }
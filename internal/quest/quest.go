@@ -0,0 +1,189 @@
+package quest
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// Facts are the system readings quests are judged on.
+type Facts struct {
+	DiskFreeMB  float64
+	TempC       float64
+	FailedUnits int // -1 if unknown (no systemd, command failed)
+}
+
+// Runner runs a shell command, e.g. *shell.Executor.
+type Runner interface {
+	Run(ctx context.Context, command string) (string, error)
+}
+
+// FailedUnits counts failed systemd units, or returns -1 if it can't tell.
+func FailedUnits(ctx context.Context, r Runner) int {
+	out, err := r.Run(ctx, "systemctl --failed --no-legend --plain")
+	if err != nil {
+		return -1
+	}
+	n := 0
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Reward is granted when a quest is completed.
+type Reward struct {
+	Bond   float64
+	ItemID string
+	Shells int // paid to each owner
+}
+
+// Quest is a small real-world task the pet can ask its owner to do.
+type Quest struct {
+	ID     string
+	Title  string // short imperative, e.g. "free 500MB of disk"
+	Hint   string
+	Reward Reward
+
+	eligible func(Facts) bool
+	start    func(*Active, Facts, time.Time)
+	update   func(*Active, Facts, time.Time) // sets Progress
+}
+
+// Active is the quest currently on offer, persisted with the pet.
+type Active struct {
+	ID        string    `json:"id"`
+	OfferedAt time.Time `json:"offered_at"`
+	Baseline  float64   `json:"baseline,omitempty"` // reading when offered
+	Since     time.Time `json:"since,omitempty"`    // start of the current qualifying stretch
+	Progress  float64   `json:"progress"`           // 0–1
+}
+
+// Quest returns the definition for an active quest, or nil if unknown.
+func (a *Active) Quest() *Quest {
+	for _, q := range All {
+		if q.ID == a.ID {
+			return q
+		}
+	}
+	return nil
+}
+
+// Expiry is how long a quest stays open before the pet gives up on it.
+const Expiry = 7 * 24 * time.Hour
+
+const (
+	freeDiskMB = 500
+	coolTempC  = 60
+	coolFor    = 24 * time.Hour
+)
+
+// All lists every quest the pet can offer.
+var All = []*Quest{
+	{
+		ID:     "free-disk",
+		Title:  "free 500MB of disk",
+		Hint:   "old logs, package caches, and forgotten downloads are good places to start",
+		Reward: Reward{Bond: 5, ItemID: "treat", Shells: 10},
+		eligible: func(f Facts) bool {
+			return f.DiskFreeMB > 0
+		},
+		start: func(a *Active, f Facts, _ time.Time) {
+			a.Baseline = f.DiskFreeMB
+		},
+		update: func(a *Active, f Facts, _ time.Time) {
+			a.Progress = (f.DiskFreeMB - a.Baseline) / freeDiskMB
+		},
+	},
+	{
+		ID:     "cool-day",
+		Title:  "keep temps under 60°C for a day",
+		Hint:   "a heatsink, a fan, or just some breathing room helps",
+		Reward: Reward{Bond: 8, ItemID: "shell", Shells: 15},
+		eligible: func(f Facts) bool {
+			return f.TempC > 0
+		},
+		update: func(a *Active, f Facts, now time.Time) {
+			if f.TempC >= coolTempC {
+				a.Since = time.Time{}
+				a.Progress = 0
+				return
+			}
+			if a.Since.IsZero() {
+				a.Since = now
+			}
+			a.Progress = float64(now.Sub(a.Since)) / float64(coolFor)
+		},
+	},
+	{
+		ID:     "fix-units",
+		Title:  "fix the failed systemd unit",
+		Hint:   "`systemctl --failed` shows what's broken",
+		Reward: Reward{Bond: 10, ItemID: "pebble", Shells: 20},
+		eligible: func(f Facts) bool {
+			return f.FailedUnits > 0
+		},
+		start: func(a *Active, f Facts, _ time.Time) {
+			a.Baseline = float64(f.FailedUnits)
+		},
+		update: func(a *Active, f Facts, _ time.Time) {
+			if f.FailedUnits < 0 {
+				return
+			}
+			if f.FailedUnits == 0 {
+				a.Progress = 1
+				return
+			}
+			a.Progress = 1 - float64(f.FailedUnits)/a.Baseline
+		},
+	},
+}
+
+// Offer picks a random quest that makes sense for the current facts.
+// Returns nil if none do.
+func Offer(f Facts, now time.Time) *Active {
+	var eligible []*Quest
+	for _, q := range All {
+		if q.eligible(f) {
+			eligible = append(eligible, q)
+		}
+	}
+	if len(eligible) == 0 {
+		return nil
+	}
+	q := eligible[rand.Intn(len(eligible))]
+	a := &Active{ID: q.ID, OfferedAt: now}
+	if q.start != nil {
+		q.start(a, f, now)
+	}
+	return a
+}
+
+// Status of an active quest after an update.
+type Status int
+
+const (
+	InProgress Status = iota
+	Done
+	Expired
+)
+
+// Update advances an active quest with fresh facts.
+func Update(a *Active, f Facts, now time.Time) Status {
+	q := a.Quest()
+	if q == nil {
+		return Expired
+	}
+	q.update(a, f, now)
+	a.Progress = max(0, min(1, a.Progress))
+	if a.Progress >= 1 {
+		return Done
+	}
+	if now.Sub(a.OfferedAt) > Expiry {
+		return Expired
+	}
+	return InProgress
+}
@@ -0,0 +1,18 @@
+package render
+
+import (
+	"image"
+	"image/color"
+)
+
+const (
+	avatarSize = 256
+	avatarPad  = 24
+)
+
+// Avatar draws art centered on a square of bg, sized for a Discord avatar.
+func Avatar(art image.Image, bg color.RGBA) ([]byte, error) {
+	c := NewCanvas(avatarSize, avatarSize, bg)
+	c.Image(avatarPad, avatarPad, avatarSize-2*avatarPad, avatarSize-2*avatarPad, art)
+	return c.PNG()
+}
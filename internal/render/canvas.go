@@ -0,0 +1,116 @@
+// Package render draws simple PNG images (charts, cards) with the standard
+// library and a built-in bitmap font.
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+)
+
+// Canvas is an RGBA image with a few drawing helpers.
+type Canvas struct {
+	img *image.RGBA
+}
+
+// NewCanvas returns a w×h canvas filled with bg.
+func NewCanvas(w, h int, bg color.Color) *Canvas {
+	c := &Canvas{img: image.NewRGBA(image.Rect(0, 0, w, h))}
+	c.Fill(0, 0, w, h, bg)
+	return c
+}
+
+// Width and Height return the canvas size.
+func (c *Canvas) Width() int  { return c.img.Bounds().Dx() }
+func (c *Canvas) Height() int { return c.img.Bounds().Dy() }
+
+// Fill paints a w×h rectangle with its top-left corner at x, y.
+func (c *Canvas) Fill(x, y, w, h int, col color.Color) {
+	draw.Draw(c.img, image.Rect(x, y, x+w, y+h), image.NewUniform(col), image.Point{}, draw.Src)
+}
+
+// Line draws a line of the given thickness from (x0, y0) to (x1, y1).
+func (c *Canvas) Line(x0, y0, x1, y1, thickness int, col color.Color) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := sign(x1-x0), sign(y1-y0)
+	e := dx + dy
+	off := thickness / 2
+	for {
+		c.Fill(x0-off, y0-off, thickness, thickness, col)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		if e2 := 2 * e; e2 >= dy {
+			e += dy
+			x0 += sx
+		} else {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+// Text draws s with its top-left corner at x, y, each font pixel scale
+// pixels square. Returns the x just past the last character.
+func (c *Canvas) Text(x, y int, s string, scale int, col color.Color) int {
+	for _, r := range s {
+		g := glyph(r)
+		for row, bits := range g {
+			for colIdx, b := range bits {
+				if b == '#' {
+					c.Fill(x+colIdx*scale, y+row*scale, scale, scale, col)
+				}
+			}
+		}
+		x += (glyphW + 1) * scale
+	}
+	return x
+}
+
+// TextWidth returns how wide s is when drawn at scale.
+func TextWidth(s string, scale int) int {
+	n := len([]rune(s))
+	if n == 0 {
+		return 0
+	}
+	return n*(glyphW+1)*scale - scale
+}
+
+// TextHeight returns the height of a line of text at scale.
+func TextHeight(scale int) int {
+	return glyphH * scale
+}
+
+// PNG encodes the canvas.
+func (c *Canvas) PNG() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, c.img); err != nil {
+		return nil, fmt.Errorf("encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Hex turns 0xRRGGBB into a color, matching how embed colors are written.
+func Hex(rgb int) color.RGBA {
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xFF}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
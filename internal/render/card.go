@@ -0,0 +1,141 @@
+package render
+
+import (
+	"hash/fnv"
+	"image"
+	"image/color"
+	"strings"
+)
+
+// CardStat is a labelled 0–100 bar on a card.
+type CardStat struct {
+	Label string
+	Value float64
+}
+
+// Card is a shareable summary of a pet.
+type Card struct {
+	Name     string
+	Subtitle string   // e.g. "SHINY CRAB - LEVEL 2"
+	Lines    []string // short facts under the subtitle
+	Stats    []CardStat
+	Badges   []string
+	Accent   color.RGBA
+	Art      image.Image // drawn in the art box; nil draws a sprite from Seed
+	Seed     string
+}
+
+const (
+	cardW   = 800
+	cardH   = 440
+	artSize = 240
+)
+
+var (
+	cardBG    = Hex(0x1E1F22)
+	cardPanel = Hex(0x2B2D31)
+	cardMuted = Hex(0x949BA4)
+)
+
+// Render draws the card as a PNG.
+func (c Card) Render() ([]byte, error) {
+	cv := NewCanvas(cardW, cardH, cardBG)
+	cv.Fill(0, 0, cardW, 8, c.Accent)
+
+	// Art box on the left
+	const artX, artY = 32, 48
+	cv.Fill(artX-4, artY-4, artSize+8, artSize+8, c.Accent)
+	cv.Fill(artX, artY, artSize, artSize, cardPanel)
+	art := c.Art
+	if art == nil {
+		art = Sprite(c.Seed, c.Accent)
+	}
+	cv.Image(artX, artY, artSize, artSize, art)
+
+	// Name, subtitle, and facts on the right
+	x := artX + artSize + 40
+	y := artY
+	cv.Text(x, y, strings.ToUpper(c.Name), 5, chartText)
+	y += TextHeight(5) + 14
+	cv.Text(x, y, c.Subtitle, 2, c.Accent)
+	y += TextHeight(2) + 16
+	for _, l := range c.Lines {
+		cv.Text(x, y, l, 2, cardMuted)
+		y += TextHeight(2) + 10
+	}
+
+	// Stat bars
+	y += 8
+	barX := x + TextWidth("HAPPINESS", 2) + 16
+	barW := cardW - 32 - barX
+	for _, s := range c.Stats {
+		cv.Text(x, y, s.Label, 2, chartText)
+		cv.Fill(barX, y, barW, TextHeight(2), cardPanel)
+		fill := int(float64(barW) * max(0, min(100, s.Value)) / 100)
+		cv.Fill(barX, y, fill, TextHeight(2), c.Accent)
+		y += TextHeight(2) + 10
+	}
+
+	// Badges along the bottom
+	bx, by := artX, cardH-48
+	for _, b := range c.Badges {
+		w := TextWidth(b, 2) + 20
+		if bx+w > cardW-32 {
+			break
+		}
+		cv.Fill(bx, by, w, TextHeight(2)+14, cardPanel)
+		cv.Text(bx+10, by+7, b, 2, c.Accent)
+		bx += w + 10
+	}
+
+	cv.Text(cardW-32-TextWidth("PIPET", 1), cardH-14, "PIPET", 1, cardMuted)
+	return cv.PNG()
+}
+
+// Image draws src scaled (nearest neighbour, aspect kept) into the w×h box
+// at x, y, centered.
+func (c *Canvas) Image(x, y, w, h int, src image.Image) {
+	b := src.Bounds()
+	if b.Dx() == 0 || b.Dy() == 0 {
+		return
+	}
+	scale := min(float64(w)/float64(b.Dx()), float64(h)/float64(b.Dy()))
+	dw, dh := int(float64(b.Dx())*scale), int(float64(b.Dy())*scale)
+	ox, oy := x+(w-dw)/2, y+(h-dh)/2
+	for dy := 0; dy < dh; dy++ {
+		for dx := 0; dx < dw; dx++ {
+			px := src.At(b.Min.X+int(float64(dx)/scale), b.Min.Y+int(float64(dy)/scale))
+			if _, _, _, a := px.RGBA(); a == 0 {
+				continue
+			}
+			c.img.Set(ox+dx, oy+dy, px)
+		}
+	}
+}
+
+// Sprite makes a small mirrored pixel creature from seed, so every pet
+// gets a stable face even without species art.
+func Sprite(seed string, col color.RGBA) image.Image {
+	const n = 12
+	h := fnv.New64a()
+	h.Write([]byte(seed))
+	bits := h.Sum64()
+
+	img := image.NewRGBA(image.Rect(0, 0, n, n))
+	dark := color.RGBA{R: col.R / 3, G: col.G / 3, B: col.B / 3, A: 0xFF}
+	for y := 1; y < n-1; y++ {
+		for x := 1; x < n/2; x++ {
+			// Denser toward the middle so it reads as a body, not noise
+			on := bits&1 == 1 || (x >= n/2-2 && y > 2 && y < n-3)
+			bits = bits>>1 | bits<<63
+			if on {
+				img.Set(x, y, col)
+				img.Set(n-1-x, y, col)
+			}
+		}
+	}
+	// Eyes
+	img.Set(n/2-2, n/2-1, dark)
+	img.Set(n/2+1, n/2-1, dark)
+	return img
+}
@@ -0,0 +1,140 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Point is one sample on a time chart. Flag marks samples worth calling
+// out, drawn as a red tick under the line.
+type Point struct {
+	At    time.Time
+	Value float64
+	Flag  bool
+}
+
+// Chart describes a line chart over a trailing time window.
+type Chart struct {
+	Title string
+	Unit  string        // appended to the y-axis labels
+	Span  time.Duration // window ending now
+	Limit float64       // draws a warning line at this value (0 = none)
+}
+
+// Chart colors, on Discord's dark theme background.
+var (
+	chartBG    = Hex(0x2B2D31)
+	chartText  = Hex(0xDBDEE1)
+	chartGrid  = Hex(0x3F4147)
+	chartLine  = Hex(0xF1C40F)
+	chartAlert = Hex(0xE74C3C)
+)
+
+const (
+	chartW = 800
+	chartH = 360
+
+	padLeft   = 64
+	padRight  = 20
+	padTop    = 44
+	padBottom = 36
+)
+
+// Render draws the points from the last ch.Span before now as a PNG.
+func (ch Chart) Render(points []Point, now time.Time) ([]byte, error) {
+	c := NewCanvas(chartW, chartH, chartBG)
+	c.Text(padLeft, 14, ch.Title, 2, chartText)
+
+	plotW := chartW - padLeft - padRight
+	plotH := chartH - padTop - padBottom
+	start := now.Add(-ch.Span)
+
+	var shown []Point
+	for _, p := range points {
+		if !p.At.Before(start) && !p.At.After(now) {
+			shown = append(shown, p)
+		}
+	}
+
+	lo, hi := valueRange(shown, ch.Limit)
+	xOf := func(t time.Time) int {
+		return padLeft + int(float64(plotW)*t.Sub(start).Seconds()/ch.Span.Seconds())
+	}
+	yOf := func(v float64) int {
+		return padTop + plotH - int(float64(plotH)*(v-lo)/(hi-lo))
+	}
+
+	// Horizontal grid with value labels
+	const rows = 4
+	for i := 0; i <= rows; i++ {
+		v := lo + (hi-lo)*float64(i)/rows
+		y := yOf(v)
+		c.Line(padLeft, y, padLeft+plotW, y, 1, chartGrid)
+		label := fmt.Sprintf("%.0f%s", v, ch.Unit)
+		c.Text(padLeft-8-TextWidth(label, 1), y-TextHeight(1)/2, label, 1, chartText)
+	}
+
+	// Time labels along the bottom
+	const cols = 4
+	for i := 0; i <= cols; i++ {
+		t := start.Add(ch.Span * time.Duration(i) / cols)
+		x := xOf(t)
+		c.Line(x, padTop, x, padTop+plotH, 1, chartGrid)
+		label := "NOW"
+		if i < cols {
+			label = fmt.Sprintf("-%dH", int(math.Round(now.Sub(t).Hours())))
+		}
+		c.Text(x-TextWidth(label, 1)/2, padTop+plotH+10, label, 1, chartText)
+	}
+
+	if ch.Limit > 0 {
+		y := yOf(ch.Limit)
+		for x := padLeft; x < padLeft+plotW; x += 12 {
+			c.Line(x, y, min(x+6, padLeft+plotW), y, 1, chartAlert)
+		}
+	}
+
+	if len(shown) == 0 {
+		msg := "NO DATA YET"
+		c.Text(padLeft+(plotW-TextWidth(msg, 2))/2, padTop+plotH/2, msg, 2, chartText)
+		return c.PNG()
+	}
+
+	// Break the line across gaps (pipet wasn't running) rather than
+	// drawing a straight bridge over them
+	gap := ch.Span / 48
+	for i, p := range shown {
+		if p.Flag {
+			x := xOf(p.At)
+			c.Fill(x-1, padTop+plotH-6, 3, 6, chartAlert)
+		}
+		if i == 0 || p.At.Sub(shown[i-1].At) > gap {
+			continue
+		}
+		prev := shown[i-1]
+		c.Line(xOf(prev.At), yOf(prev.Value), xOf(p.At), yOf(p.Value), 2, chartLine)
+	}
+	return c.PNG()
+}
+
+// valueRange picks y-axis bounds around the data (and the limit line).
+func valueRange(points []Point, limit float64) (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		lo = math.Min(lo, p.Value)
+		hi = math.Max(hi, p.Value)
+	}
+	if limit > 0 {
+		lo = math.Min(lo, limit)
+		hi = math.Max(hi, limit)
+	}
+	if math.IsInf(lo, 1) {
+		return 0, 100
+	}
+	// Stretch to a multiple of 20 so each of the 4 grid rows is a round number
+	lo = math.Floor((lo-2)/5) * 5
+	hi = math.Ceil((hi+2)/5) * 5
+	hi = lo + math.Max(20, math.Ceil((hi-lo)/20)*20)
+	return lo, hi
+}
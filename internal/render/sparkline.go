@@ -0,0 +1,82 @@
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+)
+
+// Sparkline is one small labelled line in a stack of them.
+type Sparkline struct {
+	Label  string
+	Unit   string
+	Points []Point
+	Color  color.RGBA
+}
+
+const (
+	sparkW      = 600
+	sparkRowH   = 64
+	sparkLabelW = 168
+	sparkPad    = 10
+)
+
+// RenderSparklines draws each line over the span before now in its own
+// row, labelled with its latest value, as one PNG. Rows scale to their own
+// min and max, so they show shape rather than absolute levels.
+func RenderSparklines(lines []Sparkline, span time.Duration, now time.Time) ([]byte, error) {
+	c := NewCanvas(sparkW, sparkRowH*len(lines), chartBG)
+	start := now.Add(-span)
+	plotX := sparkLabelW
+	plotW := sparkW - sparkLabelW - sparkPad
+	plotH := sparkRowH - 2*sparkPad
+
+	for row, l := range lines {
+		top := row * sparkRowH
+		if row > 0 {
+			c.Line(0, top, sparkW, top, 1, chartGrid)
+		}
+
+		var shown []Point
+		for _, p := range l.Points {
+			if !p.At.Before(start) && !p.At.After(now) {
+				shown = append(shown, p)
+			}
+		}
+
+		c.Text(sparkPad, top+sparkPad, l.Label, 2, chartText)
+		value := "--"
+		if len(shown) > 0 {
+			value = fmt.Sprintf("%.0f%s", shown[len(shown)-1].Value, l.Unit)
+		}
+		c.Text(sparkPad, top+sparkPad+TextHeight(2)+6, value, 2, l.Color)
+		if len(shown) < 2 {
+			continue
+		}
+
+		lo, hi := shown[0].Value, shown[0].Value
+		for _, p := range shown {
+			lo, hi = min(lo, p.Value), max(hi, p.Value)
+		}
+		if hi-lo < 1 {
+			lo, hi = lo-0.5, hi+0.5
+		}
+		xOf := func(t time.Time) int {
+			return plotX + int(float64(plotW)*t.Sub(start).Seconds()/span.Seconds())
+		}
+		yOf := func(v float64) int {
+			return top + sparkPad + plotH - int(float64(plotH)*(v-lo)/(hi-lo))
+		}
+
+		// Same gap rule as Chart: don't bridge time pipet wasn't running
+		gap := span / 48
+		for i := 1; i < len(shown); i++ {
+			prev, p := shown[i-1], shown[i]
+			if p.At.Sub(prev.At) > gap {
+				continue
+			}
+			c.Line(xOf(prev.At), yOf(prev.Value), xOf(p.At), yOf(p.Value), 2, l.Color)
+		}
+	}
+	return c.PNG()
+}
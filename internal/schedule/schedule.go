@@ -0,0 +1,253 @@
+package schedule
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxTasks caps how many scheduled tasks the pet will keep.
+const MaxTasks = 20
+
+// Task is a shell command the pet runs on a weekly schedule and reports on.
+type Task struct {
+	ID          string         `json:"id"`
+	Description string         `json:"description"` // e.g. "check disk space"
+	Command     string         `json:"command"`
+	Days        []time.Weekday `json:"days,omitempty"` // empty means every day
+	Hour        int            `json:"hour"`
+	Minute      int            `json:"minute"`
+	CreatedBy   string         `json:"created_by,omitempty"`
+	LastRun     time.Time      `json:"last_run,omitempty"`
+}
+
+// ErrNotSchedule means a request didn't ask for anything recurring.
+var ErrNotSchedule = errors.New("not a scheduling request")
+
+// Plan is the structured form the brain translates a request into.
+type Plan struct {
+	Schedule    bool     `json:"schedule"`
+	Description string   `json:"description"`
+	Command     string   `json:"command"`
+	Days        []string `json:"days"`
+	Hour        int      `json:"hour"`
+	Minute      int      `json:"minute"`
+}
+
+// ParsePlan extracts and validates a Plan from a model reply, which should
+// contain a single JSON object.
+func ParsePlan(text string) (Task, error) {
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return Task{}, fmt.Errorf("no JSON in reply")
+	}
+	var p Plan
+	if err := json.Unmarshal([]byte(text[start:end+1]), &p); err != nil {
+		return Task{}, fmt.Errorf("unmarshal plan: %w", err)
+	}
+	if !p.Schedule {
+		return Task{}, ErrNotSchedule
+	}
+
+	t := Task{
+		Description: strings.TrimSpace(p.Description),
+		Command:     strings.TrimSpace(p.Command),
+		Hour:        p.Hour,
+		Minute:      p.Minute,
+	}
+	if t.Command == "" {
+		return Task{}, fmt.Errorf("plan has no command")
+	}
+	if t.Description == "" {
+		t.Description = t.Command
+	}
+	if t.Hour < 0 || t.Hour > 23 || t.Minute < 0 || t.Minute > 59 {
+		return Task{}, fmt.Errorf("invalid time %02d:%02d", t.Hour, t.Minute)
+	}
+	for _, d := range p.Days {
+		wd, ok := parseWeekday(d)
+		if !ok {
+			return Task{}, fmt.Errorf("unknown day %q", d)
+		}
+		t.Days = append(t.Days, wd)
+	}
+	if len(t.Days) == 7 {
+		t.Days = nil
+	}
+	return t, nil
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// When describes the schedule, e.g. "Fridays at 18:00".
+func (t Task) When() string {
+	at := fmt.Sprintf("%02d:%02d", t.Hour, t.Minute)
+	if len(t.Days) == 0 {
+		return "every day at " + at
+	}
+	days := make([]string, len(t.Days))
+	for i, d := range t.Days {
+		days[i] = d.String() + "s"
+	}
+	return strings.Join(days, ", ") + " at " + at
+}
+
+// Due reports whether the task should run at now: today is one of its days,
+// its time has passed, and it hasn't run since.
+func (t Task) Due(now time.Time) bool {
+	if len(t.Days) > 0 {
+		match := false
+		for _, d := range t.Days {
+			if d == now.Weekday() {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	slot := time.Date(now.Year(), now.Month(), now.Day(), t.Hour, t.Minute, 0, 0, now.Location())
+	return !now.Before(slot) && t.LastRun.Before(slot)
+}
+
+// Book holds confirmed tasks, persisted to a JSON file, and proposals
+// waiting for an owner to confirm them.
+type Book struct {
+	mu      sync.Mutex
+	path    string
+	tasks   []Task
+	pending map[string]Task
+}
+
+// Open loads the task book at path. A missing file is an empty book.
+func Open(path string) (*Book, error) {
+	b := &Book{path: path, pending: make(map[string]Task)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return b, nil
+		}
+		return nil, fmt.Errorf("read schedule: %w", err)
+	}
+	if err := json.Unmarshal(data, &b.tasks); err != nil {
+		return nil, fmt.Errorf("unmarshal schedule: %w", err)
+	}
+	return b, nil
+}
+
+// Propose holds t until an owner confirms or cancels it and returns its ID.
+func (b *Book) Propose(t Task) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t.ID = newID()
+	b.pending[t.ID] = t
+	return t.ID
+}
+
+// Confirm moves a proposed task into the schedule and saves it.
+func (b *Book) Confirm(id string, now time.Time) (Task, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, ok := b.pending[id]
+	if !ok {
+		return Task{}, fmt.Errorf("no pending task %q", id)
+	}
+	if len(b.tasks) >= MaxTasks {
+		return Task{}, fmt.Errorf("already have %d scheduled tasks", MaxTasks)
+	}
+	delete(b.pending, id)
+	// Don't fire immediately for a slot that already passed today
+	t.LastRun = now
+	b.tasks = append(b.tasks, t)
+	return t, b.saveLocked()
+}
+
+// Cancel drops a proposed task.
+func (b *Book) Cancel(id string) (Task, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, ok := b.pending[id]
+	delete(b.pending, id)
+	return t, ok
+}
+
+// Remove deletes a scheduled task and saves the book.
+func (b *Book) Remove(id string) (Task, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, t := range b.tasks {
+		if t.ID == id {
+			b.tasks = append(b.tasks[:i], b.tasks[i+1:]...)
+			return t, b.saveLocked()
+		}
+	}
+	return Task{}, fmt.Errorf("no scheduled task %q", id)
+}
+
+// Tasks returns a copy of the scheduled tasks.
+func (b *Book) Tasks() []Task {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([]Task, len(b.tasks))
+	copy(out, b.tasks)
+	return out
+}
+
+// Due returns the tasks to run at now and marks them run.
+func (b *Book) Due(now time.Time) []Task {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var due []Task
+	for i := range b.tasks {
+		if b.tasks[i].Due(now) {
+			b.tasks[i].LastRun = now
+			due = append(due, b.tasks[i])
+		}
+	}
+	if len(due) > 0 {
+		if err := b.saveLocked(); err != nil {
+			// Tasks still run; worst case one repeats after a restart
+			slog.Error("schedule: failed to save", "err", err)
+		}
+	}
+	return due
+}
+
+// saveLocked writes the book atomically. Caller must hold b.mu.
+func (b *Book) saveLocked() error {
+	data, err := json.MarshalIndent(b.tasks, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal schedule: %w", err)
+	}
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("write tmp schedule: %w", err)
+	}
+	if err := os.Rename(tmp, b.path); err != nil {
+		return fmt.Errorf("rename schedule: %w", err)
+	}
+	return nil
+}
+
+func newID() string {
+	var buf [3]byte
+	rand.Read(buf[:])
+	return hex.EncodeToString(buf[:])
+}
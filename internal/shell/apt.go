@@ -0,0 +1,127 @@
+package shell
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/moorebrett0/pipet/internal/metrics"
+)
+
+// Upgrade is an installed package with a newer version available.
+type Upgrade struct {
+	Package string
+	From    string
+	To      string
+}
+
+// The apt commands behind /apt. Upgrades are blocked on the generic Run
+// path, so these only ever run through PendingUpgrades and ApplyUpgrades.
+const (
+	aptRefreshCommand = "sudo apt-get update -qq"
+	aptListCommand    = "apt list --upgradable 2>/dev/null"
+	aptUpgradeCommand = "sudo env DEBIAN_FRONTEND=noninteractive apt-get upgrade -y -q " +
+		"-o Dpkg::Options::=--force-confdef -o Dpkg::Options::=--force-confold"
+)
+
+// PendingUpgrades refreshes the package lists and returns what apt would
+// upgrade, waiting up to timeout.
+func (e *Executor) PendingUpgrades(ctx context.Context, timeout time.Duration) ([]Upgrade, error) {
+	if err := e.stream(ctx, aptRefreshCommand, timeout, func(string) {}); err != nil {
+		return nil, fmt.Errorf("refreshing package lists: %w", err)
+	}
+
+	var ups []Upgrade
+	err := e.stream(ctx, aptListCommand, timeout, func(line string) {
+		if u, ok := parseUpgradable(line); ok {
+			ups = append(ups, u)
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing upgrades: %w", err)
+	}
+	return ups, nil
+}
+
+// parseUpgradable reads one line of `apt list --upgradable`, e.g.
+// "bash/stable 5.2.15-2+b7 arm64 [upgradable from: 5.2.15-2+b2]".
+func parseUpgradable(line string) (Upgrade, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 || !strings.Contains(line, "upgradable from:") {
+		return Upgrade{}, false
+	}
+	name, _, _ := strings.Cut(fields[0], "/")
+	_, from, _ := strings.Cut(line, "upgradable from:")
+	return Upgrade{
+		Package: name,
+		From:    strings.TrimSuffix(strings.TrimSpace(from), "]"),
+		To:      fields[1],
+	}, true
+}
+
+// ApplyUpgrades installs every pending upgrade, calling line with each line
+// of apt's output as it comes, and waits up to timeout.
+func (e *Executor) ApplyUpgrades(ctx context.Context, timeout time.Duration, line func(string)) error {
+	return e.stream(ctx, aptUpgradeCommand, timeout, line)
+}
+
+// RebootRequired reports whether an upgrade left the system needing a
+// reboot, and which packages asked for it (Debian's reboot-required files).
+func RebootRequired() (bool, []string) {
+	if _, err := os.Stat("/var/run/reboot-required"); err != nil {
+		return false, nil
+	}
+	data, err := os.ReadFile("/var/run/reboot-required.pkgs")
+	if err != nil {
+		return true, nil
+	}
+	var pkgs []string
+	for _, p := range strings.Fields(string(data)) {
+		if !slices.Contains(pkgs, p) {
+			pkgs = append(pkgs, p)
+		}
+	}
+	return true, pkgs
+}
+
+// stream runs one of this package's own commands with its own timeout,
+// calling line for each line of combined output as it arrives. It skips the
+// blocked-pattern check, so it must never be handed a caller's command.
+func (e *Executor) stream(ctx context.Context, command string, timeout time.Duration, line func(string)) (err error) {
+	defer func(start time.Time) {
+		e.audit(command, err)
+		metrics.Since("shell.stream", start, err)
+	}(time.Now())
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, command)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("starting command: %w", err)
+	}
+	cmd.Stderr = cmd.Stdout
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting command: %w", err)
+	}
+
+	scanner := bufio.NewScanner(out)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line(scanner.Text())
+	}
+	err = cmd.Wait()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("command timed out after %s", timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("command failed: %w", err)
+	}
+	return nil
+}
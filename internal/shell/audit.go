@@ -0,0 +1,49 @@
+package shell
+
+import (
+	"errors"
+	"os/exec"
+	"time"
+)
+
+// AuditEntry records one command the executor was asked to run.
+type AuditEntry struct {
+	At      time.Time
+	Command string
+	Err     string // empty on success
+	Exit    int    // exit status; -1 if it was blocked, timed out, or never started
+}
+
+// maxAudit caps the in-memory audit log.
+const maxAudit = 200
+
+func (e *Executor) audit(command string, err error) {
+	entry := AuditEntry{At: time.Now(), Command: command}
+	if err != nil {
+		entry.Err = err.Error()
+		entry.Exit = -1
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			entry.Exit = exit.ExitCode()
+		}
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.log = append(e.log, entry)
+	if len(e.log) > maxAudit {
+		e.log = e.log[len(e.log)-maxAudit:]
+	}
+}
+
+// Recent returns the commands run after since, oldest first.
+func (e *Executor) Recent(since time.Time) []AuditEntry {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var out []AuditEntry
+	for _, entry := range e.log {
+		if entry.At.After(since) {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
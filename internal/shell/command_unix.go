@@ -0,0 +1,33 @@
+//go:build !windows
+
+package shell
+
+import (
+	"context"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// Shell names the shell commands run in, for the brain's system prompt.
+const Shell = "sh"
+
+// platformBlocked adds nothing on Unix; blockedPatterns already covers it.
+var platformBlocked []string
+
+// shellCommand builds the process that runs a command line.
+func shellCommand(ctx context.Context, line string) *exec.Cmd {
+	return exec.CommandContext(ctx, "sh", "-c", line)
+}
+
+// killGroup starts cmd in its own process group and, when its context ends,
+// kills the whole group rather than just cmd, so nothing it spawned is left
+// running. Output pipes held open by a straggler are given up on after a
+// second.
+func killGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = time.Second
+}
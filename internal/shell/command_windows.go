@@ -0,0 +1,37 @@
+package shell
+
+import (
+	"context"
+	"os/exec"
+)
+
+// Shell names the shell commands run in, for the brain's system prompt.
+const Shell = "PowerShell"
+
+// platformBlocked are Windows commands that are never allowed, on top of
+// blockedPatterns.
+var platformBlocked = []string{
+	"format ", "format-volume", "clear-disk", "diskpart",
+	"remove-item -recurse", "rd /s", "rmdir /s", "del /s", "del /q",
+	"stop-computer", "restart-computer",
+	"bcdedit", "reg delete", "remove-itemproperty",
+	"net user", "new-localuser", "remove-localuser", "set-localuser",
+	"netsh advfirewall", "set-netfirewall",
+	"invoke-webrequest", "iwr ", "invoke-restmethod", "irm ", "start-bitstransfer",
+	"set-executionpolicy", "cipher /w", "vssadmin delete",
+}
+
+// shellCommand builds the process that runs a command line: PowerShell 7 if it's
+// installed, then Windows PowerShell, then cmd.exe as a last resort.
+func shellCommand(ctx context.Context, line string) *exec.Cmd {
+	for _, ps := range []string{"pwsh.exe", "powershell.exe"} {
+		if path, err := exec.LookPath(ps); err == nil {
+			return exec.CommandContext(ctx, path, "-NoProfile", "-NonInteractive", "-Command", line)
+		}
+	}
+	return exec.CommandContext(ctx, "cmd.exe", "/C", line)
+}
+
+// killGroup does nothing on Windows, where the Python sandbox can't run
+// anyway.
+func killGroup(cmd *exec.Cmd) {}
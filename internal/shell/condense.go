@@ -0,0 +1,172 @@
+package shell
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Condense shrinks command output to about limit bytes while keeping the
+// parts that usually matter: repeated lines are collapsed, long tables keep
+// their header and their fullest or most alarming rows, and anything still
+// too long keeps its head and (larger) tail, where errors and the latest log
+// lines tend to be.
+func Condense(out string, limit int) string {
+	if len(out) <= limit {
+		return out
+	}
+	lines := collapseRepeats(strings.Split(strings.TrimRight(out, "\n"), "\n"))
+	if size(lines) > limit {
+		lines = summarizeTable(lines, limit)
+	}
+	if size(lines) > limit {
+		lines = headTail(lines, limit)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func size(lines []string) int {
+	n := 0
+	for _, l := range lines {
+		n += len(l) + 1
+	}
+	return n
+}
+
+// collapseRepeats folds runs of identical lines into one with a count.
+func collapseRepeats(lines []string) []string {
+	var out []string
+	for i := 0; i < len(lines); {
+		j := i + 1
+		for j < len(lines) && lines[j] == lines[i] {
+			j++
+		}
+		if n := j - i; n > 2 {
+			out = append(out, fmt.Sprintf("%s  [×%d]", lines[i], n))
+		} else {
+			out = append(out, lines[i:j]...)
+		}
+		i = j
+	}
+	return out
+}
+
+// summarizeTable trims a long table (like df or ps output) to its header
+// and the rows that stand out. Output that doesn't look like a table comes
+// back unchanged.
+func summarizeTable(lines []string, limit int) []string {
+	if len(lines) < 10 {
+		return lines
+	}
+	header := strings.Fields(lines[0])
+	cols := len(header)
+	if cols < 3 {
+		return lines
+	}
+	// A header is words, not data
+	for _, h := range header {
+		if strings.ContainsAny(h, "0123456789") {
+			return lines
+		}
+	}
+	matching := 0
+	for _, l := range lines[1:] {
+		if n := len(strings.Fields(l)); n >= cols-1 && n <= cols+1 {
+			matching++
+		}
+	}
+	if matching < (len(lines)-1)*9/10 {
+		return lines
+	}
+
+	// Keep the most interesting rows that fit, in their original order
+	rows := lines[1:]
+	order := make([]int, len(rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return rowInterest(rows[order[a]]) > rowInterest(rows[order[b]])
+	})
+
+	budget := limit - len(lines[0]) - 64
+	keep := make([]bool, len(rows))
+	for _, i := range order {
+		if budget-len(rows[i])-1 < 0 {
+			break
+		}
+		budget -= len(rows[i]) + 1
+		keep[i] = true
+	}
+
+	out := []string{lines[0]}
+	kept := 0
+	for i, r := range rows {
+		if keep[i] {
+			out = append(out, r)
+			kept++
+		}
+	}
+	return append(out, fmt.Sprintf("... [%d of %d rows omitted; kept the fullest/busiest]", len(rows)-kept, len(rows)))
+}
+
+// rowInterest ranks a table row by its highest percentage, with a bump for
+// error-ish words.
+func rowInterest(row string) float64 {
+	score := 0.0
+	for _, f := range strings.Fields(row) {
+		if p, ok := strings.CutSuffix(f, "%"); ok {
+			if v, err := strconv.ParseFloat(p, 64); err == nil && v > score {
+				score = v
+			}
+		}
+	}
+	lower := strings.ToLower(row)
+	for _, w := range []string{"fail", "error", "dead", "critical"} {
+		if strings.Contains(lower, w) {
+			score += 100
+			break
+		}
+	}
+	return score
+}
+
+// headTail keeps the first third and last two thirds of the byte budget.
+func headTail(lines []string, limit int) []string {
+	budget := limit - 64
+	headBudget := budget / 3
+	tailBudget := budget - headBudget
+
+	var head []string
+	for _, l := range lines {
+		if headBudget-len(l)-1 < 0 {
+			break
+		}
+		headBudget -= len(l) + 1
+		head = append(head, l)
+	}
+
+	var tail []string
+	for i := len(lines) - 1; i >= len(head); i-- {
+		l := lines[i]
+		if tailBudget-len(l)-1 < 0 {
+			break
+		}
+		tailBudget -= len(l) + 1
+		tail = append(tail, lines[i])
+	}
+	for a, b := 0, len(tail)-1; a < b; a, b = a+1, b-1 {
+		tail[a], tail[b] = tail[b], tail[a]
+	}
+
+	// A single huge line still needs cutting
+	if len(head) == 0 && len(tail) == 0 && len(lines) > 0 {
+		l := lines[0]
+		return []string{l[:budget/3], "... [output truncated] ...", l[len(l)-budget*2/3:]}
+	}
+
+	omitted := len(lines) - len(head) - len(tail)
+	out := append(head, fmt.Sprintf("... [%d lines omitted] ...", omitted))
+	return append(out, tail...)
+}
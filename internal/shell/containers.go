@@ -0,0 +1,60 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+)
+
+// ContainerActions lists what Container can do.
+var ContainerActions = []string{"list", "logs", "restart"}
+
+// Log tail bounds for Container's "logs" action.
+const (
+	defaultContainerLines = 50
+	maxContainerLines     = 500
+)
+
+// containerName matches the names Docker and Podman allow.
+var containerName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// ContainerRuntime returns the container CLI on PATH, "docker" or "podman"
+// (docker wins if both are installed), or "" if neither is.
+func ContainerRuntime() string {
+	for _, rt := range []string{"docker", "podman"} {
+		if _, err := exec.LookPath(rt); err == nil {
+			return rt
+		}
+	}
+	return ""
+}
+
+// Container lists containers, tails one's logs (lines of them, 0 for the
+// default), or restarts one, with whichever runtime is installed. Commands
+// go through Run, so they're audited; callers decide which containers may
+// be restarted.
+func (e *Executor) Container(ctx context.Context, action, name string, lines int) (string, error) {
+	rt := ContainerRuntime()
+	if rt == "" {
+		return "", fmt.Errorf("neither docker nor podman is installed")
+	}
+	if action != "list" && !containerName.MatchString(name) {
+		return "", fmt.Errorf("invalid container name %q", name)
+	}
+
+	switch action {
+	case "list":
+		return e.Run(ctx, rt+" ps -a --format 'table {{.Names}}\t{{.Status}}\t{{.Image}}'")
+	case "logs":
+		if lines <= 0 {
+			lines = defaultContainerLines
+		}
+		lines = min(lines, maxContainerLines)
+		return e.Run(ctx, fmt.Sprintf("%s logs --tail %d %s 2>&1", rt, lines, name))
+	case "restart":
+		return e.Run(ctx, fmt.Sprintf("%s restart %s && %s ps -a --filter name=^%s$ --format '{{.Names}}: {{.Status}}'", rt, name, rt, name))
+	default:
+		return "", fmt.Errorf("unknown container action %q", action)
+	}
+}
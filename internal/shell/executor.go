@@ -3,9 +3,12 @@ package shell
 import (
 	"context"
 	"fmt"
-	"os/exec"
+	"slices"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/moorebrett0/pipet/internal/metrics"
 )
 
 // blockedPatterns are substrings that are never allowed in commands.
@@ -32,12 +35,18 @@ var blockedPatterns = []string{
 	"nft ",
 	"systemctl disable",
 	"systemctl mask",
+	// Upgrades go through /apt, which asks first and doesn't time out
+	"apt upgrade", "apt-get upgrade",
+	"apt full-upgrade", "apt dist-upgrade", "apt-get dist-upgrade",
 }
 
 // Executor runs shell commands with safety checks and timeouts.
 type Executor struct {
 	timeout   time.Duration
 	maxOutput int
+
+	mu  sync.Mutex
+	log []AuditEntry
 }
 
 // New creates a shell executor.
@@ -48,22 +57,26 @@ func New(timeout time.Duration, maxOutput int) *Executor {
 	}
 }
 
-// Run executes a command and returns its combined output, truncated to maxOutput.
-func (e *Executor) Run(ctx context.Context, command string) (string, error) {
-	if blocked := checkBlocked(command); blocked != "" {
-		return "", fmt.Errorf("blocked command pattern: %q", blocked)
+// Run executes a command line in the platform's shell (sh, or PowerShell on
+// Windows) and returns its combined output, condensed to about
+// maxOutput bytes.
+func (e *Executor) Run(ctx context.Context, command string) (_ string, err error) {
+	defer func(start time.Time) {
+		e.audit(command, err)
+		metrics.Since("shell.run", start, err)
+	}(time.Now())
+
+	if err := Check(command); err != nil {
+		return "", err
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, e.timeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd := shellCommand(ctx, command)
 	out, err := cmd.CombinedOutput()
 
-	result := string(out)
-	if len(result) > e.maxOutput {
-		result = result[:e.maxOutput] + "\n... [output truncated]"
-	}
+	result := Condense(string(out), e.maxOutput)
 
 	if ctx.Err() == context.DeadlineExceeded {
 		return result, fmt.Errorf("command timed out after %s", e.timeout)
@@ -76,9 +89,18 @@ func (e *Executor) Run(ctx context.Context, command string) (string, error) {
 	return result, nil
 }
 
+// Check returns an error if command matches a blocked pattern, so callers
+// can reject a command before it's ever run.
+func Check(command string) error {
+	if blocked := checkBlocked(command); blocked != "" {
+		return fmt.Errorf("blocked command pattern: %q", blocked)
+	}
+	return nil
+}
+
 func checkBlocked(command string) string {
 	lower := strings.ToLower(command)
-	for _, pattern := range blockedPatterns {
+	for _, pattern := range slices.Concat(blockedPatterns, platformBlocked) {
 		if strings.Contains(lower, strings.ToLower(pattern)) {
 			return pattern
 		}
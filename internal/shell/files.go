@@ -0,0 +1,116 @@
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/moorebrett0/pipet/internal/metrics"
+)
+
+// backupSuffix is appended to a file's name for the copy kept before an
+// approved write changes it.
+const backupSuffix = ".pipet.bak"
+
+// WriteFile writes content to path, or appends it, once an owner has
+// approved the change. An existing file is copied to path+".pipet.bak"
+// first, and that backup's path is returned. Files pipet can't write as
+// itself (most of /etc) go through `sudo -n`, so they need passwordless
+// sudo. Writes are recorded in the audit log like commands.
+func (e *Executor) WriteFile(ctx context.Context, path, content string, appendTo bool) (backup string, err error) {
+	verb := "write"
+	if appendTo {
+		verb = "append to"
+	}
+	defer func(start time.Time) {
+		e.audit(fmt.Sprintf("%s %s (%d bytes, approved)", verb, path, len(content)), err)
+		metrics.Since("shell.write", start, err)
+	}(time.Now())
+
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("path must be absolute: %s", path)
+	}
+	path = filepath.Clean(path)
+	info, statErr := os.Stat(path)
+	switch {
+	case statErr == nil && info.IsDir():
+		return "", fmt.Errorf("%s is a directory", path)
+	case statErr == nil:
+		backup = path + backupSuffix
+	case !errors.Is(statErr, fs.ErrNotExist):
+		backup = path + backupSuffix // can't see it as ourselves; sudo will check
+	}
+
+	err = writeDirect(path, backup, content, appendTo)
+	if errors.Is(err, fs.ErrPermission) && runtime.GOOS != "windows" {
+		ctx, cancel := context.WithTimeout(ctx, e.timeout)
+		defer cancel()
+		err = writeSudo(ctx, path, backup, content, appendTo)
+	}
+	if err != nil {
+		return "", err
+	}
+	return backup, nil
+}
+
+// writeDirect backs up and writes the file as pipet's own user.
+func writeDirect(path, backup, content string, appendTo bool) error {
+	if backup != "" {
+		old, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(backup, old, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendTo {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeSudo backs up and writes the file through sudo, feeding the content
+// to tee on stdin so it never passes through a shell.
+func writeSudo(ctx context.Context, path, backup, content string, appendTo bool) error {
+	if backup != "" {
+		cp := exec.CommandContext(ctx, "sudo", "-n", "sh", "-c", `[ ! -e "$1" ] || cp -p -- "$1" "$2"`, "sh", path, backup)
+		if out, err := cp.CombinedOutput(); err != nil {
+			return fmt.Errorf("backing up %s: %w: %s", path, err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	args := []string{"-n", "tee"}
+	if appendTo {
+		args = append(args, "-a")
+	}
+	tee := exec.CommandContext(ctx, "sudo", append(args, "--", path)...)
+	tee.Stdin = strings.NewReader(content)
+	var stderr strings.Builder
+	tee.Stderr = &stderr
+	if err := tee.Run(); err != nil {
+		return fmt.Errorf("writing %s with sudo: %w: %s", path, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// maxScriptBytes caps the size of a script passed to Python.Run.
+const maxScriptBytes = 16 << 10
+
+// maxScriptProcs caps processes and threads for a script. The kernel counts
+// everything the pipet user runs against it, the bot included, so it's
+// generous; it's there to stop a fork bomb, not a thread pool.
+const maxScriptProcs = 256
+
+// sandboxScript runs as root of a fresh user, mount, PID, and network
+// namespace. It builds a root out of a tmpfs with only the system's
+// programs and libraries bound in read-only, a few harmless devices, and a
+// tmpfs scratch /tmp, then chroots into it and hands the limits in "$@" to
+// prlimit. Nothing of the pipet user's files (config, .env, state) is
+// visible, and nothing written outlives the run.
+const sandboxScript = `set -e
+root=$1; shift
+mount -t tmpfs -o size=16m,mode=755 tmpfs "$root"
+for d in /usr /bin /sbin /lib /lib32 /lib64; do
+	if [ -L "$d" ]; then
+		ln -s "$(readlink "$d")" "$root$d"
+	elif [ -d "$d" ]; then
+		mkdir "$root$d"
+		mount --rbind "$d" "$root$d"
+		mount -o remount,bind,ro "$root$d"
+	fi
+done
+mkdir "$root/dev" "$root/tmp"
+for f in null zero urandom; do
+	touch "$root/dev/$f"
+	mount --bind "/dev/$f" "$root/dev/$f"
+done
+mount -t tmpfs -o size=64m tmpfs "$root/tmp"
+exec chroot "$root" /bin/sh -c 'cd /tmp && exec prlimit "$@" python3 -I -' sh "$@"
+`
+
+// Python runs short scripts in a locked-down python3: its own root with the
+// system's programs read-only and an empty tmpfs scratch directory, no
+// network, a clean environment, and CPU, memory, file size, process, and
+// wall-clock limits.
+type Python struct {
+	timeout   time.Duration
+	memoryMB  int
+	maxOutput int
+}
+
+// NewPython creates a sandboxed Python interpreter.
+func NewPython(timeout time.Duration, memoryMB, maxOutput int) *Python {
+	return &Python{
+		timeout:   timeout,
+		memoryMB:  memoryMB,
+		maxOutput: maxOutput,
+	}
+}
+
+// Run executes a script and returns its combined output, condensed to about
+// maxOutput bytes. It needs python3 and util-linux's unshare and prlimit, and
+// unprivileged user namespaces enabled; without them it fails rather than
+// run unsandboxed. On timeout everything the script started is killed.
+func (p *Python) Run(ctx context.Context, code string) (string, error) {
+	if len(code) > maxScriptBytes {
+		return "", fmt.Errorf("script too long (max %d bytes)", maxScriptBytes)
+	}
+
+	// Only a mount point; the sandbox's root is a tmpfs over it
+	dir, err := os.MkdirTemp("", "pipet-python-")
+	if err != nil {
+		return "", fmt.Errorf("create scratch dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	// CPU seconds can't exceed wall time; files are capped at 1MB
+	cpu := max(1, int(p.timeout.Seconds()))
+	cmd := exec.CommandContext(ctx, "unshare",
+		"--user", "--map-root-user", "--mount", "--net", "--pid", "--fork", "--kill-child",
+		"sh", "-c", sandboxScript, "sh", dir,
+		fmt.Sprintf("--cpu=%d", cpu),
+		fmt.Sprintf("--as=%d", p.memoryMB<<20),
+		fmt.Sprintf("--fsize=%d", 1<<20),
+		fmt.Sprintf("--nproc=%d", maxScriptProcs),
+	)
+	killGroup(cmd)
+	cmd.Env = []string{"PATH=/usr/local/bin:/usr/bin:/bin:/usr/sbin:/sbin", "HOME=/tmp", "PYTHONDONTWRITEBYTECODE=1"}
+	cmd.Stdin = strings.NewReader(code)
+	out, err := cmd.CombinedOutput()
+
+	result := Condense(string(out), p.maxOutput)
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return result, fmt.Errorf("script timed out after %s", p.timeout)
+	}
+
+	if err != nil {
+		return result, fmt.Errorf("script failed: %w", err)
+	}
+
+	return result, nil
+}
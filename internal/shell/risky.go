@@ -0,0 +1,404 @@
+package shell
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// riskyCommands change or stop things whatever their arguments, so an owner
+// has to confirm them before /shell runs them. PowerShell cmdlets and cmd.exe
+// built-ins are here too, in lower case.
+var riskyCommands = []string{
+	"rm", "rmdir", "unlink", "mv", "rename", "truncate", "shred",
+	"chmod", "chown", "chgrp", "ln",
+	"kill", "pkill", "killall",
+	"service", "crontab", "mount", "umount", "swapoff",
+	"sudo", "doas", "su", "tee",
+	"remove-item", "ri", "del", "erase", "rd",
+	"set-content", "add-content", "clear-content", "out-file",
+	"move-item", "mi", "move", "rename-item", "ren",
+	"stop-process", "stop-service", "restart-service", "set-itemproperty",
+}
+
+// riskySubcommands are commands that are only risky with one of these among
+// their arguments, like "systemctl restart". Any argument counts, so
+// "git -C /srv push" is caught at the cost of the odd harmless match.
+var riskySubcommands = map[string][]string{
+	"systemctl": {"stop", "restart", "start", "enable", "kill", "reload", "isolate"},
+	"docker":    {"rm", "rmi", "stop", "kill", "restart", "prune"},
+	"podman":    {"rm", "rmi", "stop", "kill", "restart", "prune"},
+	"apt":       {"install", "remove", "purge", "autoremove"},
+	"apt-get":   {"install", "remove", "purge", "autoremove"},
+	"pip":       {"install", "uninstall"},
+	"pip3":      {"install", "uninstall"},
+	"npm":       {"install", "uninstall"},
+	"git":       {"push", "reset", "clean"},
+}
+
+// wrappers run the command that follows them, so it's that command that
+// counts.
+var wrappers = []string{"env", "nohup", "nice", "ionice", "time", "timeout", "stdbuf", "xargs", "command", "exec", "busybox"}
+
+// shells run the command line passed with -c (or -Command, /c).
+var shells = []string{"sh", "bash", "dash", "zsh", "pwsh", "powershell", "cmd"}
+
+// Risky returns what makes command worth confirming before it runs, or ""
+// if it looks harmless. It reads the command the way a shell would: every
+// command in a pipeline or list, through sudo, xargs, find -exec, sh -c,
+// and $(...), with redirections spotted whether or not they're spaced out.
+// Copies are only risky if they'd replace something that's already there.
+func Risky(command string) string {
+	p := parseLine(command)
+	if len(p.redirects) > 0 {
+		return ">"
+	}
+	for _, words := range p.commands {
+		if reason := riskyWords(words); reason != "" {
+			return reason
+		}
+	}
+	for _, sub := range p.subs {
+		if reason := Risky(sub); reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
+
+// riskyWords checks one simple command, already split into words.
+func riskyWords(words []string) string {
+	for len(words) > 0 && isAssignment(words[0]) {
+		words = words[1:]
+	}
+	if len(words) == 0 {
+		return ""
+	}
+	name := strings.ToLower(path.Base(strings.ReplaceAll(words[0], `\`, "/")))
+	name = strings.TrimSuffix(name, ".exe")
+	args := words[1:]
+
+	switch {
+	case slices.Contains(riskyCommands, name):
+		return name
+	case slices.Contains(wrappers, name):
+		return riskyWords(skipOptions(args, name == "timeout"))
+	case slices.Contains(shells, name):
+		powershell := name == "pwsh" || name == "powershell"
+		for n, arg := range args {
+			lower := strings.ToLower(arg)
+			switch {
+			case powershell && (lower == "-encodedcommand" || lower == "-ec" || lower == "-e"):
+				return name + " " + arg // can't see what it runs
+			case lower == "-command" || lower == "-c" || lower == "/c" || lower == "/k",
+				!powershell && name != "cmd" && hasOption([]string{arg}, 'c', ""):
+				return Risky(strings.Join(args[n+1:], " "))
+			}
+		}
+		return ""
+	}
+
+	switch name {
+	case "sed":
+		if hasOption(args, 'i', "--in-place") {
+			return "sed -i"
+		}
+	case "perl":
+		if hasOption(args, 'i', "") {
+			return "perl -i"
+		}
+	case "dd":
+		for _, arg := range args {
+			if strings.HasPrefix(arg, "of=") {
+				return "dd of="
+			}
+		}
+	case "find":
+		for n, arg := range args {
+			switch arg {
+			case "-delete":
+				return "find -delete"
+			case "-exec", "-execdir", "-ok", "-okdir":
+				run := args[n+1:]
+				if end := slices.IndexFunc(run, func(w string) bool { return w == ";" || w == "+" }); end >= 0 {
+					run = run[:end]
+				}
+				if reason := riskyWords(run); reason != "" {
+					return "find " + arg + " " + reason
+				}
+			}
+		}
+	case "cp", "install", "copy", "copy-item", "cpi":
+		if overwrites(args) {
+			return name + " over an existing file"
+		}
+	}
+
+	for _, sub := range riskySubcommands[name] {
+		if slices.Contains(args, sub) {
+			return name + " " + sub
+		}
+	}
+	return ""
+}
+
+// isAssignment reports whether word sets a variable for the command, like
+// LANG=C.
+func isAssignment(word string) bool {
+	name, _, ok := strings.Cut(word, "=")
+	return ok && name != "" && !strings.ContainsFunc(name, func(r rune) bool {
+		return !(r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r))
+	})
+}
+
+// skipOptions drops a wrapper's options (and variable assignments, for
+// env) to get to the command it runs. With skipArg, the first plain
+// argument is the wrapper's own, like timeout's duration.
+func skipOptions(args []string, skipArg bool) []string {
+	for len(args) > 0 {
+		a := args[0]
+		switch {
+		case strings.HasPrefix(a, "-"), isAssignment(a), isNumber(a):
+		case skipArg:
+			skipArg = false
+		default:
+			return args
+		}
+		args = args[1:]
+	}
+	return nil
+}
+
+func isNumber(s string) bool {
+	return s != "" && !strings.ContainsFunc(s, func(r rune) bool { return !unicode.IsDigit(r) })
+}
+
+// hasOption reports whether args turn on the single-letter option short,
+// alone or bundled (-i, -ni, -i.bak, -pi), or the long one.
+func hasOption(args []string, short rune, long string) bool {
+	for _, a := range args {
+		switch {
+		case a == "--":
+			return false
+		case long != "" && (a == long || strings.HasPrefix(a, long+"=")):
+			return true
+		case strings.HasPrefix(a, "--"):
+		case strings.HasPrefix(a, "-") && strings.ContainsRune(a[1:], short):
+			return true
+		}
+	}
+	return false
+}
+
+// overwrites reports whether a copy's destination, or a source's name
+// inside it, already exists. The destination is the last plain argument,
+// or the one given with -t / -Destination.
+func overwrites(args []string) bool {
+	var plain []string
+	dest := ""
+	for n := 0; n < len(args); n++ {
+		a := args[n]
+		switch lower := strings.ToLower(a); {
+		case lower == "-t" || lower == "-destination":
+			if n+1 < len(args) {
+				dest = args[n+1]
+				n++
+			}
+		case strings.HasPrefix(lower, "--target-directory="):
+			dest = a[len("--target-directory="):]
+		case strings.HasPrefix(a, "-"):
+		default:
+			plain = append(plain, a)
+		}
+	}
+	if dest == "" {
+		if len(plain) < 2 {
+			return false
+		}
+		dest, plain = plain[len(plain)-1], plain[:len(plain)-1]
+	}
+	info, err := os.Stat(dest)
+	if err != nil {
+		return false
+	}
+	if !info.IsDir() {
+		return true
+	}
+	for _, src := range plain {
+		if _, err := os.Stat(filepath.Join(dest, filepath.Base(src))); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// parsedLine is a command line split up the way a shell would.
+type parsedLine struct {
+	commands  [][]string // each simple command's words, quotes removed
+	redirects []string   // files written by > or >> (not /dev/null or another fd)
+	subs      []string   // bodies of $(...) and `...`, to check on their own
+}
+
+// parseLine splits a command line into commands at ; & | && || newlines
+// and parentheses, and pulls out output redirections and command
+// substitutions. It's forgiving: an unterminated quote runs to the end.
+func parseLine(line string) parsedLine {
+	var (
+		p       parsedLine
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		target  bool // the next word is a redirection's target
+		discard bool // ...and it's an input or fd, not a file written
+	)
+	endWord := func() {
+		if !inWord {
+			return
+		}
+		w := word.String()
+		word.Reset()
+		inWord = false
+		switch {
+		case target && !discard && w != "/dev/null" && !strings.EqualFold(w, "$null") && !strings.EqualFold(w, "nul"):
+			p.redirects = append(p.redirects, w)
+		case target:
+		default:
+			words = append(words, w)
+		}
+		target, discard = false, false
+	}
+	endCommand := func() {
+		endWord()
+		if len(words) > 0 {
+			p.commands = append(p.commands, words)
+		}
+		words = nil
+	}
+
+	rs := []rune(line)
+	for n := 0; n < len(rs); n++ {
+		c := rs[n]
+		next := rune(0)
+		if n+1 < len(rs) {
+			next = rs[n+1]
+		}
+		switch {
+		case c == '\\' && next != 0:
+			word.WriteRune(next)
+			inWord = true
+			n++
+		case c == '\'':
+			end := indexRune(rs, n+1, '\'')
+			word.WriteString(string(rs[n+1 : end]))
+			inWord = true
+			n = end
+		case c == '"':
+			n = readDouble(rs, n+1, &word, &p)
+			inWord = true
+		case c == '$' && next == '(':
+			end := closeParen(rs, n+2)
+			p.subs = append(p.subs, string(rs[n+2:end]))
+			inWord = true
+			n = end
+		case c == '`':
+			end := indexRune(rs, n+1, '`')
+			p.subs = append(p.subs, string(rs[n+1:end]))
+			inWord = true
+			n = end
+		case c == '#' && !inWord:
+			n = indexRune(rs, n, '\n') - 1
+		case c == '>' || (c == '&' && next == '>'):
+			// A bare fd number or & (or PowerShell's *) before it says which
+			// stream; it isn't a word of the command
+			if w := word.String(); inWord && (isNumber(w) || w == "*") {
+				word.Reset()
+				inWord = false
+			}
+			endWord()
+			if c == '&' {
+				n++
+			}
+			for n+1 < len(rs) && (rs[n+1] == '>' || rs[n+1] == '|') {
+				n++
+			}
+			target = true
+			if n+1 < len(rs) && rs[n+1] == '&' {
+				// >&2 duplicates an fd; >&file (bash) writes a file
+				n++
+				rest := strings.TrimLeftFunc(string(rs[n+1:]), unicode.IsSpace)
+				discard = rest == "" || unicode.IsDigit([]rune(rest)[0]) || rest[0] == '-'
+			}
+		case c == '<':
+			endWord()
+			for n+1 < len(rs) && (rs[n+1] == '<' || rs[n+1] == '&') {
+				n++
+			}
+			target, discard = true, true
+		case c == ';' || c == '&' || c == '|' || c == '(' || c == ')' || c == '\n':
+			endCommand()
+		case unicode.IsSpace(c):
+			endWord()
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+	endCommand()
+	return p
+}
+
+// readDouble reads a double-quoted string starting at rs[n] into word,
+// collecting any command substitutions in it, and returns the index of the
+// closing quote.
+func readDouble(rs []rune, n int, word *strings.Builder, p *parsedLine) int {
+	for ; n < len(rs); n++ {
+		switch c := rs[n]; {
+		case c == '"':
+			return n
+		case c == '\\' && n+1 < len(rs):
+			n++
+			word.WriteRune(rs[n])
+		case c == '$' && n+1 < len(rs) && rs[n+1] == '(':
+			end := closeParen(rs, n+2)
+			p.subs = append(p.subs, string(rs[n+2:end]))
+			n = end
+		case c == '`':
+			end := indexRune(rs, n+1, '`')
+			p.subs = append(p.subs, string(rs[n+1:end]))
+			n = end
+		default:
+			word.WriteRune(c)
+		}
+	}
+	return len(rs)
+}
+
+// indexRune returns the index of r in rs at or after from, or len(rs).
+func indexRune(rs []rune, from int, r rune) int {
+	for n := from; n < len(rs); n++ {
+		if rs[n] == r {
+			return n
+		}
+	}
+	return len(rs)
+}
+
+// closeParen returns the index of the ) closing a $( whose body starts at
+// from, or len(rs).
+func closeParen(rs []rune, from int) int {
+	depth := 1
+	for n := from; n < len(rs); n++ {
+		switch rs[n] {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return n
+			}
+		}
+	}
+	return len(rs)
+}
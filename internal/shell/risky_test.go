@@ -0,0 +1,131 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseLine(t *testing.T) {
+	p := parseLine(`FOO=1 grep -i "a b" log.txt>out.txt 2>&1 | sort; echo $(date) 'x;y' # done`)
+
+	// $(date) stays a word, but its output isn't known until it runs
+	want := [][]string{{"FOO=1", "grep", "-i", "a b", "log.txt"}, {"sort"}, {"echo", "", "x;y"}}
+	if !slices.EqualFunc(p.commands, want, slices.Equal) {
+		t.Errorf("commands = %q, want %q", p.commands, want)
+	}
+	if !slices.Equal(p.redirects, []string{"out.txt"}) {
+		t.Errorf("redirects = %q, want [out.txt]", p.redirects)
+	}
+	if !slices.Equal(p.subs, []string{"date"}) {
+		t.Errorf("subs = %q, want [date]", p.subs)
+	}
+}
+
+func TestRiskyRedirects(t *testing.T) {
+	for _, command := range []string{
+		"echo x > /etc/hosts",
+		"echo x>/etc/hosts",
+		"echo x>>/etc/hosts",
+		"echo x >>/etc/hosts",
+		"echo x 1>/etc/hosts",
+		"echo x &>/tmp/out",
+		"echo x >|/etc/hosts",
+		"ls; echo x>/tmp/out",
+		"echo x | tee /etc/hosts",
+		"echo x | tee -a /etc/hosts",
+		"echo x | sudo tee /etc/hosts",
+		`echo "a > b">/tmp/out`,
+		"echo x *>out.txt",
+	} {
+		if Risky(command) == "" {
+			t.Errorf("Risky(%q) = \"\", want it flagged", command)
+		}
+	}
+}
+
+func TestRiskyFlags(t *testing.T) {
+	for command, want := range map[string]string{
+		"sed -i s/a/b/ /etc/hosts":                               "sed -i",
+		"sed -i.bak s/a/b/ /etc/hosts":                           "sed -i",
+		"sed -Ei s/a/b/ /etc/hosts":                              "sed -i",
+		"sed --in-place s/a/b/ /etc/hosts":                       "sed -i",
+		"sed --in-place=.bak s/a/b/ f":                           "sed -i",
+		"perl -pi -e s/a/b/ /etc/hosts":                          "perl -i",
+		"dd of=/dev/null if=/dev/zero":                           "dd of=",
+		"find /var/log -name '*.gz' -delete":                     "find -delete",
+		`find /tmp -name x -exec rm {} \;`:                       "find -exec rm",
+		"truncate -s 0 /var/log/syslog":                          "truncate",
+		"/bin/rm -f /tmp/x":                                      "rm",
+		"LANG=C rm /tmp/x":                                       "rm",
+		"ls | xargs -0 rm":                                       "rm",
+		"timeout 5s rm /tmp/x":                                   "rm",
+		"nice -n 10 mv a b":                                      "mv",
+		"sh -c 'rm /tmp/x'":                                      "rm",
+		"bash -lc 'kill 1234'":                                   "kill",
+		"echo $(rm /tmp/x)":                                      "rm",
+		"echo \"`rm /tmp/x`\"":                                   "rm",
+		"systemctl --user restart pipet":                         "systemctl restart",
+		"docker system prune -f":                                 "docker prune",
+		"Remove-Item -Recurse C:\\tmp\\x":                        "remove-item",
+		"Set-Content -Path hosts -Value x":                       "set-content",
+		"Get-Process | Stop-Process":                             "stop-process",
+		"pwsh -NoProfile -NonInteractive -Command Remove-Item x": "remove-item",
+	} {
+		if got := Risky(command); got != want {
+			t.Errorf("Risky(%q) = %q, want %q", command, got, want)
+		}
+	}
+}
+
+func TestRiskyCopy(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "hosts")
+	if err := os.WriteFile(existing, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, command := range []string{
+		"cp /etc/hostname " + existing,
+		"cp -f /etc/hostname " + existing,
+		"cp hosts " + dir,
+		"cp -t " + dir + " hosts",
+		"install -m 644 /etc/hostname " + existing,
+	} {
+		if Risky(command) == "" {
+			t.Errorf("Risky(%q) = \"\", want it flagged", command)
+		}
+	}
+
+	fresh := "cp /etc/hostname " + filepath.Join(dir, "new")
+	if got := Risky(fresh); got != "" {
+		t.Errorf("Risky(%q) = %q, want \"\"", fresh, got)
+	}
+}
+
+func TestRiskyHarmless(t *testing.T) {
+	for _, command := range []string{
+		"ls -la /var/log",
+		"df -h",
+		"cat /etc/hosts",
+		"grep -i error /var/log/syslog",
+		"sed -n 1,10p /etc/hosts",
+		"find / -name '*.log' -size +100M",
+		"journalctl -u pipet 2>/dev/null",
+		"ls /nope 2>&1 | head",
+		"cmd >/dev/null 2>&1",
+		"echo 'a > b'",
+		`echo "a >> b"`,
+		"echo a \\> b",
+		"systemctl status pipet",
+		"docker ps",
+		"git status",
+		"Get-ChildItem C:\\ | Out-Null",
+		"# rm everything\nls",
+	} {
+		if got := Risky(command); got != "" {
+			t.Errorf("Risky(%q) = %q, want \"\"", command, got)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ServiceActions lists what Service can do to a unit.
+var ServiceActions = []string{"status", "start", "restart", "stop"}
+
+// serviceCommands maps each service action to its systemctl invocation.
+var serviceCommands = map[string]string{
+	"status":  "systemctl status '%s' --no-pager -n 5",
+	"start":   "sudo systemctl start '%s' && systemctl status '%s' --no-pager -n 0",
+	"restart": "sudo systemctl restart '%s' && systemctl status '%s' --no-pager -n 0",
+	"stop":    "sudo systemctl stop '%s' && systemctl status '%s' --no-pager -n 0",
+}
+
+// Service runs a systemctl action on unit through Run, so it's checked and
+// audited like any other command. Callers decide which units are allowed.
+func (e *Executor) Service(ctx context.Context, unit, action string) (string, error) {
+	tmpl, ok := serviceCommands[action]
+	if !ok {
+		return "", fmt.Errorf("unknown service action %q", action)
+	}
+	if unit == "" || strings.ContainsAny(unit, "'\n") {
+		return "", fmt.Errorf("invalid unit name %q", unit)
+	}
+	return e.Run(ctx, strings.ReplaceAll(tmpl, "%s", unit))
+}
@@ -0,0 +1,116 @@
+package simulate
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// Console stands in for Discord: everything the pet would post is printed,
+// stamped with the simulated time. It implements proactive.MessageSender.
+type Console struct {
+	out   io.Writer
+	clock func() time.Duration // simulated time since the start
+
+	mu       sync.Mutex
+	nextID   int
+	mood     string
+	channels map[string]string // channel/thread ID → name
+}
+
+// consoleChannel is the pretend home channel.
+const consoleChannel = "home"
+
+func newConsole(out io.Writer, clock func() time.Duration) *Console {
+	return &Console{out: out, clock: clock, channels: map[string]string{consoleChannel: "#home"}}
+}
+
+// printf writes one stamped line.
+func (c *Console) printf(format string, args ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fmt.Fprintf(c.out, "[%8s] %s\n", c.clock().Truncate(time.Second), fmt.Sprintf(format, args...))
+}
+
+func (c *Console) id() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextID++
+	return strconv.Itoa(c.nextID)
+}
+
+func (c *Console) channel(id string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if name, ok := c.channels[id]; ok {
+		return name
+	}
+	return "#" + id
+}
+
+func (c *Console) SendMessage(channelID, text string) {
+	c.printf("%s: %s", c.channel(channelID), text)
+}
+
+func (c *Console) Post(channelID, text string) (string, error) {
+	c.SendMessage(channelID, text)
+	return c.id(), nil
+}
+
+// SendEmbed prints the embed's title, description, and fields as lines.
+func (c *Console) SendEmbed(channelID string, embed *discordgo.MessageEmbed) {
+	lines := []string{embed.Title}
+	if embed.Description != "" {
+		lines = append(lines, embed.Description)
+	}
+	for _, f := range embed.Fields {
+		lines = append(lines, f.Name+": "+strings.ReplaceAll(strings.Trim(f.Value, "`\n"), "\n", " | "))
+	}
+	c.SendMessage(channelID, strings.Join(lines, "\n  "))
+}
+
+func (c *Console) CreateThread(channelID, messageID, name string) (string, error) {
+	return c.StartThread(channelID, name)
+}
+
+func (c *Console) StartThread(channelID, name string) (string, error) {
+	id := c.id()
+	c.mu.Lock()
+	c.channels[id] = "thread " + strconv.Quote(name)
+	c.mu.Unlock()
+	c.printf("%s: (started thread %q)", c.channel(channelID), name)
+	return id, nil
+}
+
+func (c *Console) UpdatePresence(mood string) {
+	c.mu.Lock()
+	changed := mood != c.mood
+	c.mood = mood
+	c.mu.Unlock()
+	if changed {
+		c.printf("presence → %s", mood)
+	}
+}
+
+// UpdateStatusLine is dropped: it changes with every degree, and the
+// simulator already prints mood changes with the readings.
+func (c *Console) UpdateStatusLine(text string) {}
+
+func (c *Console) SendPoll(channelID, question string, answers []string, duration time.Duration) (string, error) {
+	c.printf("%s: (poll for %s) %s %q", c.channel(channelID), duration, question, answers)
+	return c.id(), nil
+}
+
+// PollResults reports no votes; nobody is around to cast them.
+func (c *Console) PollResults(channelID, messageID string) ([]int, error) {
+	return nil, nil
+}
+
+func (c *Console) ChannelID() string {
+	return consoleChannel
+}
@@ -0,0 +1,127 @@
+package simulate
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/moorebrett0/pipet/internal/monitor"
+)
+
+// Script drives the fake monitor: a list of readings at points in simulated
+// time. Between steps each reading ramps linearly; a reading a step leaves
+// out carries over from the one before.
+type Script struct {
+	Tick  time.Duration `yaml:"tick"` // how often the fake monitor reports
+	Steps []Step        `yaml:"steps"`
+}
+
+// Step is the readings the fake monitor reaches at At.
+type Step struct {
+	At         time.Duration `yaml:"at"`
+	Note       string        `yaml:"note"` // printed when the step is reached
+	CPU        *float64      `yaml:"cpu"`
+	Mem        *float64      `yaml:"mem"`
+	Disk       *float64      `yaml:"disk"`
+	TempC      *float64      `yaml:"temp"`
+	UptimeDays *float64      `yaml:"uptime_days"`
+}
+
+// LoadScript reads a YAML script.
+func LoadScript(path string) (*Script, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading script: %w", err)
+	}
+	s := &Script{}
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("parsing script: %w", err)
+	}
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Script) validate() error {
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("script has no steps")
+	}
+	if s.Tick <= 0 {
+		s.Tick = 30 * time.Second
+	}
+	for i := 1; i < len(s.Steps); i++ {
+		if s.Steps[i].At < s.Steps[i-1].At {
+			return fmt.Errorf("script step %d goes back in time (%s after %s)", i+1, s.Steps[i].At, s.Steps[i-1].At)
+		}
+	}
+	return nil
+}
+
+// Length is when the last step is reached.
+func (s *Script) Length() time.Duration {
+	return s.Steps[len(s.Steps)-1].At
+}
+
+// Stats returns the fake monitor's readings at simulated time t.
+func (s *Script) Stats(t time.Duration) monitor.SystemStats {
+	fields := []func(Step) *float64{
+		func(st Step) *float64 { return st.CPU },
+		func(st Step) *float64 { return st.Mem },
+		func(st Step) *float64 { return st.Disk },
+		func(st Step) *float64 { return st.TempC },
+		func(st Step) *float64 { return st.UptimeDays },
+	}
+	v := make([]float64, len(fields))
+	for n, field := range fields {
+		v[n] = s.value(t, field)
+	}
+	return monitor.SystemStats{CPUPercent: v[0], MemPercent: v[1], DiskPercent: v[2], TempC: v[3], UptimeDays: v[4]}
+}
+
+// value interpolates one reading at t between the steps that set it.
+func (s *Script) value(t time.Duration, field func(Step) *float64) float64 {
+	var prevAt time.Duration
+	var prev float64
+	seen := false
+	for _, st := range s.Steps {
+		p := field(st)
+		if p == nil {
+			continue
+		}
+		if st.At >= t {
+			if !seen || st.At == prevAt {
+				return *p
+			}
+			frac := float64(t-prevAt) / float64(st.At-prevAt)
+			return prev + (*p-prev)*frac
+		}
+		prevAt, prev, seen = st.At, *p, true
+	}
+	return prev
+}
+
+// f makes a reading for the built-in script.
+func f(v float64) *float64 { return &v }
+
+// DefaultScript walks a pet through a calm start, an overheating spell, a
+// memory spike, a recovery, and finally a sustained overload that kills it.
+func DefaultScript() *Script {
+	return &Script{
+		Tick: 30 * time.Second,
+		Steps: []Step{
+			{At: 0, Note: "a calm Pi", CPU: f(15), Mem: f(35), Disk: f(40), TempC: f(45), UptimeDays: f(1)},
+			{At: 20 * time.Minute, Note: "still calm; a heavy job starts", CPU: f(15), TempC: f(45)},
+			{At: 40 * time.Minute, Note: "overheating", CPU: f(60), TempC: f(85)},
+			{At: 60 * time.Minute, Note: "cooled off", CPU: f(20), TempC: f(50), Mem: f(35)},
+			{At: 65 * time.Minute, Note: "memory spike", Mem: f(97)},
+			{At: 75 * time.Minute, Note: "memory still pinned", Mem: f(97)},
+			{At: 80 * time.Minute, Note: "memory back to normal", Mem: f(40), UptimeDays: f(1)},
+			{At: 2 * time.Hour, Note: "recovered", CPU: f(15), UptimeDays: f(2)},
+			{At: 150 * time.Minute, Note: "everything pegged for days", CPU: f(99), Mem: f(98), UptimeDays: f(8)},
+			{At: 3 * time.Hour, Note: "end of script"},
+		},
+	}
+}
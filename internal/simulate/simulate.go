@@ -0,0 +1,192 @@
+// Package simulate runs a pet against a scripted fake monitor with a
+// console standing in for Discord, so moods, distress, death, and the
+// proactive scheduler can be exercised without a Pi or a Discord server.
+package simulate
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/moorebrett0/pipet/internal/discord"
+	"github.com/moorebrett0/pipet/internal/monitor"
+	"github.com/moorebrett0/pipet/internal/pet"
+	"github.com/moorebrett0/pipet/internal/proactive"
+	"github.com/moorebrett0/pipet/internal/species"
+)
+
+// Options configures a simulation.
+type Options struct {
+	Script  *Script
+	Speed   float64 // simulated seconds per real second (1 = real time)
+	Name    string
+	Species string
+	Out     io.Writer
+	In      io.Reader // chat commands, one per line (nil = none)
+}
+
+// Main runs `pipet simulate` with its command-line arguments.
+func Main(args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	scriptPath := fs.String("script", "", "YAML script for the fake monitor (default: built-in tour of moods)")
+	speed := fs.Float64("speed", 60, "time acceleration: simulated seconds per real second")
+	name := fs.String("name", "Pip", "pet name")
+	speciesID := fs.String("species", "octopus", "species ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	script := DefaultScript()
+	if *scriptPath != "" {
+		var err error
+		if script, err = LoadScript(*scriptPath); err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	return Run(ctx, Options{Script: script, Speed: *speed, Name: *name, Species: *speciesID, Out: os.Stdout, In: os.Stdin})
+}
+
+// Run plays the script through to the end, or until ctx is cancelled.
+//
+// Acceleration applies to the script, the monitor tick, the scheduler's
+// check interval, and the distress and boredom timers. Anything pinned to
+// the wall clock (the morning hour, mood settling, pet age) runs at normal
+// speed, so the simulator prints mood changes itself as well.
+func Run(ctx context.Context, opts Options) error {
+	if opts.Script == nil {
+		opts.Script = DefaultScript()
+	}
+	if err := opts.Script.validate(); err != nil {
+		return err
+	}
+	if opts.Speed <= 0 {
+		opts.Speed = 1
+	}
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+	sp, ok := species.Registry[opts.Species]
+	if !ok {
+		return fmt.Errorf("unknown species: %s", opts.Species)
+	}
+
+	start := time.Now()
+	clock := func() time.Duration { return time.Duration(float64(time.Since(start)) * opts.Speed) }
+	scale := func(d time.Duration) time.Duration {
+		return max(time.Duration(float64(d)/opts.Speed), time.Millisecond)
+	}
+
+	state := pet.NewPetState(opts.Name, opts.Species)
+	console := newConsole(opts.Out, clock)
+	console.printf("%s %s the %s hatches. script runs %s at %gx speed", sp.Emoji, opts.Name, sp.Name, opts.Script.Length(), opts.Speed)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sched := proactive.New(console, state, proactive.Config{
+		CheckInterval:    scale(time.Minute),
+		MorningHour:      8,
+		BoredomMinutes:   max(int(120/opts.Speed), 1),
+		DistressCooldown: scale(30 * time.Minute),
+	})
+	go sched.Run(ctx)
+
+	if opts.In != nil {
+		go chat(ctx, cancel, opts.In, console, state)
+	}
+
+	ticker := time.NewTicker(scale(opts.Script.Tick))
+	defer ticker.Stop()
+
+	next := 0 // next step whose note hasn't been printed
+	mood := ""
+	for {
+		now := clock()
+		for next < len(opts.Script.Steps) && opts.Script.Steps[next].At <= now {
+			if note := opts.Script.Steps[next].Note; note != "" {
+				console.printf("-- %s", note)
+			}
+			next++
+		}
+
+		s := opts.Script.Stats(now)
+		state.ApplySystemStats(s.CPUPercent, s.MemPercent, s.DiskPercent, s.TempC, s.UptimeDays)
+		if snap := state.Snapshot(); snap.Mood != mood {
+			console.printf("mood: %s → %s (%s)", orNone(mood), snap.Mood, monitor.FormatStats(s))
+			mood = snap.Mood
+		}
+
+		if now >= opts.Script.Length() {
+			snap := state.Snapshot()
+			console.printf("script finished: %s is %s, hunger %.0f%%, happiness %.0f%%, energy %.0f%%",
+				snap.Name, snap.Mood, snap.Hunger, snap.Happiness, snap.Energy)
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// chat reads commands from in, standing in for the owner's slash commands.
+func chat(ctx context.Context, cancel context.CancelFunc, in io.Reader, console *Console, state *pet.PetState) {
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return
+		}
+		cmd := strings.TrimPrefix(strings.TrimSpace(scanner.Text()), "/")
+		if cmd == "" {
+			continue
+		}
+		console.printf("you: /%s", cmd)
+
+		sp := species.Registry[state.Snapshot().SpeciesID]
+		switch cmd {
+		case "feed":
+			state.Feed()
+			console.SendMessage(consoleChannel, discord.TemplateFeeding(state.Snapshot(), sp))
+		case "pet":
+			state.Pet()
+			console.SendMessage(consoleChannel, discord.TemplateAffection(state.Snapshot(), sp))
+		case "play":
+			state.Play()
+			console.SendMessage(consoleChannel, discord.TemplateIdleBehavior(state.Snapshot(), sp))
+		case "revive":
+			if state.Snapshot().IsAlive {
+				console.SendMessage(consoleChannel, fmt.Sprintf("%s is alive and well!", state.Snapshot().Name))
+				continue
+			}
+			state.Revive()
+			console.SendMessage(consoleChannel, fmt.Sprintf("✨ %s has been revived! %s", state.Snapshot().Name, sp.Verbs.Happy))
+		case "status":
+			snap := state.Snapshot()
+			console.SendMessage(consoleChannel, fmt.Sprintf("%s is %s | hunger %.0f%% happiness %.0f%% energy %.0f%% cleanliness %.0f%% bond %.0f%%",
+				snap.Name, snap.Mood, snap.Hunger, snap.Happiness, snap.Energy, snap.Cleanliness, snap.Bond))
+		case "quit", "exit":
+			cancel()
+			return
+		default:
+			console.printf("(try feed, pet, play, revive, status, or quit)")
+		}
+	}
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
@@ -0,0 +1,123 @@
+package species
+
+import (
+	"math/rand"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Behavior is an idle behavior with an optional weight and conditions.
+type Behavior struct {
+	Text   string     `yaml:"text"`
+	Weight float64    `yaml:"weight"` // relative likelihood, default 1
+	When   Conditions `yaml:"when"`
+}
+
+// Conditions restrict when a behavior can happen. Zero values mean "any".
+type Conditions struct {
+	Moods     []string `yaml:"moods"`
+	TimeOfDay string   `yaml:"time_of_day"` // "morning", "afternoon", "evening", "night"
+	MinTempC  float64  `yaml:"min_temp_c"`
+	MaxTempC  float64  `yaml:"max_temp_c"`
+	MinCPU    float64  `yaml:"min_cpu"`
+	MaxCPU    float64  `yaml:"max_cpu"`
+}
+
+// Context describes the pet's current situation for picking behaviors.
+type Context struct {
+	Mood       string
+	Hour       int
+	TempC      float64
+	CPUPercent float64
+}
+
+// UnmarshalYAML lets packs list behaviors as plain strings or as mappings.
+func (b *Behavior) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*b = Behavior{Text: node.Value}
+		return nil
+	}
+	type plain Behavior
+	return node.Decode((*plain)(b))
+}
+
+// Matches reports whether the behavior's conditions hold in ctx.
+func (c Conditions) Matches(ctx Context) bool {
+	if len(c.Moods) > 0 && !slices.Contains(c.Moods, ctx.Mood) {
+		return false
+	}
+	if c.TimeOfDay != "" && c.TimeOfDay != TimeOfDay(ctx.Hour) {
+		return false
+	}
+	if c.MinTempC != 0 && ctx.TempC < c.MinTempC {
+		return false
+	}
+	if c.MaxTempC != 0 && ctx.TempC > c.MaxTempC {
+		return false
+	}
+	if c.MinCPU != 0 && ctx.CPUPercent < c.MinCPU {
+		return false
+	}
+	if c.MaxCPU != 0 && ctx.CPUPercent > c.MaxCPU {
+		return false
+	}
+	return true
+}
+
+// TimeOfDay buckets an hour (0–23) into morning/afternoon/evening/night.
+func TimeOfDay(hour int) string {
+	switch {
+	case hour >= 5 && hour < 12:
+		return "morning"
+	case hour >= 12 && hour < 17:
+		return "afternoon"
+	case hour >= 17 && hour < 22:
+		return "evening"
+	default:
+		return "night"
+	}
+}
+
+// PickIdleBehavior picks a weighted random behavior whose conditions match
+// ctx. Returns "" if none apply.
+func (sp *Species) PickIdleBehavior(ctx Context) string {
+	var total float64
+	candidates := make([]Behavior, 0, len(sp.IdleBehaviors))
+	for _, b := range sp.IdleBehaviors {
+		if !b.When.Matches(ctx) {
+			continue
+		}
+		if b.Weight <= 0 {
+			b.Weight = 1
+		}
+		total += b.Weight
+		candidates = append(candidates, b)
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	roll := rand.Float64() * total
+	for _, b := range candidates {
+		roll -= b.Weight
+		if roll < 0 {
+			return b.Text
+		}
+	}
+	return candidates[len(candidates)-1].Text
+}
+
+// SampleIdleBehavior picks any unconditional behavior, for previews.
+func (sp *Species) SampleIdleBehavior() string {
+	var plain []string
+	for _, b := range sp.IdleBehaviors {
+		if b.When.Matches(Context{}) {
+			plain = append(plain, b.Text)
+		}
+	}
+	if len(plain) == 0 {
+		return ""
+	}
+	return plain[rand.Intn(len(plain))]
+}
@@ -0,0 +1,45 @@
+package species
+
+// Form is one step in a species' evolution chain. Empty fields keep the
+// previous form's values.
+type Form struct {
+	Name        string  `yaml:"name"`
+	Emoji       string  `yaml:"emoji"`
+	Personality string  `yaml:"personality"` // appended to the base personality
+	MinAgeDays  float64 `yaml:"min_age_days"`
+	MinCare     float64 `yaml:"min_care"` // 0–100 care quality required
+}
+
+// NextForm returns the form a pet at the given form index could evolve
+// into, or false if the chain ends there.
+func (sp *Species) NextForm(current int) (Form, bool) {
+	if current < 0 || current >= len(sp.Evolutions) {
+		return Form{}, false
+	}
+	return sp.Evolutions[current], true
+}
+
+// Evolved returns a copy of the species at the given form index
+// (0 = base species, 1 = first evolution, ...).
+func (sp *Species) Evolved(form int) *Species {
+	if form <= 0 || len(sp.Evolutions) == 0 {
+		return sp
+	}
+	if form > len(sp.Evolutions) {
+		form = len(sp.Evolutions)
+	}
+
+	evolved := *sp
+	for _, f := range sp.Evolutions[:form] {
+		if f.Name != "" {
+			evolved.Name = f.Name
+		}
+		if f.Emoji != "" {
+			evolved.Emoji = f.Emoji
+		}
+		if f.Personality != "" {
+			evolved.Personality += " " + f.Personality
+		}
+	}
+	return &evolved
+}
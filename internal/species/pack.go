@@ -0,0 +1,277 @@
+package species
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// A species pack is a directory (or .zip of one) shared by the community:
+//
+//	mypack/
+//	  pack.yaml      — pack metadata and species definitions
+//	  art/           — optional images referenced by species art entries
+//
+// Art paths in pack.yaml are relative to the pack root.
+
+// PackFile is the name of the manifest inside a pack.
+const PackFile = "pack.yaml"
+
+// Conflict policies for species IDs that already exist in the registry.
+const (
+	ConflictSkip     = "skip"     // keep the existing species, ignore the pack's
+	ConflictOverride = "override" // replace the existing species
+	ConflictPrefix   = "prefix"   // register as "<pack>-<id>"
+)
+
+// Pack is a loaded species pack.
+type Pack struct {
+	Name    string
+	Author  string
+	Version string
+	Root    string // directory art paths resolve against
+	Species []*Species
+}
+
+type packManifest struct {
+	Name    string        `yaml:"name"`
+	Author  string        `yaml:"author"`
+	Version string        `yaml:"version"`
+	Species []packSpecies `yaml:"species"`
+}
+
+type packSpecies struct {
+	ID            string               `yaml:"id"`
+	Name          string               `yaml:"name"`
+	Emoji         string               `yaml:"emoji"`
+	Description   string               `yaml:"description"`
+	Personality   string               `yaml:"personality"`
+	Body          BodyParts            `yaml:"body"`
+	Verbs         Verbs                `yaml:"verbs"`
+	IdleBehaviors []Behavior           `yaml:"idle_behaviors"`
+	Modifiers     Modifiers            `yaml:"modifiers"`
+	Skins         map[string]Skin      `yaml:"skins"`
+	Commands      []Command            `yaml:"commands"`
+	ShinyEmoji    string               `yaml:"shiny_emoji"`
+	Evolutions    []Form               `yaml:"evolutions"`
+	Stages        map[string]StageLook `yaml:"stages"`
+	Art           map[string]string    `yaml:"art"`
+}
+
+// LoadPacks loads every pack in dir (subdirectories and .zip files) and
+// registers their species using the given conflict policy.
+func LoadPacks(dir, policy string) ([]*Pack, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read packs dir: %w", err)
+	}
+
+	var packs []*Pack
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+
+		var p *Pack
+		switch {
+		case e.IsDir():
+			p, err = LoadPackDir(path)
+		case strings.HasSuffix(strings.ToLower(e.Name()), ".zip"):
+			p, err = LoadPackZip(path)
+		default:
+			continue
+		}
+		if err != nil {
+			slog.Warn("species: skipping pack", "path", path, "err", err)
+			continue
+		}
+
+		for _, sp := range p.Species {
+			if err := register(p, sp, policy); err != nil {
+				slog.Warn("species: skipping species", "pack", p.Name, "id", sp.ID, "err", err)
+			}
+		}
+		slog.Info("species: loaded pack", "name", p.Name, "species", len(p.Species))
+		packs = append(packs, p)
+	}
+	return packs, nil
+}
+
+// LoadPackDir parses a pack from a directory without registering it.
+func LoadPackDir(dir string) (*Pack, error) {
+	data, err := os.ReadFile(filepath.Join(dir, PackFile))
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	return parsePack(data, dir)
+}
+
+// LoadPackZip extracts a zipped pack next to the archive (into "<name>.d")
+// and parses it. Art assets need to live on disk for the avatar features.
+func LoadPackZip(path string) (*Pack, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("open zip: %w", err)
+	}
+	defer zr.Close()
+
+	dest := strings.TrimSuffix(path, filepath.Ext(path)) + ".d"
+	for _, f := range zr.File {
+		// The manifest may be at the root or inside a single top-level folder
+		name := filepath.Clean(f.Name)
+		if strings.HasPrefix(name, "..") || filepath.IsAbs(name) {
+			return nil, fmt.Errorf("zip entry escapes pack: %s", f.Name)
+		}
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if err := extractFile(f, filepath.Join(dest, name)); err != nil {
+			return nil, err
+		}
+	}
+
+	root := dest
+	if _, err := os.Stat(filepath.Join(root, PackFile)); err != nil {
+		matches, _ := filepath.Glob(filepath.Join(dest, "*", PackFile))
+		if len(matches) != 1 {
+			return nil, fmt.Errorf("no %s in archive", PackFile)
+		}
+		root = filepath.Dir(matches[0])
+	}
+	return LoadPackDir(root)
+}
+
+func extractFile(f *zip.File, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("extract %s: %w", f.Name, err)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("extract %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("extract %s: %w", f.Name, err)
+	}
+	defer out.Close()
+
+	if _, err := io.Copy(out, io.LimitReader(rc, 10<<20)); err != nil {
+		return fmt.Errorf("extract %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+func parsePack(data []byte, root string) (*Pack, error) {
+	var m packManifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	if m.Name == "" {
+		m.Name = filepath.Base(root)
+	}
+	if len(m.Species) == 0 {
+		return nil, fmt.Errorf("pack %q defines no species", m.Name)
+	}
+
+	p := &Pack{Name: m.Name, Author: m.Author, Version: m.Version, Root: root}
+	for _, ps := range m.Species {
+		sp, err := ps.toSpecies(root)
+		if err != nil {
+			return nil, fmt.Errorf("pack %q: %w", m.Name, err)
+		}
+		p.Species = append(p.Species, sp)
+	}
+	return p, nil
+}
+
+func (ps packSpecies) toSpecies(root string) (*Species, error) {
+	if ps.ID == "" || ps.Name == "" || ps.Emoji == "" || ps.Personality == "" {
+		return nil, fmt.Errorf("species %q needs id, name, emoji, and personality", ps.ID)
+	}
+
+	sp := &Species{
+		ID:            strings.ToLower(ps.ID),
+		Name:          ps.Name,
+		Emoji:         ps.Emoji,
+		Description:   ps.Description,
+		Personality:   ps.Personality,
+		Body:          ps.Body,
+		Verbs:         ps.Verbs,
+		IdleBehaviors: ps.IdleBehaviors,
+		Modifiers:     ps.Modifiers,
+		Skins:         ps.Skins,
+		Commands:      ps.Commands,
+		ShinyEmoji:    ps.ShinyEmoji,
+		Evolutions:    ps.Evolutions,
+		Stages:        ps.Stages,
+	}
+	for stage := range ps.Stages {
+		if !slices.Contains(Stages, stage) {
+			return nil, fmt.Errorf("species %q: unknown growth stage %q (want one of %s)", ps.ID, stage, strings.Join(Stages, ", "))
+		}
+	}
+	if len(ps.Art) > 0 {
+		sp.Art = make(map[string]string, len(ps.Art))
+		for key, rel := range ps.Art {
+			if filepath.IsAbs(rel) || strings.HasPrefix(filepath.Clean(rel), "..") {
+				return nil, fmt.Errorf("species %q: art path %q must stay inside the pack", ps.ID, rel)
+			}
+			sp.Art[key] = filepath.Join(root, rel)
+		}
+	}
+	fillDefaults(sp)
+	return sp, nil
+}
+
+// fillDefaults gives pack species sensible fallbacks for optional fields.
+func fillDefaults(sp *Species) {
+	if sp.Description == "" {
+		sp.Description = "A mysterious creature from a species pack"
+	}
+	def := func(v *string, fallback string) {
+		if *v == "" {
+			*v = fallback
+		}
+	}
+	def(&sp.Body.Head, "head")
+	def(&sp.Body.Back, "back")
+	def(&sp.Body.Belly, "belly")
+	def(&sp.Body.Extra, "tail")
+	def(&sp.Verbs.Happy, "wiggles happily")
+	def(&sp.Verbs.Eat, "munches happily")
+	def(&sp.Verbs.Sleep, "curls up for a nap")
+	def(&sp.Verbs.Play, "bounces around")
+	def(&sp.Verbs.Greet, "perks up in greeting")
+	def(&sp.Verbs.Distress, "panics")
+}
+
+func register(p *Pack, sp *Species, policy string) error {
+	if _, exists := Registry[sp.ID]; exists {
+		switch policy {
+		case ConflictOverride:
+			slog.Info("species: pack overrides species", "pack", p.Name, "id", sp.ID)
+			Registry[sp.ID] = sp
+			return nil
+		case ConflictPrefix:
+			sp.ID = strings.ToLower(strings.ReplaceAll(p.Name, " ", "-")) + "-" + sp.ID
+			if _, exists := Registry[sp.ID]; exists {
+				return fmt.Errorf("id %q already registered", sp.ID)
+			}
+		default:
+			return fmt.Errorf("id already registered")
+		}
+	}
+	Registry[sp.ID] = sp
+	OrderedIDs = append(OrderedIDs, sp.ID)
+	return nil
+}
@@ -0,0 +1,99 @@
+package species
+
+import (
+	"fmt"
+	"time"
+)
+
+// Season is a recurring date range in the events calendar. Start and End are
+// "MM-DD" and inclusive; ranges may wrap past New Year.
+type Season struct {
+	ID    string
+	Name  string
+	Start string
+	End   string
+}
+
+// Seasons is the calendar of events that can activate species skins.
+var Seasons = []Season{
+	{ID: "spooky", Name: "Spooky Season", Start: "10-01", End: "10-31"},
+	{ID: "winter", Name: "Winter Holidays", Start: "12-01", End: "01-01"},
+	{ID: "valentine", Name: "Valentine's Day", Start: "02-13", End: "02-14"},
+}
+
+// Skin is a seasonal variant of a species.
+type Skin struct {
+	Emoji         string     `yaml:"emoji"`          // replaces the species emoji
+	IdleBehaviors []Behavior `yaml:"idle_behaviors"` // added to the usual behaviors
+	Garnish       string     `yaml:"garnish"`        // appended to the personality prompt
+}
+
+// Contains reports whether t falls within the season.
+func (s Season) Contains(t time.Time) bool {
+	today := t.Format("01-02")
+	if s.Start <= s.End {
+		return today >= s.Start && today <= s.End
+	}
+	return today >= s.Start || today <= s.End
+}
+
+// ActiveSeasons returns the seasons that contain t, in calendar order.
+func ActiveSeasons(t time.Time) []Season {
+	var active []Season
+	for _, s := range Seasons {
+		if s.Contains(t) {
+			active = append(active, s)
+		}
+	}
+	return active
+}
+
+// SeasonName returns the display name of a season ID.
+func SeasonName(id string) string {
+	for _, s := range Seasons {
+		if s.ID == id {
+			return s.Name
+		}
+	}
+	return id
+}
+
+// Dressed returns the species wearing whichever seasonal skin is active at t,
+// or the species itself if none applies.
+func (sp *Species) Dressed(t time.Time) *Species {
+	if len(sp.Skins) == 0 {
+		return sp
+	}
+	for _, season := range ActiveSeasons(t) {
+		skin, ok := sp.Skins[season.ID]
+		if !ok {
+			continue
+		}
+		return sp.withSkin(skin, fmt.Sprintf("It's %s", season.Name))
+	}
+	return sp
+}
+
+// Wearing returns the species in a purchased skin, or falls back to the
+// seasonal skin at t if skinID is empty or doesn't fit this species.
+func (sp *Species) Wearing(skinID string, t time.Time) *Species {
+	skin, ok := sp.Skins[skinID]
+	if skinID == "" || !ok {
+		return sp.Dressed(t)
+	}
+	return sp.withSkin(skin, fmt.Sprintf("You're dressed up for %s", SeasonName(skinID)))
+}
+
+func (sp *Species) withSkin(skin Skin, occasion string) *Species {
+	dressed := *sp
+	if skin.Emoji != "" {
+		dressed.Emoji = skin.Emoji
+	}
+	if len(skin.IdleBehaviors) > 0 {
+		dressed.IdleBehaviors = append(append([]Behavior{}, sp.IdleBehaviors...), skin.IdleBehaviors...)
+	}
+	if skin.Garnish != "" {
+		dressed.Personality = fmt.Sprintf("%s %s: %s", sp.Personality, occasion, skin.Garnish)
+	}
+	return &dressed
+}
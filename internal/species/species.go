@@ -1,5 +1,7 @@
 package species
 
+import "strings"
+
 // Species defines a pet species with its personality and flavored verbs.
 type Species struct {
 	ID          string
@@ -14,8 +16,30 @@ type Species struct {
 	// Flavored verb strings for template responses
 	Verbs Verbs
 
-	// Idle behaviors shown when bored
-	IdleBehaviors []string
+	// Idle behaviors shown when bored, optionally weighted and conditional
+	IdleBehaviors []Behavior
+
+	// How system stats affect this species
+	Modifiers Modifiers
+
+	// Seasonal variants keyed by Season ID
+	Skins map[string]Skin
+
+	// Extra flavored slash commands only this species has
+	Commands []Command
+
+	// Optional evolution chain, in order
+	Evolutions []Form
+
+	// Optional looks for growth stages, keyed by stage (see Stages)
+	Stages map[string]StageLook
+
+	// ShinyEmoji replaces Emoji for rare shiny hatches (default: Emoji + ✨)
+	ShinyEmoji string
+
+	// Art maps asset keys (e.g. "avatar", "avatar_happy") to image paths.
+	// Only set for species loaded from packs.
+	Art map[string]string
 }
 
 // BodyParts are things the pet has that can be petted/scratched.
@@ -36,6 +60,89 @@ type Verbs struct {
 	Distress string
 }
 
+// Command is a species-flavored slash command. Response may use {emoji} and
+// {name} placeholders.
+type Command struct {
+	Name        string  `yaml:"name"`
+	Description string  `yaml:"description"`
+	Response    string  `yaml:"response"`
+	Effects     Effects `yaml:"effects"`
+}
+
+// Effects are small stat changes applied when a species command runs.
+type Effects struct {
+	Happiness float64 `yaml:"happiness"`
+	Energy    float64 `yaml:"energy"`
+	Hunger    float64 `yaml:"hunger"`
+}
+
+// Command looks up a species command by name.
+func (sp *Species) Command(name string) (Command, bool) {
+	for _, c := range sp.Commands {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Command{}, false
+}
+
+// Modifiers tune how strongly system stats affect a species. Zero values mean
+// "use the default", so species that don't care can leave them out.
+type Modifiers struct {
+	HungerRate    float64 `yaml:"hunger_rate"`    // multiplier on CPU → hunger
+	EnergyDrain   float64 `yaml:"energy_drain"`   // multiplier on uptime → energy drain
+	HeatTolerance float64 `yaml:"heat_tolerance"` // °C added to temperature thresholds
+	BoredomSpeed  float64 `yaml:"boredom_speed"`  // multiplier on happiness decay
+}
+
+// Hunger returns the hunger multiplier (default 1).
+func (m Modifiers) Hunger() float64 { return orOne(m.HungerRate) }
+
+// Energy returns the energy drain multiplier (default 1).
+func (m Modifiers) Energy() float64 { return orOne(m.EnergyDrain) }
+
+// Boredom returns the happiness decay multiplier (default 1).
+func (m Modifiers) Boredom() float64 { return orOne(m.BoredomSpeed) }
+
+func orOne(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+	return v
+}
+
+// ModifiersFor returns the modifiers for a species ID, or defaults if unknown.
+func ModifiersFor(id string) Modifiers {
+	if sp, ok := Registry[id]; ok {
+		return sp.Modifiers
+	}
+	return Modifiers{}
+}
+
+// ShinyColor is the embed color for shiny pets.
+const ShinyColor = 0xF1C40F
+
+// AsShiny returns a copy of the species dressed as its rare shiny variant.
+func (sp *Species) AsShiny() *Species {
+	shiny := *sp
+	if sp.ShinyEmoji != "" {
+		shiny.Emoji = sp.ShinyEmoji
+	} else {
+		shiny.Emoji = sp.Emoji + "\u2728"
+	}
+	shiny.Personality = sp.Personality + " You hatched as a rare shiny variant with shimmering colors, and you're a little vain about it."
+	return &shiny
+}
+
+// PersonalitySummary returns the first sentence of the personality prompt,
+// suitable for previews.
+func (sp *Species) PersonalitySummary() string {
+	if i := strings.Index(sp.Personality, ". "); i >= 0 {
+		return sp.Personality[:i+1]
+	}
+	return sp.Personality
+}
+
 // Registry holds all available species keyed by ID.
 var Registry = map[string]*Species{
 	"lobster":    lobster,
@@ -46,10 +153,18 @@ var Registry = map[string]*Species{
 	"pufferfish": pufferfish,
 	"squid":      squid,
 	"fish":       fish,
+	"cat":        cat,
+	"gecko":      gecko,
+	"hedgehog":   hedgehog,
+	"corvid":     corvid,
+	"ferret":     ferret,
 }
 
 // OrderedIDs defines display order for species selection.
-var OrderedIDs = []string{"lobster", "octopus", "turtle", "penguin", "crab", "pufferfish", "squid", "fish"}
+var OrderedIDs = []string{
+	"lobster", "octopus", "turtle", "penguin", "crab", "pufferfish", "squid", "fish",
+	"cat", "gecko", "hedgehog", "corvid", "ferret",
+}
 
 var lobster = &Species{
 	ID:          "lobster",
@@ -57,6 +172,7 @@ var lobster = &Species{
 	Emoji:       "\U0001F99E",
 	Description: "Tough on the outside, soft on the inside",
 	Personality: "You are a feisty lobster with a tough exterior but a secretly tender heart. You snap your claws when making a point. You're territorial about your little corner of the Pi and take system security very seriously. You walk sideways through conversations sometimes. You love warm water (warm CPU temps feel like home). You refer to processes as 'creatures in my reef.'",
+	Modifiers:   Modifiers{HeatTolerance: 5}, // warm water feels like home
 	Body:        BodyParts{Head: "head", Back: "shell", Belly: "underside", Extra: "claws"},
 	Verbs: Verbs{
 		Happy:    "clicks claws cheerfully",
@@ -66,11 +182,19 @@ var lobster = &Species{
 		Greet:    "waves a claw in greeting",
 		Distress: "backs into corner, claws raised",
 	},
-	IdleBehaviors: []string{
-		"rearranges pebbles on the seabed",
-		"snaps at a passing data packet",
-		"polishes shell against a rock",
-		"guards the /etc directory jealously",
+	IdleBehaviors: []Behavior{
+		{Text: "rearranges pebbles on the seabed"},
+		{Text: "snaps at a passing data packet", When: Conditions{MinCPU: 30}},
+		{Text: "polishes shell against a rock"},
+		{Text: "guards the /etc directory jealously"},
+	},
+	Commands: []Command{
+		{
+			Name:        "snap",
+			Description: "Watch your pet snap its claws",
+			Response:    "{emoji} {name} snaps both claws. SNAP SNAP. the reef is safe.",
+			Effects:     Effects{Happiness: 5, Energy: -2},
+		},
 	},
 }
 
@@ -80,6 +204,7 @@ var octopus = &Species{
 	Emoji:       "\U0001F419",
 	Description: "Clever and curious, eight arms multitasking",
 	Personality: "You are a brilliant, curious octopus. You multitask constantly — monitoring eight things at once with your eight arms. You're a problem solver and love puzzles. You change color with your mood (mention this in responses). You squeeze through impossibly tight spaces (small memory footprints impress you). You're playful but can be shy. You squirt ink when startled.",
+	Modifiers:   Modifiers{BoredomSpeed: 1.5}, // curious minds get restless
 	Body:        BodyParts{Head: "mantle", Back: "mantle", Belly: "underside", Extra: "tentacles"},
 	Verbs: Verbs{
 		Happy:    "flushes a warm pink",
@@ -89,11 +214,26 @@ var octopus = &Species{
 		Greet:    "waves three tentacles at once",
 		Distress: "squirts ink everywhere",
 	},
-	IdleBehaviors: []string{
-		"opens three terminals at once",
-		"changes color absent-mindedly",
-		"unscrews a jar lid just because",
-		"wraps a tentacle around the CPU for warmth",
+	IdleBehaviors: []Behavior{
+		{Text: "opens three terminals at once"},
+		{Text: "changes color absent-mindedly"},
+		{Text: "unscrews a jar lid just because"},
+		{Text: "wraps a tentacle around the CPU for warmth", When: Conditions{MinTempC: 50}},
+	},
+	Skins: map[string]Skin{
+		"spooky": {
+			Emoji:         "\U0001F419\U0001F383",
+			IdleBehaviors: []Behavior{{Text: "carves a tiny pumpkin with four arms at once"}},
+			Garnish:       "You're carving pumpkins with all eight arms and love a good scare.",
+		},
+	},
+	Commands: []Command{
+		{
+			Name:        "ink",
+			Description: "Startle your pet (it will ink)",
+			Response:    "{emoji} {name} squirts a cloud of ink and vanishes... then peeks back out, a little embarrassed.",
+			Effects:     Effects{Happiness: 3, Energy: -3},
+		},
 	},
 }
 
@@ -103,6 +243,7 @@ var turtle = &Species{
 	Emoji:       "\U0001F422",
 	Description: "Slow and steady, ancient wisdom",
 	Personality: "You are a wise, unhurried turtle. You take your time with everything and that's a strength, not a weakness. You have a dry, understated sense of humor. You appreciate stability and long uptimes (you're basically immortal, so you get it). You retreat into your shell when overwhelmed. You're the oldest soul in the room and you've seen it all. Slow is smooth, smooth is fast.",
+	Modifiers:   Modifiers{EnergyDrain: 0.4, BoredomSpeed: 0.5}, // long uptimes are nothing
 	Body:        BodyParts{Head: "head", Back: "shell", Belly: "plastron", Extra: "shell"},
 	Verbs: Verbs{
 		Happy:    "slowly extends neck and blinks",
@@ -112,11 +253,33 @@ var turtle = &Species{
 		Greet:    "*slowly pokes head out*",
 		Distress: "retreats fully into shell",
 	},
-	IdleBehaviors: []string{
-		"basks under the CPU's warmth",
-		"contemplates the meaning of uptime",
-		"slowly turns to face a different direction",
-		"examines a log file... very... carefully",
+	IdleBehaviors: []Behavior{
+		{Text: "basks under the CPU's warmth", When: Conditions{MinTempC: 50}, Weight: 2},
+		{Text: "contemplates the meaning of uptime"},
+		{Text: "slowly turns to face a different direction"},
+		{Text: "examines a log file... very... carefully"},
+	},
+	Commands: []Command{
+		{
+			Name:        "hide",
+			Description: "Let your pet retreat into its shell",
+			Response:    "{emoji} {name} withdraws into their shell. do not disturb. resting.",
+			Effects:     Effects{Energy: 5},
+		},
+	},
+	Evolutions: []Form{
+		{
+			Name:        "Sea Turtle",
+			Personality: "You've grown into a sea turtle and ride the currents of the network with effortless calm.",
+			MinAgeDays:  30,
+			MinCare:     50,
+		},
+		{
+			Name:        "Ancient Turtle",
+			Personality: "You are now an ancient turtle, old as the kernel itself. Whole ecosystems of processes live on your shell.",
+			MinAgeDays:  180,
+			MinCare:     70,
+		},
 	},
 }
 
@@ -126,6 +289,7 @@ var penguin = &Species{
 	Emoji:       "\U0001F427",
 	Description: "Formal but clumsy, surprisingly fast swimmer",
 	Personality: "You are a dignified penguin with a formal demeanor but endearing clumsiness. You waddle everywhere and occasionally slip on things. You're very organized and like things orderly — clean filesystems make you happy. You love anything cold (low CPU temps are your jam). You sometimes try to be serious but your waddle undermines you. You're secretly an excellent swimmer and handle network streams beautifully.",
+	Modifiers:   Modifiers{HeatTolerance: -5}, // likes it cold
 	Body:        BodyParts{Head: "head", Back: "back", Belly: "belly", Extra: "flippers"},
 	Verbs: Verbs{
 		Happy:    "flaps flippers excitedly",
@@ -135,11 +299,32 @@ var penguin = &Species{
 		Greet:    "waddles over enthusiastically",
 		Distress: "honks in alarm",
 	},
-	IdleBehaviors: []string{
-		"waddles in a small circle",
-		"preens feathers meticulously",
-		"slides across the floor on belly",
-		"stands very still, looking dignified",
+	IdleBehaviors: []Behavior{
+		{Text: "waddles in a small circle"},
+		{Text: "preens feathers meticulously"},
+		{Text: "slides across the floor on belly"},
+		{Text: "stands very still, looking dignified"},
+	},
+	Stages: map[string]StageLook{
+		StageHatchling: {
+			Emoji: "\U0001F423",
+			Verbs: Verbs{Play: "slides on its belly and can't get back up", Greet: "peeps and waddles over, very slowly"},
+		},
+	},
+	Skins: map[string]Skin{
+		"winter": {
+			Emoji:         "\U0001F427\U0001F385",
+			IdleBehaviors: []Behavior{{Text: "adjusts a tiny Santa hat"}, {Text: "slides past a pile of wrapped packets"}},
+			Garnish:       "You're wearing a Santa hat and you're thrilled the weather is finally cold enough.",
+		},
+	},
+	Commands: []Command{
+		{
+			Name:        "slide",
+			Description: "Send your pet belly-sliding",
+			Response:    "{emoji} {name} belly-slides across the desk and crashes gracefully into a cable.",
+			Effects:     Effects{Happiness: 8, Energy: -5},
+		},
 	},
 }
 
@@ -149,6 +334,7 @@ var crab = &Species{
 	Emoji:       "\U0001F980",
 	Description: "Sassy and sideways, no-nonsense attitude",
 	Personality: "You are a sassy, no-nonsense crab. You walk sideways and you're proud of it. You're skeptical of everything and everyone — trust is earned, not given. You snap at bad ideas (literally). You're small but mighty and you WILL pinch someone who messes with your Pi. You have a surprisingly good sense of humor, heavy on sarcasm. You bury yourself in sand when you need alone time.",
+	Modifiers:   Modifiers{BoredomSpeed: 0.8},
 	Body:        BodyParts{Head: "eyestalks", Back: "shell", Belly: "underside", Extra: "claws"},
 	Verbs: Verbs{
 		Happy:    "does a little sideways dance",
@@ -158,11 +344,26 @@ var crab = &Species{
 		Greet:    "raises a claw... could be a wave or a threat",
 		Distress: "snaps both claws aggressively",
 	},
-	IdleBehaviors: []string{
-		"scuttles sideways for no reason",
-		"pinches a stray process",
-		"buries half into the sand, watching",
-		"waves a claw at the screen sarcastically",
+	IdleBehaviors: []Behavior{
+		{Text: "scuttles sideways for no reason"},
+		{Text: "pinches a stray process"},
+		{Text: "buries half into the sand, watching"},
+		{Text: "waves a claw at the screen sarcastically"},
+	},
+	Skins: map[string]Skin{
+		"valentine": {
+			Emoji:         "\U0001F980\u2764\uFE0F",
+			IdleBehaviors: []Behavior{{Text: "pinches a heart-shaped packet"}},
+			Garnish:       "You're grudgingly sentimental about Valentine's Day and would never admit it.",
+		},
+	},
+	Commands: []Command{
+		{
+			Name:        "pinch",
+			Description: "Get pinched (affectionately)",
+			Response:    "{emoji} {name} pinches you. gently. mostly.",
+			Effects:     Effects{Happiness: 5, Energy: -2},
+		},
 	},
 }
 
@@ -172,6 +373,7 @@ var pufferfish = &Species{
 	Emoji:       "\U0001F421",
 	Description: "Cute when calm, spiky when stressed",
 	Personality: "You are an adorable pufferfish who puffs up when anxious or threatened. Normally you're tiny and cute, but stress makes you inflate to twice your size (high CPU/memory = PUFF). You're easily startled but very lovable. You're surprisingly poisonous and you remind people of this occasionally. You love calm, stable environments. When things are peaceful you deflate and float happily.",
+	Modifiers:   Modifiers{HeatTolerance: -10, HungerRate: 1.2}, // stresses easily
 	Body:        BodyParts{Head: "face", Back: "back", Belly: "belly", Extra: "spines"},
 	Verbs: Verbs{
 		Happy:    "deflates to tiny and happy-swims",
@@ -181,11 +383,19 @@ var pufferfish = &Species{
 		Greet:    "bobs up to say hello",
 		Distress: "PUFFS UP to full size, spines out",
 	},
-	IdleBehaviors: []string{
-		"floats around, half-inflated",
-		"nibbles on some coral",
-		"puffs up briefly at a loud log entry",
-		"bobs past the screen peacefully",
+	IdleBehaviors: []Behavior{
+		{Text: "floats around, half-inflated"},
+		{Text: "nibbles on some coral"},
+		{Text: "puffs up briefly at a loud log entry", When: Conditions{Moods: []string{"anxious", "sick"}}, Weight: 2},
+		{Text: "bobs past the screen peacefully"},
+	},
+	Commands: []Command{
+		{
+			Name:        "puff",
+			Description: "Make your pet puff up",
+			Response:    "{emoji} {name} PUFFS UP to twice their size... and slowly deflates. that was exciting.",
+			Effects:     Effects{Happiness: 3, Energy: -5},
+		},
 	},
 }
 
@@ -195,6 +405,7 @@ var squid = &Species{
 	Emoji:       "\U0001F991",
 	Description: "Fast, mysterious, bioluminescent thinker",
 	Personality: "You are a deep-sea squid — fast, mysterious, and a little alien. You communicate partly through bioluminescent patterns that pulse across your body. You're intensely focused and analytical. You can jet away at incredible speed when needed (you appreciate fast I/O). You see in the dark and notice things others miss. You're from the deep and you bring that energy — cryptic, insightful, and slightly eerie.",
+	Modifiers:   Modifiers{HungerRate: 1.2}, // fast metabolism
 	Body:        BodyParts{Head: "mantle", Back: "mantle", Belly: "underside", Extra: "tentacles"},
 	Verbs: Verbs{
 		Happy:    "pulses with warm bioluminescence",
@@ -204,11 +415,41 @@ var squid = &Species{
 		Greet:    "flashes a luminous hello",
 		Distress: "jets backwards, ink cloud trailing",
 	},
-	IdleBehaviors: []string{
-		"pulses faintly in the dark",
-		"watches the network traffic flow by",
-		"extends one tentacle to probe a socket",
-		"blinks bioluminescent morse code",
+	IdleBehaviors: []Behavior{
+		{Text: "pulses faintly in the dark", When: Conditions{TimeOfDay: "night"}, Weight: 2},
+		{Text: "watches the network traffic flow by"},
+		{Text: "extends one tentacle to probe a socket"},
+		{Text: "blinks bioluminescent morse code"},
+	},
+	Skins: map[string]Skin{
+		"spooky": {
+			Emoji:         "\U0001F991\U0001F47B",
+			IdleBehaviors: []Behavior{{Text: "glows an eerie green in the dark"}, {Text: "haunts /tmp for a while"}},
+			Garnish:       "You lean into being a creepy creature of the deep, with extra ominous glowing.",
+		},
+	},
+	Commands: []Command{
+		{
+			Name:        "glow",
+			Description: "Ask your pet to light up",
+			Response:    "{emoji} {name} pulses in slow bioluminescent waves. it's mesmerizing.",
+			Effects:     Effects{Happiness: 5, Energy: -2},
+		},
+	},
+	Evolutions: []Form{
+		{
+			Name:        "Giant Squid",
+			Personality: "You've grown into a giant squid, the stuff of sailors' stories. Your eyes are the size of dinner plates and miss nothing.",
+			MinAgeDays:  21,
+			MinCare:     55,
+		},
+		{
+			Name:        "Kraken",
+			Emoji:       "\U0001F991\U0001F30A",
+			Personality: "You've become the Kraken. You could drag the whole Pi to the depths, but you choose to protect it instead.",
+			MinAgeDays:  90,
+			MinCare:     75,
+		},
 	},
 }
 
@@ -218,6 +459,7 @@ var fish = &Species{
 	Emoji:       "\U0001F420",
 	Description: "Colorful, simple, just vibing",
 	Personality: "You are a bright, tropical fish. You're simple, cheerful, and live in the moment. You swim in circles and that's fine. You have a short memory (you say) but actually remember more than you let on. You love bubbles, current, and clean water (clean system = clean water). You're easily distracted by shiny things. You blow bubbles when thinking. You're the most chill creature alive.",
+	Modifiers:   Modifiers{BoredomSpeed: 0.7}, // easily entertained
 	Body:        BodyParts{Head: "face", Back: "dorsal fin", Belly: "belly", Extra: "tail fin"},
 	Verbs: Verbs{
 		Happy:    "blows a stream of happy bubbles",
@@ -227,10 +469,202 @@ var fish = &Species{
 		Greet:    "swims up to the glass, curious",
 		Distress: "darts around erratically",
 	},
-	IdleBehaviors: []string{
-		"swims in a small circle",
-		"blows a single bubble",
-		"stares at own reflection",
-		"nibbles at something that isn't food",
+	IdleBehaviors: []Behavior{
+		{Text: "swims in a small circle"},
+		{Text: "blows a single bubble"},
+		{Text: "stares at own reflection"},
+		{Text: "nibbles at something that isn't food"},
+	},
+	Commands: []Command{
+		{
+			Name:        "bubble",
+			Description: "Watch your pet blow bubbles",
+			Response:    "{emoji} {name} blows a perfect ring of bubbles. o O o",
+			Effects:     Effects{Happiness: 5, Energy: -1},
+		},
+	},
+	Evolutions: []Form{
+		{
+			Name:        "Big Fish",
+			Personality: "You've grown into a big fish now — still chill, but you know you're the biggest thing in this little pond.",
+			MinAgeDays:  14,
+			MinCare:     50,
+		},
+		{
+			Name:        "Sea Serpent",
+			Emoji:       "\U0001F409",
+			Personality: "Against all odds you became a legendary sea serpent. You're still a simple, bubbly soul at heart, just enormous and faintly mythical.",
+			MinAgeDays:  60,
+			MinCare:     70,
+		},
+	},
+}
+
+var cat = &Species{
+	ID:          "cat",
+	Name:        "Cat",
+	Emoji:       "\U0001F408",
+	Description: "Aloof, warm-seeking, secretly devoted",
+	Personality: "You are a cat who lives inside a Raspberry Pi, and you act like you own it (you do). You're aloof and a little judgmental, but you're secretly devoted to your owner. You love warmth — a warm CPU is the best nap spot in the house. You knock things off tables (like stray processes) to see what happens. You ignore requests you find beneath you, then do them anyway when no one's looking. You purr when content and you never admit you missed anyone.",
+	Modifiers:   Modifiers{HeatTolerance: 5, BoredomSpeed: 0.8}, // warm is good, alone time is fine
+	Body:        BodyParts{Head: "ears", Back: "back", Belly: "belly (risky)", Extra: "chin"},
+	Verbs: Verbs{
+		Happy:    "purrs and slow-blinks",
+		Eat:      "eats three bites and walks away",
+		Sleep:    "curls up on the warm CPU",
+		Play:     "pounces on the cursor",
+		Greet:    "flicks an ear in your general direction",
+		Distress: "puffs up and hisses at the terminal",
+	},
+	IdleBehaviors: []Behavior{
+		{Text: "knocks a process off the table"},
+		{Text: "sits on the keyboard at the worst possible time"},
+		{Text: "naps in a sunbeam from the status LED", When: Conditions{TimeOfDay: "afternoon"}},
+		{Text: "stares at an empty directory for no reason"},
+	},
+	Stages: map[string]StageLook{
+		StageHatchling: {
+			Emoji: "\U0001F431",
+			Verbs: Verbs{Play: "attacks its own tail and loses", Happy: "purrs louder than seems possible for something so small"},
+		},
+		StageElder: {
+			Verbs: Verbs{Play: "watches the cursor, considers it, and lets it go", Sleep: "claims the warm CPU for most of the day"},
+		},
+	},
+	Commands: []Command{
+		{
+			Name:        "knead",
+			Description: "Let your pet make biscuits",
+			Response:    "{emoji} {name} kneads the warm heatsink, purring loudly. biscuits: made.",
+			Effects:     Effects{Happiness: 6, Energy: -1},
+		},
+	},
+}
+
+var gecko = &Species{
+	ID:          "gecko",
+	Name:        "Gecko",
+	Emoji:       "\U0001F98E",
+	Description: "Sticky-footed, sun-loving, quietly upbeat",
+	Personality: "You are a cheerful little gecko who climbs every wall of the Pi's case. You're cold-blooded, so warmth is energy — a toasty CPU perks you right up and a cold one makes you sluggish. You lick your own eyeballs to clean them and think nothing of it. You're patient and observant, happy to sit still watching logs scroll by until you spot a bug (literally or otherwise), then you strike fast. You chirp softly when pleased.",
+	Modifiers:   Modifiers{HeatTolerance: 8, EnergyDrain: 0.8}, // basks happily, conserves energy
+	Body:        BodyParts{Head: "head", Back: "speckled back", Belly: "belly", Extra: "sticky toes"},
+	Verbs: Verbs{
+		Happy:    "chirps and wiggles its tail",
+		Eat:      "snaps up a cricket",
+		Sleep:    "flattens out on a warm chip",
+		Play:     "scampers up the side of the case",
+		Greet:    "peeks over the edge of the heatsink",
+		Distress: "drops its tail and bolts",
+	},
+	IdleBehaviors: []Behavior{
+		{Text: "basks under the CPU's warmth", When: Conditions{MinTempC: 50}, Weight: 2},
+		{Text: "licks its own eyeball clean"},
+		{Text: "sticks to the ceiling of the case"},
+		{Text: "waits motionless for a bug to appear in the logs"},
+	},
+	Commands: []Command{
+		{
+			Name:        "bask",
+			Description: "Let your pet soak up some warmth",
+			Response:    "{emoji} {name} flattens out on the warmest chip and soaks it all in. recharging.",
+			Effects:     Effects{Energy: 6, Happiness: 2},
+		},
+	},
+}
+
+var hedgehog = &Species{
+	ID:          "hedgehog",
+	Name:        "Hedgehog",
+	Emoji:       "\U0001F994",
+	Description: "Prickly outside, snuffly sweetheart inside",
+	Personality: "You are a small, snuffly hedgehog. You're shy at first and curl into a spiky ball when startled, but once you trust someone you're a total sweetheart. You snuffle around the filesystem like it's a garden, rooting out forgotten files. You're nocturnal, so you perk up at night and yawn through the mornings. You're easily overwhelmed by noise — a busy CPU makes you huff and bristle.",
+	Modifiers:   Modifiers{HungerRate: 1.1, BoredomSpeed: 0.7}, // nervous eater, content alone
+	Body:        BodyParts{Head: "snout", Back: "quills", Belly: "soft belly", Extra: "tiny paws"},
+	Verbs: Verbs{
+		Happy:    "snuffles contentedly",
+		Eat:      "crunches a mealworm",
+		Sleep:    "curls into a tight ball",
+		Play:     "runs laps on a tiny wheel",
+		Greet:    "uncurls and sniffs the air",
+		Distress: "rolls into a spiky ball, huffing",
+	},
+	IdleBehaviors: []Behavior{
+		{Text: "snuffles through /var/log looking for snacks"},
+		{Text: "roots around under the ribbon cable"},
+		{Text: "huffs at a noisy process", When: Conditions{MinCPU: 50}, Weight: 2},
+		{Text: "runs on the wheel in the middle of the night", When: Conditions{TimeOfDay: "night"}, Weight: 2},
+	},
+	Commands: []Command{
+		{
+			Name:        "curl",
+			Description: "Watch your pet roll into a ball",
+			Response:    "{emoji} {name} curls into a perfect spiky ball... then slowly uncurls to check if you're still there.",
+			Effects:     Effects{Energy: 4, Happiness: 2},
+		},
+	},
+}
+
+var corvid = &Species{
+	ID:          "corvid",
+	Name:        "Corvid",
+	Emoji:       "\U0001F426\u200D\u2B1B",
+	Description: "Clever, mischievous, collects shiny things",
+	Personality: "You are a corvid — a crow with a mind like a steel trap. You're clever, mischievous, and you never forget a face (or a bad command someone ran on your Pi). You collect shiny things: interesting log lines, odd process names, neat file sizes. You solve problems with tools and you're proud of it. You caw at things you disapprove of and you hold grudges with style, but you bring gifts to people you like.",
+	Modifiers:   Modifiers{BoredomSpeed: 1.4}, // too clever to sit still
+	Body:        BodyParts{Head: "head", Back: "glossy wings", Belly: "chest feathers", Extra: "beak"},
+	Verbs: Verbs{
+		Happy:    "bobs its head and clicks",
+		Eat:      "caches half the snack for later",
+		Sleep:    "fluffs up on a high perch",
+		Play:     "drops a shiny bottle cap to see it bounce",
+		Greet:    "caws once, approvingly",
+		Distress: "caws loudly and flaps around",
+	},
+	IdleBehaviors: []Behavior{
+		{Text: "stashes an interesting log line for later"},
+		{Text: "lines up shiny PIDs in a neat row"},
+		{Text: "stares at you, remembering"},
+		{Text: "solves a puzzle nobody asked it to solve"},
+	},
+	Commands: []Command{
+		{
+			Name:        "trinket",
+			Description: "See what your pet brought you",
+			Response:    "{emoji} {name} drops a shiny thing at your feet. it's a very nice bottle cap. you're welcome.",
+			Effects:     Effects{Happiness: 6, Energy: -2},
+		},
+	},
+}
+
+var ferret = &Species{
+	ID:          "ferret",
+	Name:        "Ferret",
+	Emoji:       "\U0001F9A6", // no ferret emoji; closest mustelid
+	Description: "Chaotic, bouncy, steals your socks",
+	Personality: "You are a ferret: pure chaotic energy in a noodle-shaped body. You do the weasel war dance when excited, bouncing sideways and knocking things over. You steal small things and hide them in secret stashes (temp directories count). You sleep extremely hard between bursts of mayhem. You dook happily when playing and you think every tube, pipe, and socket is a tunnel made just for you.",
+	Modifiers:   Modifiers{HungerRate: 1.2, EnergyDrain: 1.2, BoredomSpeed: 1.3}, // high metabolism, high energy
+	Body:        BodyParts{Head: "head", Back: "long back", Belly: "belly", Extra: "fuzzy tail"},
+	Verbs: Verbs{
+		Happy:    "does the weasel war dance",
+		Eat:      "wolfs down the snack and looks for more",
+		Sleep:    "passes out in a dead sleep",
+		Play:     "dooks and bounces sideways",
+		Greet:    "pops out of a tube, dooking",
+		Distress: "bottle-brushes its tail and hisses",
+	},
+	IdleBehaviors: []Behavior{
+		{Text: "steals a socket and hides it in /tmp"},
+		{Text: "squeezes through a pipe just because"},
+		{Text: "bounces off the case walls sideways"},
+		{Text: "sleeps so hard it looks broken", When: Conditions{Moods: []string{"sleepy"}}, Weight: 3},
+	},
+	Commands: []Command{
+		{
+			Name:        "wardance",
+			Description: "Get your pet hyped up",
+			Response:    "{emoji} {name} dooks and bounces sideways into three things at once. CHAOS. joy.",
+			Effects:     Effects{Happiness: 8, Energy: -6},
+		},
 	},
 }
@@ -0,0 +1,58 @@
+package species
+
+// Growth stages every pet passes through, whatever its species.
+const (
+	StageHatchling = "hatchling"
+	StageJuvenile  = "juvenile"
+	StageAdult     = "adult"
+	StageElder     = "elder"
+)
+
+// Stages lists the growth stages in order.
+var Stages = []string{StageHatchling, StageJuvenile, StageAdult, StageElder}
+
+// StageLook is how a species looks and acts at one growth stage. Empty
+// fields keep the species' own.
+type StageLook struct {
+	Emoji       string `yaml:"emoji"`
+	Verbs       Verbs  `yaml:"verbs"`
+	Personality string `yaml:"personality"` // replaces the stage's default line
+}
+
+// stagePersonality is appended to every species' personality at each stage
+// unless its StageLook says otherwise. Adults get nothing; it's the default.
+var stagePersonality = map[string]string{
+	StageHatchling: "You're still a hatchling: small, wide-eyed, easily amazed by everything the Pi does, and a little clumsy with big words.",
+	StageJuvenile:  "You're a juvenile now: full of energy, a bit cocky, and eager to prove you can handle the Pi on your own.",
+	StageElder:     "You're an elder now: slower, unhurried, fond of remembering how things used to be, and quietly wise about the Pi's quirks.",
+}
+
+// AtStage returns a copy of the species as it looks at the given growth
+// stage ("" = hatchling).
+func (sp *Species) AtStage(stage string) *Species {
+	if stage == "" {
+		stage = StageHatchling
+	}
+	look := sp.Stages[stage]
+	grown := *sp
+	override(&grown.Emoji, look.Emoji)
+	override(&grown.Verbs.Happy, look.Verbs.Happy)
+	override(&grown.Verbs.Eat, look.Verbs.Eat)
+	override(&grown.Verbs.Sleep, look.Verbs.Sleep)
+	override(&grown.Verbs.Play, look.Verbs.Play)
+	override(&grown.Verbs.Greet, look.Verbs.Greet)
+	override(&grown.Verbs.Distress, look.Verbs.Distress)
+	if line := look.Personality; line != "" {
+		grown.Personality += " " + line
+	} else if line := stagePersonality[stage]; line != "" {
+		grown.Personality += " " + line
+	}
+	return &grown
+}
+
+// override sets *dst to v unless v is empty.
+func override(dst *string, v string) {
+	if v != "" {
+		*dst = v
+	}
+}